@@ -9,6 +9,7 @@ import (
 	"strings"
 	"text/template"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/gengo/generator"
 )
 
@@ -21,7 +22,7 @@ type admissionGenerator struct {
 }
 
 func CreateAdmissionGenerator(apis *APIs, filename string, projectRootPath string, outputBase string) generator.Generator {
-	admissionKinds := []string{}
+	admissionKinds := sets.NewString()
 	// filter out those resources created w/ `--admission-controller` flag
 	for _, group := range apis.Groups {
 		for _, version := range group.Versions {
@@ -29,7 +30,7 @@ func CreateAdmissionGenerator(apis *APIs, filename string, projectRootPath strin
 				resourceAdmissionControllerPkg := filepath.Join(outputBase, projectRootPath, "plugin", "admission", strings.ToLower(resource.Kind))
 				// if "<repo>/plugin/admission" package is present in the project, add it to the generated installation function
 				if _, err := os.Stat(resourceAdmissionControllerPkg); err == nil {
-					admissionKinds = append(admissionKinds, resource.Kind)
+					admissionKinds.Insert(resource.Kind)
 					klog.V(5).Infof("found existing admission controller for resource: %v/%v", resource.Group, resource.Kind)
 				}
 			}
@@ -39,7 +40,8 @@ func CreateAdmissionGenerator(apis *APIs, filename string, projectRootPath strin
 	return &admissionGenerator{
 		generator.DefaultGen{OptionalName: filename},
 		projectRootPath,
-		admissionKinds,
+		// sorted for the generated file not to change from one run to the next
+		admissionKinds.List(),
 	}
 }
 
@@ -72,8 +74,8 @@ func (d *admissionGenerator) Finalize(context *generator.Context, w io.Writer) e
 		return nil
 	}
 
-	temp := template.Must(template.New("admission-install-template").Parse(AdmissionsInstallTemplate))
-	return temp.Execute(w, &struct {
+	temp := template.Must(template.New("admission-install-template").Funcs(templateFuncs).Parse(AdmissionsInstallTemplate))
+	return executeTemplate(w, "admission", temp, &struct {
 		Admissions []string
 	}{
 		Admissions: d.admissionKinds,
@@ -0,0 +1,59 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestAdmissionKinds checks the kinds with an admission plugin are installed once each, sorted by name
+// whatever the order of the groups and versions, for the generated file not to change between runs
+func TestAdmissionKinds(t *testing.T) {
+	outputBase, err := ioutil.TempDir("", "admission")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outputBase)
+	for _, plugin := range []string{"shoggoth", "festival", "deepone"} {
+		if err := os.MkdirAll(filepath.Join(outputBase, "example.com/basic/plugin/admission", plugin), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	apis := &APIs{Groups: map[string]*APIGroup{
+		"kingsport": {Versions: map[string]*APIVersion{
+			"v1": {Resources: map[string]*APIResource{"Festival": {Kind: "Festival"}}},
+		}},
+		"innsmouth": {Versions: map[string]*APIVersion{
+			"v1":       {Resources: map[string]*APIResource{"Shoggoth": {Kind: "Shoggoth"}, "DeepOne": {Kind: "DeepOne"}}},
+			"v1alpha1": {Resources: map[string]*APIResource{"Shoggoth": {Kind: "Shoggoth"}}},
+		}},
+		"miskatonic": {Versions: map[string]*APIVersion{
+			"v1beta1": {Resources: map[string]*APIResource{"University": {Kind: "University"}}},
+		}},
+	}}
+	for i := 0; i < 10; i++ {
+		g := CreateAdmissionGenerator(apis, "zz_generated.api.register", "example.com/basic", outputBase).(*admissionGenerator)
+		if expected := []string{"DeepOne", "Festival", "Shoggoth"}; !reflect.DeepEqual(g.admissionKinds, expected) {
+			t.Fatalf("expected the admission kinds %v, got %v", expected, g.admissionKinds)
+		}
+	}
+}
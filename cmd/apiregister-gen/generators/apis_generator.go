@@ -27,15 +27,17 @@ import (
 
 type apiGenerator struct {
 	generator.DefaultGen
-	apis *APIs
+	apis              *APIs
+	openAPIPerVersion bool
 }
 
 var _ generator.Generator = &apiGenerator{}
 
-func CreateApisGenerator(apis *APIs, filename string) generator.Generator {
+func CreateApisGenerator(apis *APIs, filename string, openAPIPerVersion bool) generator.Generator {
 	return &apiGenerator{
 		generator.DefaultGen{OptionalName: filename},
 		apis,
+		openAPIPerVersion,
 	}
 }
 
@@ -43,6 +45,10 @@ func (d *apiGenerator) Imports(c *generator.Context) []string {
 	imports := []string{
 		"sigs.k8s.io/apiserver-builder-alpha/pkg/builders",
 		"k8s.io/apimachinery/pkg/runtime",
+		"k8s.io/apimachinery/pkg/runtime/schema",
+	}
+	if d.openAPIPerVersion {
+		imports = append(imports, "k8s.io/kube-openapi/pkg/common")
 	}
 	for _, group := range d.apis.Groups {
 		imports = append(imports, group.PkgPath)
@@ -53,21 +59,66 @@ func (d *apiGenerator) Imports(c *generator.Context) []string {
 	}
 	for _, group := range d.apis.Groups {
 		imports = append(imports, fmt.Sprintf(
-			"_ \"%s/install\" // Install the %s group", group.Pkg.Path, group.Group))
+			"%sinstall \"%s/install\" // Install the %s group", group.Group, group.Pkg.Path, group.Group))
 	}
 
 	return imports
 }
 
 func (d *apiGenerator) Finalize(context *generator.Context, w io.Writer) error {
-	temp := template.Must(template.New("apis-template").Parse(APIsTemplate))
-	err := temp.Execute(w, d.apis)
+	temp := template.Must(template.New("apis-template").Funcs(templateFuncs).Parse(APIsTemplate))
+	err := executeTemplate(w, "apis", temp, struct {
+		*APIs
+		OpenAPIPerVersion bool
+	}{d.apis, d.openAPIPerVersion})
 	if err != nil {
 		return err
 	}
 	return err
 }
 
+type registerAllGenerator struct {
+	generator.DefaultGen
+	apis *APIs
+}
+
+var _ generator.Generator = &registerAllGenerator{}
+
+// CreateRegisterAllGenerator returns the generator of the register file of the apis package, importing the
+// install package of every group
+func CreateRegisterAllGenerator(apis *APIs, filename string) generator.Generator {
+	return &registerAllGenerator{
+		generator.DefaultGen{OptionalName: filename},
+		apis,
+	}
+}
+
+func (d *registerAllGenerator) Imports(c *generator.Context) []string {
+	imports := []string{
+		"k8s.io/apimachinery/pkg/runtime",
+	}
+	for _, group := range d.apis.Groups {
+		imports = append(imports, fmt.Sprintf(
+			"%sinstall \"%s/install\" // Install the %s group", group.Group, group.Pkg.Path, group.Group))
+	}
+	return imports
+}
+
+func (d *registerAllGenerator) Finalize(context *generator.Context, w io.Writer) error {
+	temp := template.Must(template.New("register-all-template").Funcs(templateFuncs).Parse(RegisterAllTemplate))
+	return executeTemplate(w, "registerall", temp, d.apis)
+}
+
+var RegisterAllTemplate = `
+// InstallAll registers the internal and versioned types of all known api groups with the scheme.  Importing
+// the package installs them with the builders.Scheme through the install package of each group.
+func InstallAll(scheme *runtime.Scheme) {
+	{{ range $group := .Groups -}}
+	{{ $group.Group }}install.Install(scheme)
+	{{ end -}}
+}
+`
+
 var APIsTemplate = `
 var (
 	localSchemeBuilder = runtime.SchemeBuilder{
@@ -80,6 +131,13 @@ var (
 	AddToScheme = localSchemeBuilder.AddToScheme
 )
 
+// Install registers the internal and versioned types of all known api groups with the scheme
+func Install(scheme *runtime.Scheme) {
+	{{ range $group := .Groups -}}
+	{{ $group.Group }}install.Install(scheme)
+	{{ end -}}
+}
+
 // GetAllApiBuilders returns all known APIGroupBuilders
 // so they can be registered with the apiserver
 func GetAllApiBuilders() []*builders.APIGroupBuilder {
@@ -90,14 +148,46 @@ func GetAllApiBuilders() []*builders.APIGroupBuilder {
 	}
 }
 
+// GVKToType constructs the empty objects of the versioned and internal kinds of the resources of all known api
+// groups and of their lists, without looking their types up in the scheme
+var GVKToType = map[schema.GroupVersionKind]func() runtime.Object{
+	{{ range $group := .Groups -}}
+	{{ range $version := $group.Versions -}}
+	{{ range $res := served $version.Resources -}}
+	{{ $group.Group }}{{ $version.Version }}.SchemeGroupVersion.WithKind("{{ $res.Kind }}"): func() runtime.Object { return &{{ $group.Group }}{{ $version.Version }}.{{ $res.Kind }}{} },
+	{{ $group.Group }}{{ $version.Version }}.SchemeGroupVersion.WithKind("{{ $res.Kind }}List"): func() runtime.Object { return &{{ $group.Group }}{{ $version.Version }}.{{ $res.Kind }}List{} },
+	{{ end -}}
+	{{ end -}}
+	{{ range $res := untagged $group.UnversionedResources -}}
+	{{ $group.Group }}.SchemeGroupVersion.WithKind("{{ $res.Kind }}"): func() runtime.Object { return &{{ $group.Group }}.{{ $res.Kind }}{} },
+	{{ $group.Group }}.SchemeGroupVersion.WithKind("{{ $res.Kind }}List"): func() runtime.Object { return &{{ $group.Group }}.{{ $res.Kind }}List{} },
+	{{ end -}}
+	{{ end -}}
+}
+
+{{ if .OpenAPIPerVersion -}}
+// GetAllOpenAPIDefinitions returns the OpenAPI definitions generated for all api group versions,
+// merged into one map to serve
+func GetAllOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+	return builders.MergeOpenAPIDefinitions(ref,
+		{{ range $group := .Groups -}}
+		{{ range $version := $group.Versions -}}
+		{{ $group.Group }}{{ $version.Version }}.GetOpenAPIDefinitions,
+		{{ end -}}
+		{{ end -}}
+	)
+}
+
+{{ end -}}
+
 {{ range $group := .Groups -}}
 var {{ $group.Group }}ApiGroup = builders.NewApiGroupBuilder(
 	"{{ $group.Group }}.{{ $group.Domain }}",
 	"{{ $group.PkgPath}}").
 	WithUnVersionedApi({{ $group.Group }}.ApiVersion).
 	WithVersionedApis(
-		{{ range $version := $group.Versions -}}
-		{{ $group.Group }}{{ $version.Version }}.ApiVersion,
+		{{ range $version := $group.VersionPriority -}}
+		{{ $group.Group }}{{ $version }}.ApiVersion,
 		{{ end -}}
 	).
 	WithRootScopedKinds(
@@ -108,7 +198,14 @@ var {{ $group.Group }}ApiGroup = builders.NewApiGroupBuilder(
 		{{ end -}}
 		{{ end -}}
 		{{ end -}}
-	)
+	).
+	WithResourceOptions({{ $group.Group }}.NewResourceOptions()).
+	WithStorageMediaTypes({{ $group.Group }}.StorageMediaTypes).
+	WithAdmissionPlugins({{ $group.Group }}.AdmissionPlugins).
+	WithHandlers({{ $group.Group }}.Handlers).
+	WithOpenAPIExamples({{ $group.Group }}.OpenAPIExamples).
+	WithOpenAPIUIDescriptors({{ $group.Group }}.OpenAPIUIDescriptors){{ if $group.PreviousGroupName }}.
+	WithPreviousName("{{ $group.PreviousGroupName }}"){{ end }}
 
 func Get{{ $group.GroupTitle }}APIBuilder() *builders.APIGroupBuilder {
 	return {{ $group.Group }}ApiGroup
@@ -0,0 +1,239 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"text/template"
+
+	"k8s.io/gengo/args"
+	"k8s.io/gengo/generator"
+)
+
+// taggedResourceGenerator generates the file serving a resource with a build tag.  The file holds the code
+// otherwise generated for the resource by the generator it wraps, which provides its imports, executing the
+// template with data, the resource or, in the apis package, its versions.
+type taggedResourceGenerator struct {
+	generator.Generator
+	data              interface{}
+	template          string
+	resourceTemplates string
+}
+
+func (d *taggedResourceGenerator) Finalize(context *generator.Context, w io.Writer) error {
+	temp := template.Must(template.New("tagged-resource-template").Funcs(templateFuncs).Parse(d.template))
+	template.Must(temp.Parse(d.resourceTemplates))
+	return temp.Execute(w, d.data)
+}
+
+// taggedAPIsResource is the data of the TaggedAPIsTemplate, a resource with a build tag and the resources of
+// its versions
+type taggedAPIsResource struct {
+	*APIResource
+	Versions []*APIResource
+}
+
+// taggedAPIsGenerator provides the imports of the file of the apis package of a resource with a build tag, the
+// packages of the group and of the versions of the resource
+type taggedAPIsGenerator struct {
+	generator.DefaultGen
+	apigroup *APIGroup
+	resource *taggedAPIsResource
+}
+
+func (d *taggedAPIsGenerator) Imports(c *generator.Context) []string {
+	imports := []string{
+		"k8s.io/apimachinery/pkg/runtime",
+		d.apigroup.PkgPath,
+	}
+	for _, version := range d.resource.Versions {
+		imports = append(imports, fmt.Sprintf(
+			"%s%s \"%s\"", d.apigroup.Group, version.Version, d.apigroup.Versions[version.Version].Pkg.Path))
+	}
+	return imports
+}
+
+// taggedFilename returns the name of the file serving the resource with a build tag, e.g.
+// zz_generated.api.register.foo for the Foo resource
+func taggedFilename(filename string, resource *APIResource) string {
+	return filename + "." + strings.ToLower(resource.Kind)
+}
+
+// CreateTaggedPackages returns the packages of the files serving the resources of apigroup with a build tag,
+// one file for each resource in the group package, its version packages and, with emitTestClients, their
+// testclient packages.  The files are constrained by the build tag of the resource, so building without
+// the tag leaves the resource out of the apiserver.  Only the files of the kinds of generators for which
+// generates returns true are returned.
+func CreateTaggedPackages(apigroup *APIGroup, arguments *args.GeneratorArgs, boilerplate []byte, extension string,
+	emitTestClients bool, generates func(kind string) bool) generator.Packages {
+	packages := generator.Packages{}
+	filename := arguments.OutputFileBaseName
+	for _, resource := range apigroup.UnversionedResources {
+		if len(resource.BuildTag) == 0 || !generates("unversioned") {
+			continue
+		}
+		factory := &packageFactory{apigroup.Pkg.Path, arguments, buildConstraint(resource.BuildTag, boilerplate), extension}
+		packages = append(packages, factory.createPackage(&taggedResourceGenerator{
+			CreateUnversionedGenerator(apigroup, taggedFilename(filename, resource), false),
+			resource,
+			TaggedUnversionedAPITemplate,
+			UnversionedResourceTemplates,
+		}))
+	}
+	for _, apiversion := range apigroup.Versions {
+		for _, resource := range apiversion.Resources {
+			if len(resource.BuildTag) == 0 {
+				continue
+			}
+			header := buildConstraint(resource.BuildTag, boilerplate)
+			if generates("versioned") {
+				factory := &packageFactory{apiversion.Pkg.Path, arguments, header, extension}
+				packages = append(packages, factory.createPackage(&taggedResourceGenerator{
+					CreateVersionedGenerator(apiversion, apigroup, taggedFilename(filename, resource)),
+					resource,
+					TaggedVersionedAPITemplate,
+					VersionedResourceTemplates,
+				}))
+			}
+			if generates("testclient") && emitTestClients && len(resource.REST) == 0 {
+				factory := &packageFactory{path.Join(apiversion.Pkg.Path, "testclient"), arguments, header, extension}
+				packages = append(packages, factory.createPackage(&taggedResourceGenerator{
+					CreateTestClientGenerator(apiversion, apigroup, taggedFilename(filename, resource)),
+					resource,
+					TaggedTestClientTemplate,
+					TestClientResourceTemplates,
+				}))
+			}
+		}
+	}
+	return packages
+}
+
+// CreateTaggedAPIsPackages returns the packages of the files of the apis package adding the constructors of the
+// objects of the resources of apis with a build tag to GVKToType, one file for each resource constrained by its
+// build tag
+func CreateTaggedAPIsPackages(apis *APIs, arguments *args.GeneratorArgs, boilerplate []byte,
+	extension string) generator.Packages {
+	packages := generator.Packages{}
+	filename := arguments.OutputFileBaseName
+	for _, apigroup := range apis.Groups {
+		for kind, resource := range apigroup.UnversionedResources {
+			if len(resource.BuildTag) == 0 {
+				continue
+			}
+			data := &taggedAPIsResource{APIResource: resource}
+			for _, version := range apigroup.VersionPriority {
+				if versioned, found := apigroup.Versions[version].Resources[kind]; found {
+					data.Versions = append(data.Versions, versioned)
+				}
+			}
+			factory := &packageFactory{apis.Pkg.Path, arguments, buildConstraint(resource.BuildTag, boilerplate), extension}
+			packages = append(packages, factory.createPackage(&taggedResourceGenerator{
+				&taggedAPIsGenerator{generator.DefaultGen{OptionalName: taggedFilename(filename, resource)}, apigroup, data},
+				data,
+				TaggedAPIsTemplate,
+				"",
+			}))
+		}
+	}
+	return packages
+}
+
+// buildConstraint returns the header of a file only built with tag, the constraint preceding the boilerplate
+func buildConstraint(tag string, boilerplate []byte) []byte {
+	constraint := "//go:build " + tag + "\n// +build " + tag + "\n\n"
+	return append([]byte(constraint), boilerplate...)
+}
+
+var TaggedUnversionedAPITemplate = `
+var (
+	{{ template "unversioned-resource-storage" . }}
+)
+
+func init() {
+	ApiVersion.WithKinds(
+		Internal{{ .Kind }},
+		Internal{{ .Kind }}Status,
+		{{ range $subresource := .Subresources -}}
+		Internal{{ $subresource.Kind }}REST,
+		{{ end -}}
+	)
+	{{ if .StorageMediaType -}}
+	StorageMediaTypes[Resource("{{ .Resource }}")] = "{{ .StorageMediaType }}"
+	{{ end -}}
+	{{ if .OrphanDependents -}}
+	DefaultGCPolicy[Resource("{{ .Resource }}")] = metav1.DeletePropagationOrphan
+	{{ end -}}
+	{{ range $func := .ValidatingAdmission -}}
+	AdmissionPlugins["{{ public $.Group }}{{ $func }}"] = builders.NewValidatingAdmissionPlugin(Resource("{{ $.Resource }}"),
+		func(ctx context.Context, obj runtime.Object, a admission.Attributes) error {
+			o, ok := obj.(*{{ $.Kind }})
+			if !ok {
+				return fmt.Errorf("expected *{{ $.Kind }}, got %T", obj)
+			}
+			return {{ $func }}(ctx, o, a)
+		})
+	{{ end -}}
+	{{ if .MetricsLabels -}}
+	legacyregistry.CustomMustRegister({{ .Kind }}MetricsCollector)
+	{{ end -}}
+	{{ if .CustomMetrics -}}
+	builders.AddCustomMetrics({{ .Kind }}CustomMetrics...)
+	{{ end -}}
+}
+
+{{ template "unversioned-resource-serving" . }}
+`
+
+var TaggedVersionedAPITemplate = `
+var (
+	{{ template "versioned-resource-storage" . }}
+)
+
+func init() {
+	ApiVersion.WithResources(
+		{{ .Group }}.{{ .Group|public }}{{ .Kind }}Storage,
+		{{ if not .REST -}}
+		{{ .Kind }}StatusStorage,
+		{{ end -}}
+		{{ range $subresource := .Subresources -}}
+		{{ $.Kind }}{{ $subresource.Path|public }}Storage,
+		{{ end -}}
+	)
+}
+{{ if .RuntimeWebhook }}
+{{ template "versioned-resource-webhook" . }}
+{{ end -}}
+`
+
+var TaggedAPIsTemplate = `
+func init() {
+	{{ range $res := .Versions -}}
+	GVKToType[{{ $res.Group }}{{ $res.Version }}.SchemeGroupVersion.WithKind("{{ $res.Kind }}")] = func() runtime.Object { return &{{ $res.Group }}{{ $res.Version }}.{{ $res.Kind }}{} }
+	GVKToType[{{ $res.Group }}{{ $res.Version }}.SchemeGroupVersion.WithKind("{{ $res.Kind }}List")] = func() runtime.Object { return &{{ $res.Group }}{{ $res.Version }}.{{ $res.Kind }}List{} }
+	{{ end -}}
+	GVKToType[{{ .Group }}.SchemeGroupVersion.WithKind("{{ .Kind }}")] = func() runtime.Object { return &{{ .Group }}.{{ .Kind }}{} }
+	GVKToType[{{ .Group }}.SchemeGroupVersion.WithKind("{{ .Kind }}List")] = func() runtime.Object { return &{{ .Group }}.{{ .Kind }}List{} }
+}
+`
+
+var TaggedTestClientTemplate = `
+{{ template "testclient-resource" . }}
+`
@@ -0,0 +1,154 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"go/ast"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/namer"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+type builderGenerator struct {
+	generator.DefaultGen
+	apiversion *APIVersion
+	imports    namer.ImportTracker
+}
+
+var _ generator.Generator = &builderGenerator{}
+
+// CreateBuilderGenerator generates a fluent builder of each resource of apiversion, setting the name, the
+// namespace and the top-level spec fields of the built object, so tests construct their objects concisely
+func CreateBuilderGenerator(apiversion *APIVersion, filename string) generator.Generator {
+	return &builderGenerator{
+		generator.DefaultGen{OptionalName: filename + ".builder"},
+		apiversion,
+		generator.NewImportTracker(),
+	}
+}
+
+// Imports returns the imports of the types of the spec fields, which are tracked while the template names them
+func (d *builderGenerator) Imports(c *generator.Context) []string {
+	return d.imports.ImportLines()
+}
+
+// ResourceBuilder is the builder generated for a resource
+type ResourceBuilder struct {
+	*APIResource
+	// SpecFields are the top-level fields of the spec of the resource set by the builder
+	SpecFields []*BuilderField
+}
+
+// BuilderField is a top-level spec field set by a With<Name> method of a builder
+type BuilderField struct {
+	// Name is the name of the field
+	Name string
+	// Type is the Go type of the field in the version package
+	Type string
+}
+
+func (d *builderGenerator) Finalize(context *generator.Context, w io.Writer) error {
+	raw := namer.NewRawNamer(d.apiversion.Pkg.Path, d.imports)
+	builders := []*ResourceBuilder{}
+	for _, name := range sets.StringKeySet(d.apiversion.Resources).List() {
+		r := d.apiversion.Resources[name]
+		builders = append(builders, &ResourceBuilder{APIResource: r, SpecFields: builderFields(r, raw)})
+	}
+
+	temp := template.Must(template.New("builder-template").Funcs(templateFuncs).Parse(BuilderTemplate))
+	return executeTemplate(w, "builder", temp, builders)
+}
+
+// builderFields returns the exported top-level fields of the spec of the resource r, leaving out the inlined
+// structs and the fields whose With<Name> method would be the WithName or WithNamespace method of the builder
+func builderFields(r *APIResource, raw namer.Namer) []*BuilderField {
+	fields := []*BuilderField{}
+	var spec *types.Type
+	for _, m := range r.Type.Members {
+		if m.Name == "Spec" {
+			spec = m.Type
+		}
+	}
+	if spec == nil || spec.Kind != types.Struct {
+		return fields
+	}
+	for _, m := range spec.Members {
+		tag := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		if m.Embedded || !ast.IsExported(m.Name) || tag == "-" {
+			continue
+		}
+		if m.Name == "Name" || m.Name == "Namespace" {
+			klog.Warningf("%v.%s is not set by the builder of %s, whose With%s method sets the metadata",
+				spec.Name, m.Name, r.Kind, m.Name)
+			continue
+		}
+		fields = append(fields, &BuilderField{Name: m.Name, Type: raw.Name(m.Type)})
+	}
+	return fields
+}
+
+var BuilderTemplate = `
+{{ range $api := . -}}
+// {{ $api.Kind }}Builder builds a {{ $api.Kind }} for tests, e.g. New{{ $api.Kind }}Builder().WithName("foo").Build()
+// +k8s:deepcopy-gen=false
+type {{ $api.Kind }}Builder struct {
+	obj *{{ $api.Kind }}
+}
+
+// New{{ $api.Kind }}Builder returns a builder of a {{ $api.Kind }} whose apiVersion and kind are set
+func New{{ $api.Kind }}Builder() *{{ $api.Kind }}Builder {
+	obj := &{{ $api.Kind }}{}
+	obj.SetGroupVersionKind(SchemeGroupVersion.WithKind("{{ $api.Kind }}"))
+	return &{{ $api.Kind }}Builder{obj: obj}
+}
+
+// WithName sets the name of the {{ $api.Kind }}
+func (b *{{ $api.Kind }}Builder) WithName(name string) *{{ $api.Kind }}Builder {
+	b.obj.Name = name
+	return b
+}
+
+{{ if not $api.NonNamespaced -}}
+// WithNamespace sets the namespace of the {{ $api.Kind }}
+func (b *{{ $api.Kind }}Builder) WithNamespace(namespace string) *{{ $api.Kind }}Builder {
+	b.obj.Namespace = namespace
+	return b
+}
+
+{{ end -}}
+{{ range $field := $api.SpecFields -}}
+// With{{ $field.Name }} sets the {{ $field.Name }} field of the spec of the {{ $api.Kind }}
+func (b *{{ $api.Kind }}Builder) With{{ $field.Name }}(value {{ $field.Type }}) *{{ $api.Kind }}Builder {
+	b.obj.Spec.{{ $field.Name }} = value
+	return b
+}
+
+{{ end -}}
+// Build returns a copy of the {{ $api.Kind }} built so far, so the builder may go on building others
+func (b *{{ $api.Kind }}Builder) Build() *{{ $api.Kind }} {
+	return b.obj.DeepCopy()
+}
+
+{{ end -}}
+`
@@ -0,0 +1,162 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+)
+
+// XValidation are the CEL rules declared by the types of a version of a resource, evaluated against the
+// objects of the resource converted to the version
+type XValidation struct {
+	Version string
+	Rules   []*XValidationRule
+}
+
+// XValidationRule is the CEL rule of a "+kubebuilder:validation:XValidation" comment
+type XValidationRule struct {
+	// Path is the json path of the field validated as self, empty for the object - e.g. spec
+	Path string
+	// Rule is the CEL expression - e.g. self.minStudents <= self.maxStudents
+	Rule string
+	// Message is the optional detail of the error of the objects failing the rule
+	Message string
+}
+
+// XValidationRules returns the "+kubebuilder:validation:XValidation" rules of the resource type t, of its
+// fields and of the structs declared in its package reached through struct and pointer fields.  The rules
+// are compiled, failing the generation on invalid rules.
+func XValidationRules(t *types.Type) []*XValidationRule {
+	rules := []*XValidationRule{}
+	findXValidationRules(t, t.Name.Package, "", sets.NewString(), &rules)
+	return rules
+}
+
+func findXValidationRules(t *types.Type, pkg, path string, visited sets.String, rules *[]*XValidationRule) {
+	for t.Kind == types.Pointer || t.Kind == types.Alias {
+		if t.Kind == types.Pointer {
+			t = t.Elem
+		} else {
+			t = t.Underlying
+		}
+	}
+	if t.Kind != types.Struct || t.Name.Package != pkg || visited.Has(t.Name.Name) {
+		return
+	}
+	visited.Insert(t.Name.Name)
+	defer visited.Delete(t.Name.Name)
+
+	*rules = append(*rules, parseXValidationTags(t.Name.String(), t.CommentLines, path)...)
+	for _, m := range t.Members {
+		name := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		switch {
+		case name == "-":
+			continue
+		case len(name) == 0 && m.Embedded:
+			findXValidationRules(m.Type, pkg, path, visited, rules)
+			continue
+		case len(name) == 0:
+			name = m.Name
+		}
+		fieldPath := name
+		if len(path) > 0 {
+			fieldPath = path + "." + name
+		}
+		*rules = append(*rules, parseXValidationTags(t.Name.String()+"."+m.Name, m.CommentLines, fieldPath)...)
+		findXValidationRules(m.Type, pkg, fieldPath, visited, rules)
+	}
+}
+
+// parseXValidationTags parses and compiles the "+kubebuilder:validation:XValidation" comments of the type
+// or field named name validating the field at path
+func parseXValidationTags(name string, comments []string, path string) []*XValidationRule {
+	rules := []*XValidationRule{}
+	for _, tag := range Comments(comments).GetTags("kubebuilder:validation:XValidation", ":") {
+		rule, err := ParseXValidationTag(tag)
+		if err != nil {
+			klog.Fatalf("// +kubebuilder:validation:XValidation of %s is invalid: %v.  Got string: [%s]", name, err, tag)
+		}
+		if _, err := builders.CompileCELRule(rule.Rule); err != nil {
+			klog.Fatalf("// +kubebuilder:validation:XValidation rule of %s does not compile: %v", name, err)
+		}
+		rule.Path = path
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ParseXValidationTag parses the comma separated quoted rule and message of a
+// "+kubebuilder:validation:XValidation:rule=<rule>,message=<message>" comment
+func ParseXValidationTag(tag string) (*XValidationRule, error) {
+	rule := &XValidationRule{}
+	for rest := strings.TrimSpace(tag); len(rest) > 0; {
+		i := strings.Index(rest, "=")
+		if i < 0 {
+			return nil, errors.Errorf("expected key=\"value\" pairs")
+		}
+		key := strings.TrimSpace(rest[:i])
+		rest = strings.TrimSpace(rest[i+1:])
+		value, remaining, err := unquotePrefix(rest)
+		if err != nil {
+			return nil, errors.Errorf("the value of %s must be a quoted string: %v", key, err)
+		}
+		switch key {
+		case "rule":
+			rule.Rule = value
+		case "message":
+			rule.Message = value
+		default:
+			return nil, errors.Errorf("unknown key %q, expected rule or message", key)
+		}
+		rest = strings.TrimSpace(remaining)
+		if len(rest) > 0 {
+			if rest[0] != ',' {
+				return nil, errors.Errorf("expected a comma after the value of %s", key)
+			}
+			rest = strings.TrimSpace(rest[1:])
+		}
+	}
+	if len(rule.Rule) == 0 {
+		return nil, errors.Errorf("rule is required")
+	}
+	return rule, nil
+}
+
+// unquotePrefix unquotes the double quoted string s starts with and returns the rest of s
+func unquotePrefix(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", errors.Errorf("missing opening quote")
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			return value, s[i+1:], err
+		}
+	}
+	return "", "", errors.Errorf("missing closing quote")
+}
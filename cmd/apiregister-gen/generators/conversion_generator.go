@@ -0,0 +1,315 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"io"
+	"text/template"
+
+	"k8s.io/gengo/generator"
+)
+
+type conversionGenerator struct {
+	generator.DefaultGen
+	apiversion *APIVersion
+	apigroup   *APIGroup
+}
+
+var _ generator.Generator = &conversionGenerator{}
+
+// CreateConversionGenerator generates the conversions of apiversion registered with the scheme in addition to
+// the conversion functions generated by conversion-gen, in a file of its own so the conversions may be
+// regenerated without the rest of the version package
+func CreateConversionGenerator(apiversion *APIVersion, apigroup *APIGroup, filename string) generator.Generator {
+	return &conversionGenerator{
+		generator.DefaultGen{OptionalName: filename + ".conversion"},
+		apiversion,
+		apigroup,
+	}
+}
+
+func (d *conversionGenerator) Imports(c *generator.Context) []string {
+	imports := []string{
+		"k8s.io/apimachinery/pkg/conversion",
+		"k8s.io/apimachinery/pkg/runtime",
+		"sigs.k8s.io/apiserver-builder-alpha/pkg/builders",
+		d.apigroup.Pkg.Path,
+	}
+	for _, r := range d.apiversion.Resources {
+		if len(r.EnumFields) > 0 {
+			imports = append(imports, "k8s.io/apimachinery/pkg/util/validation/field")
+			break
+		}
+	}
+	for _, m := range d.apiversion.MapConversions {
+		if len(m.Stub) > 0 {
+			// The stubs of the conversions of the maps fail with an error
+			return append(imports, "fmt")
+		}
+	}
+	return imports
+}
+
+func (d *conversionGenerator) Finalize(context *generator.Context, w io.Writer) error {
+	temp := template.Must(template.New("conversion-template").Funcs(templateFuncs).Parse(ConversionTemplate))
+	return executeTemplate(w, "conversion", temp, d.apiversion)
+}
+
+var ConversionTemplate = `
+{{ range $api := .Resources -}}
+{{ range $field := $api.InterfaceFields -}}
+// TODO: {{ $field }}, which the generated conversions share
+// between the converted objects.  Convert the field in a conversion function of the enclosing type.
+
+{{ end -}}
+{{ end -}}
+{{ range $c := .FieldConversions -}}
+{{ range $field := $c.Incompatible -}}
+// TODO: {{ $field }}.  Convert the field in the conversion
+// functions of {{ $c.Type }}, which are not generated.
+
+{{ end -}}
+{{ if $c.ToUnversioned -}}
+// Convert_{{ $.Version }}_{{ $c.Type }}_To_{{ $.Group }}_{{ $c.Type }} converts a {{ $c.Type }} to the unversioned {{ $c.Type }},
+// zeroing the fields {{ $.Version }} lacks: {{ join $c.Added ", " }}
+func Convert_{{ $.Version }}_{{ $c.Type }}_To_{{ $.Group }}_{{ $c.Type }}(in *{{ $c.Type }}, out *{{ $.Group }}.{{ $c.Type }}, s conversion.Scope) error {
+	*out = {{ $.Group }}.{{ $c.Type }}{}
+	return autoConvert_{{ $.Version }}_{{ $c.Type }}_To_{{ $.Group }}_{{ $c.Type }}(in, out, s)
+}
+
+{{ end -}}
+{{ if $c.FromUnversioned -}}
+// Convert_{{ $.Group }}_{{ $c.Type }}_To_{{ $.Version }}_{{ $c.Type }} converts an unversioned {{ $c.Type }} to {{ $.Version }},
+// dropping the fields {{ $.Version }} lacks: {{ join $c.Added ", " }}
+func Convert_{{ $.Group }}_{{ $c.Type }}_To_{{ $.Version }}_{{ $c.Type }}(in *{{ $.Group }}.{{ $c.Type }}, out *{{ $c.Type }}, s conversion.Scope) error {
+	return autoConvert_{{ $.Group }}_{{ $c.Type }}_To_{{ $.Version }}_{{ $c.Type }}(in, out, s)
+}
+
+{{ end -}}
+{{ end -}}
+{{ range $elem := .PointerSliceElems -}}
+// Convert_Pointer_{{ $.Version }}_{{ $elem }}_To_Pointer_{{ $.Group }}_{{ $elem }} converts an element of a []*{{ $elem }}
+// through the scope, allocating the converted element.  Nil elements are converted to nil.
+func Convert_Pointer_{{ $.Version }}_{{ $elem }}_To_Pointer_{{ $.Group }}_{{ $elem }}(in **{{ $elem }}, out **{{ $.Group }}.{{ $elem }}, s conversion.Scope) error {
+	if *in == nil {
+		*out = nil
+		return nil
+	}
+	*out = new({{ $.Group }}.{{ $elem }})
+	return Convert_{{ $.Version }}_{{ $elem }}_To_{{ $.Group }}_{{ $elem }}(*in, *out, s)
+}
+
+// Convert_Pointer_{{ $.Group }}_{{ $elem }}_To_Pointer_{{ $.Version }}_{{ $elem }} converts an element of a []*{{ $.Group }}.{{ $elem }}
+// through the scope, allocating the converted element.  Nil elements are converted to nil.
+func Convert_Pointer_{{ $.Group }}_{{ $elem }}_To_Pointer_{{ $.Version }}_{{ $elem }}(in **{{ $.Group }}.{{ $elem }}, out **{{ $elem }}, s conversion.Scope) error {
+	if *in == nil {
+		*out = nil
+		return nil
+	}
+	*out = new({{ $elem }})
+	return Convert_{{ $.Group }}_{{ $elem }}_To_{{ $.Version }}_{{ $elem }}(*in, *out, s)
+}
+
+{{ end -}}
+{{ range $m := .MapConversions -}}
+{{ if $m.Stub -}}
+// Convert_{{ $m.VersionedName }}_To_{{ $m.UnversionedName }} fails, the elements of the map cannot be converted.
+// {{ $m.Stub }}.
+func Convert_{{ $m.VersionedName }}_To_{{ $m.UnversionedName }}(in *{{ $m.Versioned }}, out *{{ $m.Unversioned }}, s conversion.Scope) error {
+	return fmt.Errorf({{ printf "%q" $m.Stub }})
+}
+
+// Convert_{{ $m.UnversionedName }}_To_{{ $m.VersionedName }} fails, the elements of the map cannot be converted.
+// {{ $m.Stub }}.
+func Convert_{{ $m.UnversionedName }}_To_{{ $m.VersionedName }}(in *{{ $m.Unversioned }}, out *{{ $m.Versioned }}, s conversion.Scope) error {
+	return fmt.Errorf({{ printf "%q" $m.Stub }})
+}
+
+{{ else -}}
+// Convert_{{ $m.VersionedName }}_To_{{ $m.UnversionedName }} converts each entry of a {{ $m.Versioned }} through
+// {{ $m.ToUnversioned }}, allocating the converted map.  Nil maps are converted to nil.
+func Convert_{{ $m.VersionedName }}_To_{{ $m.UnversionedName }}(in *{{ $m.Versioned }}, out *{{ $m.Unversioned }}, s conversion.Scope) error {
+	if *in == nil {
+		*out = nil
+		return nil
+	}
+	*out = make({{ $m.Unversioned }}, len(*in))
+	for key, val := range *in {
+		newVal := new({{ $m.UnversionedElem }})
+		if err := {{ $m.ToUnversioned }}(&val, newVal, s); err != nil {
+			return err
+		}
+		(*out)[{{ $m.UnversionedKey }}(key)] = *newVal
+	}
+	return nil
+}
+
+// Convert_{{ $m.UnversionedName }}_To_{{ $m.VersionedName }} converts each entry of a {{ $m.Unversioned }} through
+// {{ $m.FromUnversioned }}, allocating the converted map.  Nil maps are converted to nil.
+func Convert_{{ $m.UnversionedName }}_To_{{ $m.VersionedName }}(in *{{ $m.Unversioned }}, out *{{ $m.Versioned }}, s conversion.Scope) error {
+	if *in == nil {
+		*out = nil
+		return nil
+	}
+	*out = make({{ $m.Versioned }}, len(*in))
+	for key, val := range *in {
+		newVal := new({{ $m.VersionedElem }})
+		if err := {{ $m.FromUnversioned }}(&val, newVal, s); err != nil {
+			return err
+		}
+		(*out)[{{ $m.VersionedKey }}(key)] = *newVal
+	}
+	return nil
+}
+
+{{ end -}}
+{{ end -}}
+// RegisterCustomConversions registers the conversions wrapping the generated conversion functions, which pass
+// the fields they do not map to the builders.ConversionWebhook, migrate the annotations, decode and encode
+// the objects of the +embeddedObject fields and trace the conversions while builders.ConversionTrace is
+// enabled.  The conversions of the lists convert each of their
+// items with the conversions registered with the scheme, the conversions generated for the lists would
+// convert the items without these wrappers.
+func RegisterCustomConversions(scheme *runtime.Scheme) error {
+{{ range $api := .Resources -}}
+	if err := scheme.AddConversionFunc((*{{ $api.Kind }})(nil), (*{{ $api.Group }}.{{ $api.Kind }})(nil), func(a, b interface{}, scope conversion.Scope) error {
+		in, out := a.(*{{ $api.Kind }}), b.(*{{ $api.Group }}.{{ $api.Kind }})
+{{ if $api.ConversionWebhookFallback -}}
+		err := builders.ConvertWithWebhookFallback(in, out,
+			func(in, out runtime.Object) error {
+				return Convert_{{ $.Version }}_{{ $api.Kind }}_To_{{ $api.Group }}_{{ $api.Kind }}(in.(*{{ $api.Kind }}), out.(*{{ $api.Group }}.{{ $api.Kind }}), scope)
+			},
+			func(in, out runtime.Object) error {
+				return Convert_{{ $api.Group }}_{{ $api.Kind }}_To_{{ $.Version }}_{{ $api.Kind }}(in.(*{{ $api.Group }}.{{ $api.Kind }}), out.(*{{ $api.Kind }}), scope)
+			})
+{{ else -}}
+		err := Convert_{{ $.Version }}_{{ $api.Kind }}_To_{{ $api.Group }}_{{ $api.Kind }}(in, out, scope)
+{{ end -}}
+		if err != nil {
+			return err
+		}
+		if builders.ConversionTrace {
+			builders.TraceConversion(in, out, func(in, out runtime.Object) error {
+				return Convert_{{ $api.Group }}_{{ $api.Kind }}_To_{{ $.Version }}_{{ $api.Kind }}(in.(*{{ $api.Group }}.{{ $api.Kind }}), out.(*{{ $api.Kind }}), scope)
+			})
+		}
+{{ if $api.AnnotationConversion -}}
+		out.Annotations = builders.ConvertAnnotations(out.Annotations, true, {{ $api.AnnotationConversion }})
+{{ end -}}
+{{ if $api.EmbeddedObjectFields -}}
+		return decode{{ $api.Kind }}EmbeddedObjects(scheme, out)
+{{ else -}}
+		return nil
+{{ end -}}
+	}); err != nil {
+		return err
+	}
+{{ if or $api.AnnotationConversion $api.EmbeddedObjectFields -}}
+	if err := scheme.AddConversionFunc((*{{ $api.Group }}.{{ $api.Kind }})(nil), (*{{ $api.Kind }})(nil), func(a, b interface{}, scope conversion.Scope) error {
+		in, out := a.(*{{ $api.Group }}.{{ $api.Kind }}), b.(*{{ $api.Kind }})
+		if err := Convert_{{ $api.Group }}_{{ $api.Kind }}_To_{{ $.Version }}_{{ $api.Kind }}(in, out, scope); err != nil {
+			return err
+		}
+{{ if $api.AnnotationConversion -}}
+		out.Annotations = builders.ConvertAnnotations(out.Annotations, false, {{ $api.AnnotationConversion }})
+{{ end -}}
+{{ if $api.EmbeddedObjectFields -}}
+		return encode{{ $api.Kind }}EmbeddedObjects(scheme, out)
+{{ else -}}
+		return nil
+{{ end -}}
+	}); err != nil {
+		return err
+	}
+{{ end -}}
+	if err := scheme.AddConversionFunc((*{{ $api.Kind }}List)(nil), (*{{ $api.Group }}.{{ $api.Kind }}List)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		in, out := a.(*{{ $api.Kind }}List), b.(*{{ $api.Group }}.{{ $api.Kind }}List)
+		out.ListMeta = in.ListMeta
+		if in.Items == nil {
+			out.Items = nil
+			return nil
+		}
+		out.Items = make([]{{ $api.Group }}.{{ $api.Kind }}, len(in.Items))
+		for i := range in.Items {
+			if err := scope.Convert(&in.Items[i], &out.Items[i], scope.Flags()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := scheme.AddConversionFunc((*{{ $api.Group }}.{{ $api.Kind }}List)(nil), (*{{ $api.Kind }}List)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		in, out := a.(*{{ $api.Group }}.{{ $api.Kind }}List), b.(*{{ $api.Kind }}List)
+		out.ListMeta = in.ListMeta
+		if in.Items == nil {
+			out.Items = nil
+			return nil
+		}
+		out.Items = make([]{{ $api.Kind }}, len(in.Items))
+		for i := range in.Items {
+			if err := scope.Convert(&in.Items[i], &out.Items[i], scope.Flags()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+{{ end -}}
+	return nil
+}
+
+{{ range $api := .Resources -}}
+{{ if $api.EnumFields -}}
+// ValidateEnums checks the fields of o of +enum types hold one of the constants of their type, called by the
+// builders.EnumValidationStorageStrategy of {{ $api.Resource }} for the created and updated objects
+func (o *{{ $api.Kind }}) ValidateEnums() field.ErrorList {
+	return builders.ValidateEnums(
+		{{ range $field := $api.EnumFields -}}
+		builders.EnumValue{Path: "{{ $field.Path }}", Value: string({{ $field.Expr }}), Values: []string{
+			{{- range $i, $v := $field.Values }}{{ if $i }}, {{ end }}string({{ $v }}){{ end -}}
+		}},
+		{{ end -}}
+	)
+}
+
+{{ end -}}
+{{ if $api.EmbeddedObjectFields -}}
+// decode{{ $api.Kind }}EmbeddedObjects decodes the objects of the +embeddedObject fields of o
+func decode{{ $api.Kind }}EmbeddedObjects(scheme *runtime.Scheme, o *{{ $api.Group }}.{{ $api.Kind }}) error {
+	{{ range $field := $api.EmbeddedObjectFields -}}
+	if err := builders.DecodeRawExtension(scheme, "{{ $field.Path }}", &{{ $field.Expr }}); err != nil {
+		return err
+	}
+	{{ end -}}
+	return nil
+}
+
+// encode{{ $api.Kind }}EmbeddedObjects encodes the objects of the +embeddedObject fields of o
+func encode{{ $api.Kind }}EmbeddedObjects(scheme *runtime.Scheme, o *{{ $api.Kind }}) error {
+	{{ range $field := $api.EmbeddedObjectFields -}}
+	if err := builders.EncodeRawExtension(scheme, "{{ $field.Path }}", &{{ $field.Expr }}); err != nil {
+		return err
+	}
+	{{ end -}}
+	return nil
+}
+
+{{ end -}}
+{{ end -}}
+`
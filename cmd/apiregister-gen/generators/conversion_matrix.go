@@ -0,0 +1,111 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"io"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/generator"
+)
+
+// conversionPair is an ordered pair of the versions declaring a resource
+type conversionPair struct {
+	Kind, From, To string
+}
+
+type conversionMatrixGenerator struct {
+	generator.DefaultGen
+	apigroup *APIGroup
+}
+
+var _ generator.Generator = &conversionMatrixGenerator{}
+
+// CreateConversionMatrixGenerator returns a generator of the test of the install package of apigroup checking
+// each resource of the group converts between every pair of its versions
+func CreateConversionMatrixGenerator(apigroup *APIGroup, filename string) generator.Generator {
+	return &conversionMatrixGenerator{
+		generator.DefaultGen{OptionalName: filename},
+		apigroup,
+	}
+}
+
+func (d *conversionMatrixGenerator) Imports(c *generator.Context) []string {
+	return []string{
+		"testing",
+		"k8s.io/apimachinery/pkg/runtime",
+		"sigs.k8s.io/apiserver-builder-alpha/pkg/builders",
+	}
+}
+
+func (d *conversionMatrixGenerator) Finalize(context *generator.Context, w io.Writer) error {
+	temp := template.Must(template.New("conversion-matrix-template").Parse(ConversionMatrixTemplate))
+	return executeTemplate(w, "conversionmatrix", temp, struct {
+		Group, Domain string
+		Pairs         []conversionPair
+	}{d.apigroup.Group, d.apigroup.Domain, ConversionPairs(d.apigroup)})
+}
+
+// ConversionPairs returns the ordered pairs of the distinct versions of group declaring each of its resources,
+// including the versions only retaining the conversions of a resource, in the order of the version priority.
+// The resources with a build tag are left out, their code is only built with the tag.
+func ConversionPairs(group *APIGroup) []conversionPair {
+	pairs := []conversionPair{}
+	for _, kind := range sets.StringKeySet(group.UnversionedResources).List() {
+		if len(group.UnversionedResources[kind].BuildTag) > 0 {
+			continue
+		}
+		versions := []string{}
+		for _, version := range group.VersionPriority {
+			if _, found := group.Versions[version].Resources[kind]; found {
+				versions = append(versions, version)
+			}
+		}
+		for _, from := range versions {
+			for _, to := range versions {
+				if from != to {
+					pairs = append(pairs, conversionPair{kind, from, to})
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+var ConversionMatrixTemplate = `
+// conversionPairs are the ordered pairs of the versions declaring each resource of the {{.Group}} group
+var conversionPairs = []builders.ConversionPair{
+	{{ range $pair := .Pairs -}}
+	{Group: "{{$.Group}}.{{$.Domain}}", Kind: "{{$pair.Kind}}", From: "{{$pair.From}}", To: "{{$pair.To}}"},
+	{{ end -}}
+}
+
+// TestConversionMatrix checks each resource of the {{.Group}} group converts from each of its versions to each
+// other, directly or through the internal version.  It fails on a conversion forgotten when adding a version.
+func TestConversionMatrix(t *testing.T) {
+	scheme := runtime.NewScheme()
+	Install(scheme)
+	for _, pair := range conversionPairs {
+		t.Run(pair.String(), func(t *testing.T) {
+			if err := builders.CheckConversionPath(scheme, pair); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+`
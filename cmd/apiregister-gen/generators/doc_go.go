@@ -0,0 +1,111 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+// WriteVersionDoc adds the "+k8s:openapi-gen=true" and "+groupName" comments read by openapi-gen and
+// client-gen to the doc.go of version in dir, creating the file with header if it does not exist.  An
+// existing doc.go keeps its content, comments already setting a value are not changed.
+func WriteVersionDoc(version *APIVersion, dir string, header []byte) {
+	path, updated, markers := versionDoc(version, dir, header)
+	if len(markers) == 0 {
+		return
+	}
+	if err := ioutil.WriteFile(path, updated, 0644); err != nil {
+		klog.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// VerifyVersionDoc returns a description of the comments WriteVersionDoc would add to the doc.go of version
+// in dir, or the empty string if the doc.go is up to date.  The doc.go is not written.
+func VerifyVersionDoc(version *APIVersion, dir string, header []byte) string {
+	path, _, markers := versionDoc(version, dir, header)
+	if len(markers) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s lacks the comments:\n%s", path, markers)
+}
+
+// versionDoc returns the path of the doc.go of version in dir, its content with the missing comments added
+// and the missing comments, empty if the doc.go has them
+func versionDoc(version *APIVersion, dir string, header []byte) (string, []byte, string) {
+	groupName := version.Group + "." + version.Domain
+	path := filepath.Join(dir, "doc.go")
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		content = []byte(docHeader(header) + "package " + version.Version + "\n")
+	} else if err != nil {
+		klog.Fatalf("failed to read %s: %v", path, err)
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, content, parser.ParseComments|parser.PackageClauseOnly)
+	if err != nil {
+		klog.Fatalf("failed to parse %s: %v", path, err)
+	}
+	comments := Comments{}
+	for _, group := range f.Comments {
+		for _, line := range strings.Split(group.Text(), "\n") {
+			comments = append(comments, strings.TrimSpace(line))
+		}
+	}
+
+	markers := ""
+	if !comments.HasTag("k8s:openapi-gen=") {
+		markers += "// +k8s:openapi-gen=true\n"
+	}
+	if name := comments.GetTag("groupName", "="); len(name) == 0 {
+		markers += "// +groupName=" + groupName + "\n"
+	} else if name != groupName {
+		klog.Warningf("%s has // +groupName=%s, expected %s", path, name, groupName)
+	}
+	if len(markers) == 0 {
+		return path, content, ""
+	}
+
+	// The comments are added right above the package clause, into the package documentation
+	offset := fset.Position(f.Package).Offset
+	var updated bytes.Buffer
+	updated.Write(content[:offset])
+	updated.WriteString(markers)
+	updated.Write(content[offset:])
+	return path, updated.Bytes(), markers
+}
+
+// docHeader returns the header of a created doc.go, header without the lines marking the file generated as
+// the file is then owned by the users
+func docHeader(header []byte) string {
+	lines := []string{}
+	for _, line := range strings.Split(string(header), "\n") {
+		if !strings.Contains(line, "DO NOT EDIT") && !strings.Contains(line, "Do not edit it manually") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")) + "\n\n"
+}
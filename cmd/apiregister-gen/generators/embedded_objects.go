@@ -0,0 +1,76 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"strings"
+
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// rawExtension is the name of the runtime.RawExtension type
+var rawExtension = types.Name{Package: "k8s.io/apimachinery/pkg/runtime", Name: "RawExtension"}
+
+// EmbeddedObjectField is a runtime.RawExtension field of a resource with a "+embeddedObject" comment, whose
+// object is decoded converting to the internal version and encoded converting from it
+type EmbeddedObjectField struct {
+	// Path is the json path of the field, e.g. "spec.relic"
+	Path string
+	// Expr is the Go expression of the field in an object "o" of the resource
+	Expr string
+}
+
+// EmbeddedObjectFields returns the "+embeddedObject" fields of the resource t.  Only the fields reached from t
+// through the struct fields of types declared in the package of t are returned.
+func EmbeddedObjectFields(t *types.Type) []*EmbeddedObjectField {
+	fields := []*EmbeddedObjectField{}
+	findEmbeddedObjectFields(t, t.Name.Package, "", "o", &fields)
+	return fields
+}
+
+func findEmbeddedObjectFields(t *types.Type, pkg, path, expr string, fields *[]*EmbeddedObjectField) {
+	for _, m := range t.Members {
+		name := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		switch {
+		case name == "-":
+			continue
+		case len(name) == 0 && m.Embedded:
+			name = ""
+		case len(name) == 0:
+			name = m.Name
+		}
+		mPath := path
+		if len(name) > 0 {
+			mPath = strings.TrimPrefix(path+"."+name, ".")
+		}
+		mExpr := expr + "." + m.Name
+
+		switch {
+		case !Comments(m.CommentLines).HasTag("embeddedObject"):
+			if m.Type.Kind == types.Struct && m.Type.Name.Package == pkg {
+				findEmbeddedObjectFields(m.Type, pkg, mPath, mExpr, fields)
+			}
+		case m.Type.Name != rawExtension:
+			klog.Fatalf("+embeddedObject field %v.%s is a %v rather than a runtime.RawExtension",
+				t.Name, m.Name, m.Type.Name)
+		default:
+			*fields = append(*fields, &EmbeddedObjectField{Path: mPath, Expr: mExpr})
+		}
+	}
+}
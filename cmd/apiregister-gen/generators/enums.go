@@ -0,0 +1,103 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// EnumField is a field of a resource holding a value of a "+enum" type, a string type whose values are the
+// constants of the type declared in its package
+type EnumField struct {
+	// Path is the json path of the field, e.g. "spec.phase"
+	Path string
+	// Expr is the Go expression of the field in an object "o" of the resource
+	Expr string
+	// Values are the names of the constants of the type of the field
+	Values []string
+}
+
+// IsEnum returns true if t is a string type with a "+enum" comment
+func IsEnum(t *types.Type) bool {
+	return t.Kind == types.Alias && t.Underlying.Name == types.String.Name && Comments(t.CommentLines).HasTag("enum")
+}
+
+// EnumFields returns the fields of the resource t holding values of "+enum" types.  Only the fields reached
+// from t through the struct fields of types declared in the package of t are returned, and fields holding
+// pointers, slices or maps of "+enum" types are not supported.
+func EnumFields(universe types.Universe, t *types.Type) []*EnumField {
+	fields := []*EnumField{}
+	findEnumFields(universe, t, t.Name.Package, "", "o", &fields)
+	return fields
+}
+
+func findEnumFields(universe types.Universe, t *types.Type, pkg, path, expr string, fields *[]*EnumField) {
+	for _, m := range t.Members {
+		name := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		switch {
+		case name == "-":
+			continue
+		case len(name) == 0 && m.Embedded:
+			name = ""
+		case len(name) == 0:
+			name = m.Name
+		}
+		mPath := path
+		if len(name) > 0 {
+			mPath = strings.TrimPrefix(path+"."+name, ".")
+		}
+		mExpr := expr + "." + m.Name
+
+		switch {
+		case IsEnum(m.Type):
+			*fields = append(*fields, &EnumField{Path: mPath, Expr: mExpr, Values: enumValues(universe, m.Type)})
+		case m.Type.Kind == types.Struct && m.Type.Name.Package == pkg:
+			findEnumFields(universe, m.Type, pkg, mPath, mExpr, fields)
+		case IsEnum(containerElem(m.Type)):
+			klog.Fatalf("%v.%s holds values of the +enum type %v in a %v, which is not supported",
+				t.Name, m.Name, containerElem(m.Type).Name, m.Type.Kind)
+		}
+	}
+}
+
+// containerElem returns the type of the elements of pointers, slices, arrays and maps
+func containerElem(t *types.Type) *types.Type {
+	for t.Kind == types.Pointer || t.Kind == types.Slice || t.Kind == types.Array || t.Kind == types.Map {
+		t = t.Elem
+	}
+	return t
+}
+
+// enumValues returns the names of the constants of the "+enum" type t
+func enumValues(universe types.Universe, t *types.Type) []string {
+	values := []string{}
+	for name, c := range universe[t.Name.Package].Constants {
+		if c.Underlying != nil && c.Underlying.Name == t.Name {
+			values = append(values, name)
+		}
+	}
+	if len(values) == 0 {
+		klog.Fatalf("+enum type %v declares no constants", t.Name)
+	}
+	sort.Strings(values)
+	return values
+}
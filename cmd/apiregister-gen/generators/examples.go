@@ -0,0 +1,145 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"k8s.io/gengo/types"
+)
+
+// CheckExample returns an error if the json example of a "+example" comment does not parse, or does not
+// decode into the type t, e.g. it sets a field t does not have or a string to a numeric field.  Values of
+// types defining their own json encoding and of interface types are not checked.
+func CheckExample(t *types.Type, example string) error {
+	var value interface{}
+	if err := json.Unmarshal([]byte(example), &value); err != nil {
+		return err
+	}
+	return checkExampleValue(t, value, "")
+}
+
+func checkExampleValue(t *types.Type, value interface{}, path string) error {
+	if value == nil || hasCustomJSONMarshaling(t) {
+		return nil
+	}
+	switch t.Kind {
+	case types.Alias:
+		return checkExampleValue(t.Underlying, value, path)
+	case types.Pointer:
+		return checkExampleValue(t.Elem, value, path)
+	case types.Interface:
+		return nil
+	case types.Slice, types.Array:
+		if t.Kind == types.Slice && (t.Elem.Name == types.Byte.Name || t.Elem.Name == (types.Name{Name: "uint8"})) {
+			// []byte is encoded as a base64 string
+			return checkExampleValue(types.String, value, path)
+		}
+		values, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected a list for %v, got %v", examplePath(path), t, value)
+		}
+		for i, v := range values {
+			if err := checkExampleValue(t.Elem, v, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case types.Map:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object for %v, got %v", examplePath(path), t, value)
+		}
+		for k, v := range values {
+			if err := checkExampleValue(t.Elem, v, path+"."+k); err != nil {
+				return err
+			}
+		}
+	case types.Struct:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object for %v, got %v", examplePath(path), t, value)
+		}
+		fields := map[string]*types.Type{}
+		addExampleFields(t, fields)
+		for k, v := range values {
+			fieldType, found := fields[k]
+			if !found {
+				return fmt.Errorf("%s: %v has no field %q", examplePath(path), t, k)
+			}
+			if err := checkExampleValue(fieldType, v, path+"."+k); err != nil {
+				return err
+			}
+		}
+	case types.Builtin:
+		return checkExampleBuiltin(t, value, path)
+	}
+	return nil
+}
+
+// addExampleFields maps the json names of the fields of t, including those of its inlined embedded structs,
+// to their types
+func addExampleFields(t *types.Type, fields map[string]*types.Type) {
+	for _, m := range t.Members {
+		name := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		switch {
+		case name == "-":
+		case len(name) == 0 && m.Embedded && elemType(m.Type).Kind == types.Struct:
+			addExampleFields(elemType(m.Type), fields)
+		case len(name) == 0:
+			fields[m.Name] = m.Type
+		default:
+			fields[name] = m.Type
+		}
+	}
+}
+
+func checkExampleBuiltin(t *types.Type, value interface{}, path string) error {
+	switch t.Name.Name {
+	case "string":
+		if _, ok := value.(string); ok {
+			return nil
+		}
+	case "bool":
+		if _, ok := value.(bool); ok {
+			return nil
+		}
+	case "float32", "float64":
+		if _, ok := value.(float64); ok {
+			return nil
+		}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+		unsigned := strings.HasPrefix(t.Name.Name, "u") || t.Name.Name == "byte"
+		if n, ok := value.(float64); ok && n == math.Trunc(n) && (n >= 0 || !unsigned) {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return fmt.Errorf("%s: expected a value of type %v, got %v", examplePath(path), t, value)
+}
+
+// examplePath returns the json path of a value of an example, "." being the example itself
+func examplePath(path string) string {
+	if len(path) == 0 {
+		return "."
+	}
+	return path
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// FieldConversion is a struct of a version whose fields differ from the fields of its unversioned struct,
+// merged from the structs of every version of the group
+type FieldConversion struct {
+	// Type is the name of the struct - e.g. ShoggothSpec
+	Type string
+	// Added are the fields of the unversioned struct the version lacks, e.g. added by a newer version.  They
+	// are dropped converting to the version and zeroed converting from the version.
+	Added []string
+	// Incompatible describes the fields the version declares with another type than the unversioned struct,
+	// which require a manual conversion
+	Incompatible []string
+	// ToUnversioned is set to generate the conversion of the struct to the unversioned struct, which is not
+	// generated for incompatible fields or when the package of the version declares it
+	ToUnversioned bool
+	// FromUnversioned is set to generate the conversion of the unversioned struct to the struct
+	FromUnversioned bool
+}
+
+// ParseFieldConversions sets the FieldConversions of the versions of apigroup.  conversion-gen leaves the
+// conversions of the structs missing fields of their peer to be written by hand, the conversions handling
+// the added fields are generated instead.
+func (b *APIsBuilder) ParseFieldConversions(apigroup *APIGroup) {
+	unversioned := map[string]*Struct{}
+	for _, s := range apigroup.Structs {
+		if s.GenUnversioned {
+			unversioned[s.Name] = s
+		}
+	}
+	for _, version := range apigroup.Versions {
+		declared := declaredFunctions(version.Pkg.SourcePath)
+		maps := sets.NewString()
+		for _, name := range sets.StringKeySet(version.Pkg.Types).List() {
+			t := version.Pkg.Types[name]
+			peer, found := unversioned[name]
+			if !found || t.Kind != types.Struct || !ast.IsExported(name) ||
+				Comments(t.CommentLines).GetTag("k8s:conversion-gen", "=") == "false" {
+				continue
+			}
+			versioned, _ := apigroup.DoType(t)
+			fields := map[string]*Field{}
+			for _, field := range versioned.Fields {
+				fields[field.Name] = field
+			}
+
+			c := &FieldConversion{Type: name}
+			for _, field := range peer.Fields {
+				if len(field.Name) == 0 {
+					continue
+				}
+				if v, found := fields[field.Name]; !found {
+					c.Added = append(c.Added, field.Name)
+				} else if v.UnversionedType != field.UnversionedType {
+					if m := ParseMapConversion(version, t, member(t, field.Name), field.UnversionedType, unversioned,
+						declared); m != nil {
+						if !maps.Has(m.VersionedName + "_To_" + m.UnversionedName) {
+							maps.Insert(m.VersionedName + "_To_" + m.UnversionedName)
+							version.MapConversions = append(version.MapConversions, m)
+						}
+						continue
+					}
+					c.Incompatible = append(c.Incompatible, fmt.Sprintf("%s.%s is a %s in %s and a %s in the unversioned %s",
+						name, field.Name, v.UnversionedType, version.Version, field.UnversionedType, name))
+				}
+			}
+			if len(c.Added) == 0 && len(c.Incompatible) == 0 {
+				continue
+			}
+			if len(c.Incompatible) == 0 {
+				c.ToUnversioned = !declared.Has(fmt.Sprintf("Convert_%s_%s_To_%s_%s", version.Version, name, version.Group, name))
+				c.FromUnversioned = !declared.Has(fmt.Sprintf("Convert_%s_%s_To_%s_%s", version.Group, name, version.Version, name))
+			}
+			version.FieldConversions = append(version.FieldConversions, c)
+		}
+	}
+}
+
+// member returns the member of the struct t named name
+func member(t *types.Type, name string) types.Member {
+	for _, m := range t.Members {
+		if m.Name == name {
+			return m
+		}
+	}
+	return types.Member{Name: name, Type: &types.Type{Kind: types.Unknown}}
+}
+
+// declaredFunctions returns the names of the functions declared by the go files of the package in dir, other
+// than its generated files and tests
+func declaredFunctions(dir string) sets.String {
+	functions := sets.NewString()
+	files := typesModuleFiles(dir, func(name string) bool { return !strings.HasPrefix(name, "zz_generated.") })
+	for _, file := range files {
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+		if err != nil {
+			klog.Fatalf("failed to parse %s: %v", file, err)
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				functions.Insert(fn.Name.Name)
+			}
+		}
+	}
+	return functions
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+)
+
+// FieldDoc is the description of a field of a resource
+type FieldDoc struct {
+	// Path is the dotted json path of the field, e.g. "spec.venue.hall".  The fields of the elements of slices
+	// and maps are reached through the path of the slice or map.
+	Path string
+	// Description is the comment of the field without its "+" comment tags
+	Description string
+}
+
+// FieldDocs returns the descriptions of the fields of the resource t, e.g. for tooling explaining the fields
+// without reading the OpenAPI definitions.  The fields of embedded structs without a json name are the fields
+// of the embedding struct.  Only the fields of the struct types declared in the package of t are documented in
+// turn, and the fields of types with their own json encoding are not documented.
+func FieldDocs(t *types.Type) []*FieldDoc {
+	docs := []*FieldDoc{}
+	findFieldDocs(t, t.Name.Package, "", sets.NewString(), &docs)
+	return docs
+}
+
+func findFieldDocs(t *types.Type, pkg, path string, visiting sets.String, docs *[]*FieldDoc) {
+	t = elemType(t)
+	if t.Kind != types.Struct || visiting.Has(t.Name.String()) || hasCustomJSONMarshaling(t) {
+		return
+	}
+	// A type nested in itself is documented once along each path
+	visiting.Insert(t.Name.String())
+	defer visiting.Delete(t.Name.String())
+
+	for _, m := range t.Members {
+		name := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		switch {
+		case name == "-":
+			continue
+		case len(name) == 0 && m.Embedded:
+			findFieldDocs(m.Type, pkg, path, visiting, docs)
+			continue
+		case len(name) == 0:
+			name = m.Name
+		}
+		mPath := strings.TrimPrefix(path+"."+name, ".")
+		*docs = append(*docs, &FieldDoc{Path: mPath, Description: fieldDescription(m.CommentLines)})
+		if elemType(m.Type).Name.Package == pkg {
+			findFieldDocs(m.Type, pkg, mPath, visiting, docs)
+		}
+	}
+}
+
+// fieldDescription joins the comment lines of a field, skipping the "+" comment tags
+func fieldDescription(lines []string) string {
+	description := []string{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if len(line) > 0 && !strings.HasPrefix(line, "+") {
+			description = append(description, line)
+		}
+	}
+	return strings.Join(description, " ")
+}
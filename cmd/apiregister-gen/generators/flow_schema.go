@@ -0,0 +1,150 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+)
+
+// flowSchemaGroup is a group whose requests are classified by a FlowSchema
+type flowSchemaGroup struct {
+	// Name is the name of the group - e.g. mushroomkingdom.k8s.io
+	Name string
+	// Shares are the assured concurrency shares of the priority level of the group
+	Shares int
+	// Resources are the resources and the subresources served by the versions of the group, sorted - e.g.
+	// castles, castles/status
+	Resources []string
+	// Namespaced and ClusterScoped are set if the group serves namespaced and cluster scoped resources
+	Namespaced, ClusterScoped bool
+}
+
+// WriteFlowSchemaManifest writes the FlowSchema and the PriorityLevelConfiguration of each group of apis to file
+func WriteFlowSchemaManifest(apis *APIs, file string, shares int) {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		klog.Fatalf("failed to create %s: %v", filepath.Dir(file), err)
+	}
+	if err := ioutil.WriteFile(file, FlowSchemaManifest(apis, shares), 0644); err != nil {
+		klog.Fatalf("failed to write %s: %v", file, err)
+	}
+}
+
+// FlowSchemaManifest returns a FlowSchema classifying the requests to the resources served by each group of
+// apis into a PriorityLevelConfiguration of the group, limited to the assured concurrency shares.  The
+// requests of every user are matched, and distinguished by user within the priority level.  The resources
+// with a build tag are left out, as they are by the webhook manifest.
+func FlowSchemaManifest(apis *APIs, shares int) []byte {
+	if shares <= 0 {
+		klog.Fatalf("--flow-schema-concurrency-shares must be positive, got %d", shares)
+	}
+	groups := []*flowSchemaGroup{}
+	for _, name := range sets.StringKeySet(apis.Groups).List() {
+		group := apis.Groups[name]
+		g := &flowSchemaGroup{Name: group.Group + "." + group.Domain, Shares: shares}
+		resources := sets.NewString()
+		for _, version := range group.Versions {
+			for _, resource := range served(version.Resources) {
+				resources.Insert(resource.Resource)
+				if len(resource.REST) == 0 {
+					resources.Insert(resource.Resource + "/status")
+				}
+				for _, subresource := range resource.Subresources {
+					resources.Insert(resource.Resource + "/" + subresource.Path)
+				}
+				if resource.NonNamespaced {
+					g.ClusterScoped = true
+				} else {
+					g.Namespaced = true
+				}
+			}
+		}
+		if resources.Len() == 0 {
+			continue
+		}
+		g.Resources = resources.List()
+		groups = append(groups, g)
+	}
+
+	var b bytes.Buffer
+	temp := template.Must(template.New("flow-schema-manifest-template").Parse(FlowSchemaManifestTemplate))
+	if err := temp.Execute(&b, groups); err != nil {
+		klog.Fatalf("failed to write the flow schema manifest: %v", err)
+	}
+	return b.Bytes()
+}
+
+var FlowSchemaManifestTemplate = `{{- range $i, $group := . -}}
+{{- if $i }}---
+{{ end -}}
+apiVersion: flowcontrol.apiserver.k8s.io/v1alpha1
+kind: PriorityLevelConfiguration
+metadata:
+  name: {{ $group.Name }}
+spec:
+  limited:
+    assuredConcurrencyShares: {{ $group.Shares }}
+    limitResponse:
+      queuing:
+        handSize: 8
+        queueLengthLimit: 50
+        queues: 64
+      type: Queue
+  type: Limited
+---
+apiVersion: flowcontrol.apiserver.k8s.io/v1alpha1
+kind: FlowSchema
+metadata:
+  name: {{ $group.Name }}
+spec:
+  distinguisherMethod:
+    type: ByUser
+  matchingPrecedence: 1000
+  priorityLevelConfiguration:
+    name: {{ $group.Name }}
+  rules:
+  - resourceRules:
+    - apiGroups:
+      - {{ $group.Name }}
+      {{- if $group.ClusterScoped }}
+      clusterScope: true
+      {{- end }}
+      {{- if $group.Namespaced }}
+      namespaces:
+      - '*'
+      {{- end }}
+      resources:
+      {{- range $resource := $group.Resources }}
+      - {{ $resource }}
+      {{- end }}
+      verbs:
+      - '*'
+    subjects:
+    - group:
+        name: system:authenticated
+      kind: Group
+    - group:
+        name: system:unauthenticated
+      kind: Group
+{{ end -}}
+`
@@ -0,0 +1,176 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// WriteFuzzCorpus writes the fuzz seed corpus of each resource of apis to dir/<group>/<version>/<resource>/.
+// The corpus holds generated.json, an object of the resource setting its fields to deterministic values,
+// and example.json, the json example of the "+example" comment of the resource type if it has one.
+func WriteFuzzCorpus(apis *APIs, dir string) {
+	for _, group := range apis.Groups {
+		for _, version := range group.Versions {
+			for _, resource := range version.Resources {
+				resourceDir := filepath.Join(dir, group.Group, version.Version, resource.Resource)
+				if err := os.MkdirAll(resourceDir, 0700); err != nil {
+					klog.Fatalf("failed to create %s: %v", resourceDir, err)
+				}
+				seeds := map[string][]byte{"generated.json": FuzzSeed(resource)}
+				if example := Comments(resource.Type.CommentLines).GetTag("example", "="); len(example) > 0 {
+					var b bytes.Buffer
+					if err := json.Indent(&b, []byte(example), "", "  "); err != nil {
+						klog.Fatalf("// +example of type %v must be a json example of the type: %v", resource.Type.Name, err)
+					}
+					seeds["example.json"] = append(b.Bytes(), '\n')
+				}
+				for name, seed := range seeds {
+					file := filepath.Join(resourceDir, name)
+					if err := ioutil.WriteFile(file, seed, 0644); err != nil {
+						klog.Fatalf("failed to write %s: %v", file, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// FuzzSeed returns the indented json of an object of the resource r whose fields are set to deterministic
+// values: strings to their json name, numbers to 1, booleans to true, and lists and maps to one element.
+// Only the fields of the types declared in the package of the resource are set, besides the name of the
+// object, the name of the "+singleton" comment for singleton resources, and its generateName for
+// "+resource:generateNameRequired" resources.  Fields which would make the object invalid are left unset:
+// all but the first field of a "+resource:oneOf" constraint, "+removedField" fields and "+enum" fields.
+func FuzzSeed(r *APIResource) []byte {
+	skipped := sets.NewString()
+	for _, constraint := range r.FieldConstraints {
+		if constraint.Rule != "OneOf" {
+			continue
+		}
+		for _, field := range constraint.Fields[1:] {
+			skipped.Insert(field.Path)
+		}
+	}
+	for _, field := range r.RemovedFields {
+		skipped.Insert(field.Path)
+	}
+
+	seed := map[string]interface{}{}
+	fuzzSeedFields(r.Type, r.Type.Name.Package, "", skipped, sets.NewString(r.Type.Name.Name), seed)
+	seed["apiVersion"] = fmt.Sprintf("%s.%s/%s", r.Group, r.Domain, r.Version)
+	seed["kind"] = r.Kind
+	metadata := map[string]interface{}{"name": strings.ToLower(r.Kind)}
+	if len(r.Singleton) > 0 {
+		metadata["name"] = r.Singleton
+	}
+	if r.GenerateNameRequired {
+		metadata["generateName"] = strings.ToLower(r.Kind) + "-"
+	}
+	seed["metadata"] = metadata
+
+	b, err := json.MarshalIndent(seed, "", "  ")
+	if err != nil {
+		klog.Fatalf("failed to encode the fuzz seed of %v: %v", r.Type.Name, err)
+	}
+	return append(b, '\n')
+}
+
+// fuzzSeedFields sets the fields of the struct t, including those of its inlined embedded structs, in seed.
+// The types being set are visited to not recurse into recursive types.
+func fuzzSeedFields(t *types.Type, pkg, prefix string, skipped, visited sets.String, seed map[string]interface{}) {
+	for _, m := range t.Members {
+		name := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		switch {
+		case name == "-":
+			continue
+		case len(name) == 0 && m.Embedded && elemType(m.Type).Kind == types.Struct:
+			if elemType(m.Type).Name.Package == pkg {
+				fuzzSeedFields(elemType(m.Type), pkg, prefix, skipped, visited, seed)
+			}
+			continue
+		case len(name) == 0:
+			name = m.Name
+		}
+		if skipped.Has(prefix + name) {
+			continue
+		}
+		if value, ok := fuzzSeedValue(m.Type, name, pkg, prefix+name+".", skipped, visited); ok {
+			seed[name] = value
+		}
+	}
+}
+
+// fuzzSeedValue returns the deterministic value of a field of type t named name, or false if the field is
+// left unset
+func fuzzSeedValue(t *types.Type, name, pkg, prefix string, skipped, visited sets.String) (interface{}, bool) {
+	if IsEnum(t) || hasCustomJSONMarshaling(t) {
+		return nil, false
+	}
+	switch t.Kind {
+	case types.Alias:
+		return fuzzSeedValue(t.Underlying, name, pkg, prefix, skipped, visited)
+	case types.Pointer:
+		return fuzzSeedValue(t.Elem, name, pkg, prefix, skipped, visited)
+	case types.Builtin:
+		switch t.Name.Name {
+		case "string":
+			return name, true
+		case "bool":
+			return true, true
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+			"byte", "rune", "float32", "float64":
+			return 1, true
+		}
+	case types.Slice, types.Array:
+		if t.Kind == types.Slice && (t.Elem.Name == types.Byte.Name || t.Elem.Name == (types.Name{Name: "uint8"})) {
+			// []byte is encoded as a base64 string
+			return []byte(name), true
+		}
+		if elem, ok := fuzzSeedValue(t.Elem, name, pkg, prefix, skipped, visited); ok {
+			return []interface{}{elem}, true
+		}
+	case types.Map:
+		if elemType(t.Key).Name != types.String.Name {
+			return nil, false
+		}
+		if elem, ok := fuzzSeedValue(t.Elem, name, pkg, prefix, skipped, visited); ok {
+			return map[string]interface{}{"key": elem}, true
+		}
+	case types.Struct:
+		if t.Name.Package != pkg || visited.Has(t.Name.Name) {
+			return nil, false
+		}
+		visited.Insert(t.Name.Name)
+		defer visited.Delete(t.Name.Name)
+		fields := map[string]interface{}{}
+		fuzzSeedFields(t, pkg, prefix, skipped, visited, fields)
+		return fields, true
+	}
+	return nil, false
+}
@@ -55,8 +55,8 @@ func (d *installGenerator) Imports(c *generator.Context) []string {
 }
 
 func (d *installGenerator) Finalize(context *generator.Context, w io.Writer) error {
-	temp := template.Must(template.New("install-template").Parse(InstallAPITemplate))
-	err := temp.Execute(w, d.apigroup)
+	temp := template.Must(template.New("install-template").Funcs(templateFuncs).Parse(InstallAPITemplate))
+	err := executeTemplate(w, "install", temp, d.apigroup)
 	if err != nil {
 		return err
 	}
@@ -74,6 +74,7 @@ func Install(scheme *runtime.Scheme) {
 {{ end -}}
 	utilruntime.Must({{ $.Group }}.AddToScheme(scheme))
 	utilruntime.Must(addKnownTypes(scheme))
+	utilruntime.Must(scheme.SetVersionPriority({{ $.Group }}.VersionPriority...))
 }
 
 
@@ -0,0 +1,50 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+)
+
+// InterfaceFields describes each field of the resource t holding values of an interface type.  conversion-gen
+// copies the interface values rather than converting them, so the converted object shares them with the
+// original.  runtime.RawExtension fields are deep copied by the builders.Convert_runtime_RawExtension_To_runtime_RawExtension
+// conversion and are not interface fields.  Only types declared in the package of t are checked.
+func InterfaceFields(t *types.Type) []string {
+	fields := []string{}
+	findInterfaceFields(t, t.Name.Package, sets.NewString(), &fields)
+	return fields
+}
+
+func findInterfaceFields(t *types.Type, pkg string, visited sets.String, fields *[]string) {
+	t = elemType(t)
+	if t.Kind != types.Struct || t.Name.Package != pkg || visited.Has(t.Name.String()) {
+		return
+	}
+	visited.Insert(t.Name.String())
+
+	for _, m := range t.Members {
+		findInterfaceFields(m.Type, pkg, visited, fields)
+
+		if elem := elemType(m.Type); elem.Kind == types.Interface {
+			*fields = append(*fields, fmt.Sprintf("%v.%s holds values of the interface type %v", t.Name, m.Name, elem))
+		}
+	}
+}
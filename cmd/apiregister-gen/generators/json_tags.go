@@ -0,0 +1,129 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+)
+
+// JSONTagDeviations describes each field serialized by the resource t whose json tag is not the
+// lowerCamelCase of its Go name.  Only types declared in the package of t are checked, and fields
+// with a "+json:allowDeviation" comment or of types defining their own json encoding are skipped.
+func JSONTagDeviations(t *types.Type) []string {
+	deviations := []string{}
+	checkJSONTags(t, t.Name.Package, sets.NewString(), &deviations)
+	return deviations
+}
+
+func checkJSONTags(t *types.Type, pkg string, visited sets.String, deviations *[]string) {
+	t = elemType(t)
+	if t.Kind != types.Struct || t.Name.Package != pkg || visited.Has(t.Name.String()) {
+		return
+	}
+	visited.Insert(t.Name.String())
+	// The json tags of the fields are ignored by MarshalJSON and UnmarshalJSON
+	if hasCustomJSONMarshaling(t) {
+		return
+	}
+
+	for _, m := range t.Members {
+		checkJSONTags(m.Type, pkg, visited, deviations)
+
+		name := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		switch {
+		case len(name) == 0, name == "-":
+		case name == "metadata" && isListOrObjectMeta(m.Type):
+		case Comments(m.CommentLines).HasTag("json:allowDeviation"):
+		case name != lowerCamelCase(m.Name):
+			*deviations = append(*deviations, fmt.Sprintf(
+				"%v.%s has json tag %q, expected %q", t.Name, m.Name, name, lowerCamelCase(m.Name)))
+		}
+	}
+}
+
+// CustomJSONMarshalers returns the resource t and the types of its fields declared in the package of t
+// that define both MarshalJSON and UnmarshalJSON
+func CustomJSONMarshalers(t *types.Type) []*types.Type {
+	marshalers := []*types.Type{}
+	findCustomJSONMarshalers(t, t.Name.Package, sets.NewString(), &marshalers)
+	return marshalers
+}
+
+func findCustomJSONMarshalers(t *types.Type, pkg string, visited sets.String, marshalers *[]*types.Type) {
+	t = elemType(t)
+	if t.Name.Package != pkg || visited.Has(t.Name.String()) {
+		return
+	}
+	visited.Insert(t.Name.String())
+	if hasCustomJSONMarshaling(t) {
+		*marshalers = append(*marshalers, t)
+	}
+	if t.Kind != types.Struct {
+		return
+	}
+	for _, m := range t.Members {
+		findCustomJSONMarshalers(m.Type, pkg, visited, marshalers)
+	}
+}
+
+// hasCustomJSONMarshaling returns true if t defines both MarshalJSON and UnmarshalJSON
+func hasCustomJSONMarshaling(t *types.Type) bool {
+	_, marshal := t.Methods["MarshalJSON"]
+	_, unmarshal := t.Methods["UnmarshalJSON"]
+	return marshal && unmarshal
+}
+
+// elemType returns the type of the elements of pointers, slices, arrays, maps and aliases
+func elemType(t *types.Type) *types.Type {
+	for {
+		switch t.Kind {
+		case types.Pointer, types.Slice, types.Array, types.Map:
+			t = t.Elem
+		case types.Alias:
+			t = t.Underlying
+		default:
+			return t
+		}
+	}
+}
+
+// isListOrObjectMeta returns true if t is metav1.ObjectMeta or metav1.ListMeta, which are serialized as "metadata"
+func isListOrObjectMeta(t *types.Type) bool {
+	return t.Name == types.Name{Package: "k8s.io/apimachinery/pkg/apis/meta/v1", Name: "ObjectMeta"} ||
+		t.Name == types.Name{Package: "k8s.io/apimachinery/pkg/apis/meta/v1", Name: "ListMeta"}
+}
+
+// lowerCamelCase returns name with its leading word lowercased, e.g. "MaxStudents" is "maxStudents",
+// "URLPath" is "urlPath" and "ID" is "id"
+func lowerCamelCase(name string) string {
+	runes := []rune(name)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	// The last upper case letter of an initialism followed by lower case letters starts the next word
+	if i > 1 && i < len(runes) && unicode.IsLower(runes[i]) {
+		i--
+	}
+	return strings.ToLower(string(runes[:i])) + string(runes[i:])
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+)
+
+// ListMapKeyErrors describes each list field of the resource t marked "+listType=map" that is not merged
+// by one of its "+listMapKey" keys.  openapi-gen emits the x-kubernetes-list-type and
+// x-kubernetes-list-map-keys extensions used by server-side apply from the comments, but strategic merge
+// patches only read the patchStrategy and patchMergeKey struct tags, so the field must have both.
+// Only types declared in the package of t are checked.
+func ListMapKeyErrors(t *types.Type) []string {
+	errors := []string{}
+	checkListMapKeys(t, t.Name.Package, sets.NewString(), &errors)
+	return errors
+}
+
+func checkListMapKeys(t *types.Type, pkg string, visited sets.String, errors *[]string) {
+	t = elemType(t)
+	if t.Kind != types.Struct || t.Name.Package != pkg || visited.Has(t.Name.String()) {
+		return
+	}
+	visited.Insert(t.Name.String())
+
+	for _, m := range t.Members {
+		checkListMapKeys(m.Type, pkg, visited, errors)
+
+		c := Comments(m.CommentLines)
+		if c.GetTag("listType", "=") != "map" {
+			continue
+		}
+		field := fmt.Sprintf("%v.%s", t.Name, m.Name)
+		keys := c.GetTags("listMapKey", "=")
+		if len(keys) == 0 {
+			*errors = append(*errors, fmt.Sprintf("%s is marked +listType=map without a +listMapKey", field))
+			continue
+		}
+		elem := elemType(m.Type)
+		for _, key := range keys {
+			if elem.Kind != types.Struct || !hasJSONField(elem, key) {
+				*errors = append(*errors, fmt.Sprintf(
+					"%s has +listMapKey=%s, which is not a field of the list items %v", field, key, elem.Name))
+			}
+		}
+
+		tags := reflect.StructTag(m.Tags)
+		if !sets.NewString(strings.Split(tags.Get("patchStrategy"), ",")...).Has("merge") ||
+			!sets.NewString(keys...).Has(tags.Get("patchMergeKey")) {
+			*errors = append(*errors, fmt.Sprintf(
+				"%s is marked +listType=map, expected the struct tags patchStrategy:\"merge\" patchMergeKey:%q",
+				field, keys[0]))
+		}
+	}
+}
+
+// hasJSONField returns true if the struct t or one of its embedded structs has a field serialized as name
+func hasJSONField(t *types.Type, name string) bool {
+	for _, m := range t.Members {
+		tag := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		switch {
+		case tag == name:
+			return true
+		case m.Embedded && len(tag) == 0 && elemType(m.Type).Kind == types.Struct:
+			if hasJSONField(elemType(m.Type), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
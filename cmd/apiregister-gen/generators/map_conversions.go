@@ -0,0 +1,152 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// MapConversion converts a map field of a version whose elements differ from the elements of the field of the
+// unversioned struct, e.g. a map[string]Den of v1alpha1 declared as a map[string]Lair by the most preferred
+// version.  conversion-gen converts the field with the conversions of the map types rather than with the
+// missing conversions of its elements.
+type MapConversion struct {
+	// Versioned and Unversioned are the map types in the package of the version - e.g. map[string]Den and
+	// map[string]innsmouth.Lair
+	Versioned, Unversioned string
+	// VersionedName and UnversionedName are the names of the map types in the names of the conversions - e.g.
+	// Map_string_To_v1alpha1_Den and Map_string_To_innsmouth_Lair
+	VersionedName, UnversionedName string
+	// VersionedKey and UnversionedKey are the key types in the package of the version - e.g. string
+	VersionedKey, UnversionedKey string
+	// VersionedElem and UnversionedElem are the element types in the package of the version - e.g. Den and
+	// innsmouth.Lair
+	VersionedElem, UnversionedElem string
+	// ToUnversioned and FromUnversioned are the conversions of the elements declared by the package of the
+	// version, called for each entry of the map
+	ToUnversioned, FromUnversioned string
+	// Stub describes why the elements cannot be converted.  When set the conversions of the map types fail with
+	// the message rather than convert the entries.
+	Stub string
+}
+
+// mapTypeName is a key or element type of a map, named by its name in the package of the version and by its
+// name in the names of the conversions
+type mapTypeName struct {
+	raw, public string
+	builtin     bool
+	name        string
+}
+
+// ParseMapConversion returns the conversion of the map member of the struct t of version whose unversioned
+// type differs, or nil when its types are not builtins or types of the group, which are left to the conversion
+// functions of the struct.  unversioned are the unversioned structs of the group keyed by name and declared
+// the functions declared by the package of the version.
+func ParseMapConversion(version *APIVersion, t *types.Type, member types.Member, unversionedType string,
+	unversioned map[string]*Struct, declared sets.String) *MapConversion {
+	expr, err := parser.ParseExpr(unversionedType)
+	if err != nil || member.Type.Kind != types.Map {
+		return nil
+	}
+	m, ok := expr.(*ast.MapType)
+	if !ok {
+		return nil
+	}
+	vKey, ok := versionedMapTypeName(version, member.Type.Key)
+	if !ok {
+		return nil
+	}
+	vElem, ok := versionedMapTypeName(version, member.Type.Elem)
+	if !ok {
+		return nil
+	}
+	uKey, ok := unversionedMapTypeName(version, m.Key)
+	if !ok {
+		return nil
+	}
+	uElem, ok := unversionedMapTypeName(version, m.Value)
+	if !ok || (vElem.builtin && uElem.builtin) {
+		// conversion-gen converts the builtin elements
+		return nil
+	}
+
+	c := &MapConversion{
+		Versioned:       fmt.Sprintf("map[%s]%s", vKey.raw, vElem.raw),
+		Unversioned:     fmt.Sprintf("map[%s]%s", uKey.raw, uElem.raw),
+		VersionedName:   fmt.Sprintf("Map_%s_To_%s", vKey.public, vElem.public),
+		UnversionedName: fmt.Sprintf("Map_%s_To_%s", uKey.public, uElem.public),
+		VersionedKey:    vKey.raw,
+		UnversionedKey:  uKey.raw,
+		VersionedElem:   vElem.raw,
+		UnversionedElem: uElem.raw,
+		ToUnversioned:   fmt.Sprintf("Convert_%s_To_%s", vElem.public, uElem.public),
+		FromUnversioned: fmt.Sprintf("Convert_%s_To_%s", uElem.public, vElem.public),
+	}
+	field := fmt.Sprintf("%s.%s is a %s in %s and a %s in the unversioned %s", t.Name.Name, member.Name,
+		c.Versioned, version.Version, unversionedType, t.Name.Name)
+	_, structElem := unversioned[uElem.name]
+	switch {
+	case vKey.name != uKey.name:
+		c.Stub = fmt.Sprintf("%s, whose keys differ: convert the field in the conversion functions of %s",
+			field, t.Name.Name)
+	case member.Type.Elem.Kind != types.Struct || uElem.builtin || !structElem:
+		c.Stub = fmt.Sprintf("%s, whose elements are not both structs: convert the field in the conversion "+
+			"functions of %s", field, t.Name.Name)
+	case !declared.HasAll(c.ToUnversioned, c.FromUnversioned):
+		c.Stub = fmt.Sprintf("%s: declare %s and %s to convert its elements", field, c.ToUnversioned,
+			c.FromUnversioned)
+	}
+	if len(c.Stub) > 0 {
+		klog.Warningf("%s", c.Stub)
+	}
+	return c
+}
+
+// versionedMapTypeName names the key or element type t of a map of version, which must be a builtin or a type
+// declared by version
+func versionedMapTypeName(version *APIVersion, t *types.Type) (mapTypeName, bool) {
+	switch {
+	case t.Kind == types.Builtin:
+		return mapTypeName{raw: t.Name.Name, public: t.Name.Name, builtin: true, name: t.Name.Name}, true
+	case (t.Kind == types.Struct || t.Kind == types.Alias) && t.Name.Package == version.Pkg.Path:
+		return mapTypeName{raw: t.Name.Name, public: version.Version + "_" + t.Name.Name, name: t.Name.Name}, true
+	}
+	return mapTypeName{}, false
+}
+
+// unversionedMapTypeName names the key or element type expr of a map of the unversioned package of version,
+// which must be a builtin or a type declared by the unversioned package
+func unversionedMapTypeName(version *APIVersion, expr ast.Expr) (mapTypeName, bool) {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return mapTypeName{}, false
+	}
+	if !ast.IsExported(ident.Name) {
+		return mapTypeName{raw: ident.Name, public: ident.Name, builtin: true, name: ident.Name}, true
+	}
+	return mapTypeName{
+		raw:    version.Group + "." + ident.Name,
+		public: version.Group + "_" + ident.Name,
+		name:   ident.Name,
+	}, true
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+)
+
+const innsmouthV1alpha1 = "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1alpha1"
+
+// TestMapConversions checks the conversions generated for the map fields whose elements differ between a
+// version and the unversioned struct convert each entry through the conversions of the elements, or fail with
+// a message when the elements cannot be converted
+func TestMapConversions(t *testing.T) {
+	den := &types.Type{Name: types.Name{Package: innsmouthV1alpha1, Name: "Den"}, Kind: types.Struct}
+	converters := sets.NewString("Convert_v1alpha1_Den_To_innsmouth_Lair", "Convert_innsmouth_Lair_To_v1alpha1_Den")
+	unversioned := map[string]*Struct{"Lair": {Name: "Lair"}}
+
+	tests := []struct {
+		name        string
+		elem        *types.Type
+		unversioned string
+		declared    sets.String
+		expected    []string
+	}{
+		{
+			name:        "convertible elements",
+			elem:        den,
+			unversioned: "map[string]Lair",
+			declared:    converters,
+			expected: []string{
+				"func Convert_Map_string_To_v1alpha1_Den_To_Map_string_To_innsmouth_Lair(in *map[string]Den, " +
+					"out *map[string]innsmouth.Lair, s conversion.Scope) error {",
+				"*out = make(map[string]innsmouth.Lair, len(*in))",
+				"if err := Convert_v1alpha1_Den_To_innsmouth_Lair(&val, newVal, s); err != nil {",
+				"(*out)[string(key)] = *newVal",
+				"func Convert_Map_string_To_innsmouth_Lair_To_Map_string_To_v1alpha1_Den(in *map[string]innsmouth.Lair, " +
+					"out *map[string]Den, s conversion.Scope) error {",
+				"if err := Convert_innsmouth_Lair_To_v1alpha1_Den(&val, newVal, s); err != nil {",
+			},
+		},
+		{
+			name:        "incompatible elements",
+			elem:        types.String,
+			unversioned: "map[string]Lair",
+			declared:    converters,
+			expected: []string{
+				"func Convert_Map_string_To_string_To_Map_string_To_innsmouth_Lair(in *map[string]string, " +
+					"out *map[string]innsmouth.Lair, s conversion.Scope) error {",
+				`return fmt.Errorf("ShoggothStatus.Lairs is a map[string]string in v1alpha1 and a map[string]Lair in ` +
+					`the unversioned ShoggothStatus, whose elements are not both structs: convert the field in the ` +
+					`conversion functions of ShoggothStatus")`,
+			},
+		},
+		{
+			name:        "undeclared element conversions",
+			elem:        den,
+			unversioned: "map[string]Lair",
+			declared:    sets.NewString(),
+			expected: []string{
+				`return fmt.Errorf("ShoggothStatus.Lairs is a map[string]Den in v1alpha1 and a map[string]Lair in the ` +
+					`unversioned ShoggothStatus: declare Convert_v1alpha1_Den_To_innsmouth_Lair and ` +
+					`Convert_innsmouth_Lair_To_v1alpha1_Den to convert its elements")`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			version := &APIVersion{Group: "innsmouth", Version: "v1alpha1", Pkg: &types.Package{Path: innsmouthV1alpha1}}
+			lairs := types.Member{Name: "Lairs", Type: &types.Type{Kind: types.Map, Key: types.String, Elem: test.elem}}
+			status := &types.Type{
+				Name:    types.Name{Package: innsmouthV1alpha1, Name: "ShoggothStatus"},
+				Kind:    types.Struct,
+				Members: []types.Member{lairs},
+			}
+
+			m := ParseMapConversion(version, status, lairs, test.unversioned, unversioned, test.declared)
+			if m == nil {
+				t.Fatalf("expected the conversion of the lairs")
+			}
+			version.MapConversions = []*MapConversion{m}
+
+			out := &bytes.Buffer{}
+			temp := template.Must(template.New("conversion-template").Funcs(templateFuncs).Parse(ConversionTemplate))
+			if err := temp.Execute(out, version); err != nil {
+				t.Fatal(err)
+			}
+			generated := out.String()
+			for _, expected := range test.expected {
+				if !strings.Contains(generated, expected) {
+					t.Errorf("expected the generated conversions to contain %q, got:\n%s", expected, generated)
+				}
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), "", "package v1alpha1\n"+generated, 0); err != nil {
+				t.Errorf("the generated conversions are not valid go: %v\n%s", err, generated)
+			}
+
+			apigroup := &APIGroup{Pkg: &types.Package{Path: "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"}}
+			imports := sets.NewString(CreateConversionGenerator(version, apigroup, "zz_generated").Imports(nil)...)
+			if len(m.Stub) > 0 != imports.Has("fmt") {
+				t.Errorf("expected fmt to be imported by the stubs only, got %v", imports.List())
+			}
+		})
+	}
+}
+
+// TestBuiltinMapConversions checks no conversion is generated for the maps of builtin elements, which
+// conversion-gen converts
+func TestBuiltinMapConversions(t *testing.T) {
+	version := &APIVersion{Group: "innsmouth", Version: "v1alpha1", Pkg: &types.Package{Path: innsmouthV1alpha1}}
+	eyes := types.Member{Name: "Eyes", Type: &types.Type{Kind: types.Map, Key: types.String, Elem: types.Int32}}
+	spec := &types.Type{
+		Name:    types.Name{Package: innsmouthV1alpha1, Name: "ShoggothSpec"},
+		Kind:    types.Struct,
+		Members: []types.Member{eyes},
+	}
+	if m := ParseMapConversion(version, spec, eyes, "map[string]int64", nil, sets.NewString()); m != nil {
+		t.Errorf("expected the builtin elements to be left to conversion-gen, got %+v", m)
+	}
+}
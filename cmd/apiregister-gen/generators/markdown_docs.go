@@ -0,0 +1,252 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// MarkdownDoc is the reference of a resource, documenting the resource type and the types declared in its
+// package which it serializes
+type MarkdownDoc struct {
+	Resource *APIResource
+	Types    []*MarkdownType
+}
+
+// MarkdownType documents a struct type
+type MarkdownType struct {
+	Name        string
+	Description string
+	// Example is the indented json example of the "+example" comment of the type
+	Example string
+	// Fields are empty if the type defines its own json encoding
+	Fields []*MarkdownField
+}
+
+// MarkdownField documents a field of a struct type by its json name
+type MarkdownField struct {
+	Name        string
+	Type        string
+	Description string
+	// Validation lists the constraints of the "+optional", "+listType", "+listMapKey", "+resource:oneOf",
+	// "+resource:allOrNone", "+removedField", "+readOnly" and "+resource:rangeDefault" comments of the field
+	Validation []string
+}
+
+// WriteMarkdownDocs writes the markdown reference of each resource of apis to
+// dir/<group>/<version>/<resource>.md
+func WriteMarkdownDocs(apis *APIs, dir string) {
+	for _, group := range apis.Groups {
+		for _, version := range group.Versions {
+			for _, resource := range version.Resources {
+				file := filepath.Join(dir, group.Group, version.Version, resource.Resource+".md")
+				if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+					klog.Fatalf("failed to create %s: %v", filepath.Dir(file), err)
+				}
+				if err := ioutil.WriteFile(file, MarkdownDocs(resource), 0644); err != nil {
+					klog.Fatalf("failed to write %s: %v", file, err)
+				}
+			}
+		}
+	}
+}
+
+// MarkdownDocs returns the markdown reference of the resource r
+func MarkdownDocs(r *APIResource) []byte {
+	doc := &MarkdownDoc{Resource: r}
+	constraints := map[string][]string{}
+	for _, constraint := range r.FieldConstraints {
+		paths := []string{}
+		for _, field := range constraint.Fields {
+			paths = append(paths, "`"+field.Path+"`")
+		}
+		description := "one of " + strings.Join(paths, ", ")
+		if constraint.Rule == "AllOrNone" {
+			description = "all or none of " + strings.Join(paths, ", ")
+		}
+		for _, field := range constraint.Fields {
+			constraints[field.Path] = append(constraints[field.Path], description)
+		}
+	}
+	for _, field := range r.RemovedFields {
+		constraints[field.Path] = append(constraints[field.Path], "removed, cleared from the objects")
+	}
+	for _, field := range r.RangeDefaults {
+		constraints[field.Path] = append(constraints[field.Path],
+			fmt.Sprintf("clamped into [%d, %d] on create", field.Min, field.Max))
+	}
+	for _, field := range r.ReadOnlyFields {
+		constraints[field.Path] = append(constraints[field.Path], "read-only, cleared from the created objects and kept on updates")
+	}
+	addMarkdownType(doc, r.Type, "", constraints, sets.NewString())
+
+	var b bytes.Buffer
+	temp := template.Must(template.New("markdown-docs-template").Funcs(
+		template.FuncMap{"join": strings.Join},
+	).Parse(MarkdownDocsTemplate))
+	if err := temp.Execute(&b, doc); err != nil {
+		klog.Fatalf("failed to document %v: %v", r.Type.Name, err)
+	}
+	return b.Bytes()
+}
+
+// addMarkdownType documents t and the types of its fields declared in the package of the resource.  A type
+// is documented once, its fields reached through the json path prefix are matched with the constraints.
+func addMarkdownType(doc *MarkdownDoc, t *types.Type, prefix string, constraints map[string][]string, visited sets.String) {
+	t = elemType(t)
+	if t.Kind != types.Struct || t.Name.Package != doc.Resource.Type.Name.Package || visited.Has(t.Name.Name) {
+		return
+	}
+	visited.Insert(t.Name.Name)
+
+	mt := &MarkdownType{
+		Name:        t.Name.Name,
+		Description: markdownDescription(t.CommentLines),
+		Example:     markdownExample(t),
+	}
+	doc.Types = append(doc.Types, mt)
+	if hasCustomJSONMarshaling(t) {
+		return
+	}
+	nested := []func(){}
+	var addFields func(t *types.Type)
+	addFields = func(t *types.Type) {
+		for _, m := range t.Members {
+			tag := reflect.StructTag(m.Tags).Get("json")
+			name := strings.Split(tag, ",")[0]
+			switch {
+			case name == "-":
+				continue
+			case len(name) == 0 && m.Embedded && elemType(m.Type).Kind == types.Struct:
+				addFields(elemType(m.Type))
+				continue
+			case len(name) == 0:
+				name = m.Name
+			}
+			c := Comments(m.CommentLines)
+			field := &MarkdownField{
+				Name:        name,
+				Type:        markdownTypeName(m.Type, doc.Resource.Type.Name.Package),
+				Description: markdownDescription(m.CommentLines),
+			}
+			if c.HasTag("optional") || strings.Contains(tag, ",omitempty") {
+				field.Validation = append(field.Validation, "optional")
+			} else {
+				field.Validation = append(field.Validation, "required")
+			}
+			if listType := c.GetTag("listType", "="); len(listType) > 0 {
+				field.Validation = append(field.Validation, "list type "+listType)
+			}
+			if keys := c.GetTags("listMapKey", "="); len(keys) > 0 {
+				field.Validation = append(field.Validation, "keyed by "+strings.Join(keys, ", "))
+			}
+			field.Validation = append(field.Validation, constraints[prefix+name]...)
+			mt.Fields = append(mt.Fields, field)
+
+			fieldType, fieldPrefix := m.Type, prefix+name+"."
+			nested = append(nested, func() {
+				addMarkdownType(doc, fieldType, fieldPrefix, constraints, visited)
+			})
+		}
+	}
+	addFields(t)
+	for _, add := range nested {
+		add()
+	}
+}
+
+// markdownTypeName returns the Go type of a field, linking the types documented in the same reference
+func markdownTypeName(t *types.Type, pkg string) string {
+	switch t.Kind {
+	case types.Pointer:
+		return markdownTypeName(t.Elem, pkg)
+	case types.Slice, types.Array:
+		return "[]" + markdownTypeName(t.Elem, pkg)
+	case types.Map:
+		return fmt.Sprintf("map[%s]%s", markdownTypeName(t.Key, pkg), markdownTypeName(t.Elem, pkg))
+	case types.Struct:
+		if t.Name.Package == pkg {
+			return fmt.Sprintf("[%s](#%s)", t.Name.Name, strings.ToLower(t.Name.Name))
+		}
+	}
+	if len(t.Name.Package) == 0 {
+		return t.Name.Name
+	}
+	return path.Base(t.Name.Package) + "." + t.Name.Name
+}
+
+// markdownDescription joins the comment lines of a type or field, skipping the "+" comment tags.  Pipes are
+// escaped so the description may be printed in a table.
+func markdownDescription(lines []string) string {
+	description := []string{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if len(line) > 0 && !strings.HasPrefix(line, "+") {
+			description = append(description, strings.Replace(line, "|", "\\|", -1))
+		}
+	}
+	return strings.Join(description, " ")
+}
+
+// markdownExample returns the json example of the "+example" comment of t indented, or an empty string
+// if t has none
+func markdownExample(t *types.Type) string {
+	example := Comments(t.CommentLines).GetTag("example", "=")
+	var b bytes.Buffer
+	if len(example) == 0 || json.Indent(&b, []byte(example), "", "  ") != nil {
+		return example
+	}
+	return b.String()
+}
+
+var MarkdownDocsTemplate = `# {{ .Resource.Kind }}
+
+- Group: ` + "`{{ .Resource.Group }}.{{ .Resource.Domain }}`" + `
+- Version: ` + "`{{ .Resource.Version }}`" + `
+- Resource: ` + "`{{ .Resource.Resource }}`" + `
+{{ range $type := .Types }}
+## {{ $type.Name }}
+{{ if $type.Description }}
+{{ $type.Description }}
+{{ end -}}
+{{ if $type.Example }}
+` + "```json" + `
+{{ $type.Example }}
+` + "```" + `
+{{ end -}}
+{{ if $type.Fields }}
+| Field | Type | Description | Validation |
+| ----- | ---- | ----------- | ---------- |
+{{ range $field := $type.Fields -}}
+| ` + "`{{ $field.Name }}`" + ` | {{ $field.Type }} | {{ $field.Description }} | {{ join $field.Validation ", " }} |
+{{ end -}}
+{{ end -}}
+{{ end -}}
+`
@@ -17,9 +17,16 @@ limitations under the License.
 package generators
 
 import (
+	"bytes"
+	"os"
 	"path"
 	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/gengo/args"
@@ -29,21 +36,165 @@ import (
 	"k8s.io/klog"
 
 	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
 )
 
 // CustomArgs is used tby the go2idl framework to pass args specific to this
 // generator.
-type CustomArgs struct{}
+type CustomArgs struct {
+	// StrictJSONTags fails generation rather than warning when a json tag is not the
+	// lowerCamelCase of its field name
+	StrictJSONTags bool
+	// EmitTests generates test helpers, such as the LoadFixture function of each api group
+	EmitTests bool
+	// EmitTestClients generates a testclient package in each api version package, holding clients issuing
+	// the requests of the tests through the storage of the apiserver
+	EmitTestClients bool
+	// OpenAPIPerVersion generates a GetAllOpenAPIDefinitions function merging the GetOpenAPIDefinitions
+	// function generated by openapi-gen in each api version package
+	OpenAPIPerVersion bool
+	// EmitRegisterAll generates a zz_generated.register.go file in the apis package importing the install
+	// package of every group, with an InstallAll function installing them all with a scheme
+	EmitRegisterAll bool
+	// OutputFileExtension is the extension of the generated files, e.g. .go.tmpl for files post-processed
+	// before their final placement.  Defaults to .go.
+	OutputFileExtension string
+	// SPDXLicense is the SPDX license identifier of the short header written to the generated files in
+	// place of the boilerplate file, e.g. Apache-2.0
+	SPDXLicense string
+	// CopyrightOwner is the copyright owner of the SPDX and default headers of the generated files, the
+	// go header file being used as is
+	CopyrightOwner string
+	// MarkdownDocsDir is the directory the markdown reference of each resource is written to in place of
+	// the generated go files
+	MarkdownDocsDir string
+	// FuzzCorpusDir is the directory the fuzz seed corpus of each resource is written to in place of the
+	// generated go files
+	FuzzCorpusDir string
+	// WebhookManifest is the file the webhook configurations of the "+webhook:runtime" resources are written
+	// to in place of the generated go files
+	WebhookManifest string
+	// FlowSchemaManifest is the file the FlowSchema and the PriorityLevelConfiguration of each group are written
+	// to in place of the generated go files
+	FlowSchemaManifest string
+	// FlowSchemaConcurrencyShares are the assured concurrency shares of the PriorityLevelConfiguration of each
+	// group written to FlowSchemaManifest.  Defaults to 20.
+	FlowSchemaConcurrencyShares int
+	// TypesModuleDir is the directory the versioned type packages are written to as a standalone go module
+	// in place of the generated go files
+	TypesModuleDir string
+	// TypesModulePath is the module path of the types module written to TypesModuleDir
+	TypesModulePath string
+	// CPUProfile is the file a pprof cpu profile of the generation is written to
+	CPUProfile string
+	// MemProfile is the file a pprof heap profile is written to once the generation completes
+	MemProfile string
+	// TemplateOverrides maps generator kinds, e.g. versioned, to the file of a template replacing the
+	// built-in template of the generator.  The built-in templates are used for the kinds not set.
+	TemplateOverrides map[string]string
+	// WriteIfChanged leaves the generated files whose content is unchanged untouched, preserving their
+	// modification times.  Defaults to true.
+	WriteIfChanged bool
+	// OnlyGenerators restricts the generation to the files of the named generator kinds, e.g. conversion
+	// after adding a version, leaving the files of the other kinds untouched.  All the kinds are generated
+	// if empty.
+	OnlyGenerators []string
+	// WriteDocGo adds the "+k8s:openapi-gen=true" and "+groupName" comments to the doc.go of the api
+	// version packages lacking them, creating the missing doc.go files
+	WriteDocGo bool
+	// VerifyDocGo fails the generation, listing the missing comments, if WriteDocGo would change a doc.go.
+	// No doc.go is written.
+	VerifyDocGo bool
+}
+
+// AddFlags adds the generator specific flags to fs
+func (ca *CustomArgs) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&ca.StrictJSONTags, "strict-json-tags", ca.StrictJSONTags,
+		"fail if a json tag is not the lowerCamelCase of its field name and is not marked +json:allowDeviation")
+	fs.BoolVar(&ca.EmitTests, "emit-tests", ca.EmitTests,
+		"generate test helpers, such as a LoadFixture function decoding the testdata fixtures of each api group and a fluent builder of each resource")
+	fs.BoolVar(&ca.EmitTestClients, "emit-test-clients", ca.EmitTestClients,
+		"generate a testclient package in each api version package, with clients of the resources using the storage of the apiserver")
+	fs.BoolVar(&ca.OpenAPIPerVersion, "openapi-per-version", ca.OpenAPIPerVersion,
+		"generate a GetAllOpenAPIDefinitions function merging the OpenAPI definitions generated in each api version package")
+	fs.BoolVar(&ca.EmitRegisterAll, "emit-register-all", ca.EmitRegisterAll,
+		"generate a zz_generated.register.go file in the apis package importing the install package of every group, with an InstallAll function")
+	fs.StringVar(&ca.OutputFileExtension, "output-file-extension", ".go",
+		"extension of the generated files, e.g. .go.tmpl to post-process them before their final placement")
+	fs.StringVar(&ca.SPDXLicense, "spdx-license", ca.SPDXLicense,
+		"SPDX license identifier, e.g. Apache-2.0, of a short header written to the generated files in place of the go header file")
+	fs.StringVar(&ca.CopyrightOwner, "copyright-owner", "The Kubernetes Authors",
+		"copyright owner of the --spdx-license header, and of the default header used when the go header file can not be loaded")
+	fs.StringVar(&ca.MarkdownDocsDir, "markdown-docs-dir", ca.MarkdownDocsDir,
+		"write the markdown reference of each resource to <dir>/<group>/<version>/<resource>.md instead of generating go files")
+	fs.StringVar(&ca.FuzzCorpusDir, "fuzz-corpus-dir", ca.FuzzCorpusDir,
+		"write the fuzz seed corpus of each resource to <dir>/<group>/<version>/<resource>/ instead of generating go files")
+	fs.StringVar(&ca.WebhookManifest, "webhook-manifest", ca.WebhookManifest,
+		"write the mutating and validating webhook configurations of the +webhook:runtime resources to this file instead of generating go files")
+	fs.StringVar(&ca.FlowSchemaManifest, "flow-schema-manifest", ca.FlowSchemaManifest,
+		"write the FlowSchema and PriorityLevelConfiguration classifying the requests of each group to this file instead of generating go files")
+	fs.IntVar(&ca.FlowSchemaConcurrencyShares, "flow-schema-concurrency-shares", 20,
+		"assured concurrency shares of the PriorityLevelConfiguration of each group written to --flow-schema-manifest")
+	fs.StringVar(&ca.TypesModuleDir, "types-module-dir", ca.TypesModuleDir,
+		"write the versioned type packages to this directory as a go module building independently of the apiserver, instead of generating go files")
+	fs.StringVar(&ca.TypesModulePath, "types-module-path", ca.TypesModulePath,
+		"module path of the types module written to --types-module-dir, e.g. example.com/myproject/types")
+	fs.StringVar(&ca.CPUProfile, "cpu-profile", ca.CPUProfile,
+		"write a pprof cpu profile of the generation to this file")
+	fs.StringVar(&ca.MemProfile, "mem-profile", ca.MemProfile,
+		"write a pprof heap profile to this file once the generation completes")
+	fs.StringToStringVar(&ca.TemplateOverrides, "template-override", ca.TemplateOverrides,
+		"replace the built-in template of a generator kind (versioned, conversion, builder, unversioned, install, apis, admission or testclient) with a template file, e.g. versioned=hack/versioned.tmpl")
+	fs.BoolVar(&ca.WriteIfChanged, "write-if-changed", true,
+		"only write the generated files whose content changed, preserving the modification times of the others")
+	fs.StringSliceVar(&ca.OnlyGenerators, "only-generators", ca.OnlyGenerators,
+		"only generate the files of these generator kinds (versioned, conversion, builder, unversioned, install, apis, admission or testclient), e.g. conversion")
+	fs.BoolVar(&ca.WriteDocGo, "write-doc-go", ca.WriteDocGo,
+		"add the +k8s:openapi-gen=true and +groupName comments to the doc.go of the api version packages lacking them, creating the missing doc.go files")
+	fs.BoolVar(&ca.VerifyDocGo, "verify-doc-go", ca.VerifyDocGo,
+		"fail listing the comments --write-doc-go would add to the doc.go of the api version packages, without writing them")
+}
 
 type Gen struct {
 	p []generator.Package
 }
 
+// Execute runs the generation, profiling it if the CPUProfile or MemProfile of the CustomArgs are set.  The
+// profiles do not change the generated files.
 func (g *Gen) Execute(arguments *args.GeneratorArgs) error {
-	return arguments.Execute(
+	ca, _ := arguments.CustomArgs.(*CustomArgs)
+	if ca != nil && len(ca.CPUProfile) > 0 {
+		f, err := os.Create(ca.CPUProfile)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the cpu profile")
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return errors.Wrap(err, "failed to start the cpu profile")
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	if err := arguments.Execute(
 		g.NameSystems(),
 		g.DefaultNameSystem(),
-		g.Packages)
+		g.Packages); err != nil {
+		return err
+	}
+
+	if ca != nil && len(ca.MemProfile) > 0 {
+		f, err := os.Create(ca.MemProfile)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the heap profile")
+		}
+		defer f.Close()
+		// Collect the garbage so the profile reports the live heap
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			return errors.Wrap(err, "failed to write the heap profile")
+		}
+	}
+	return nil
 }
 
 // DefaultNameSystem returns the default name system for ordering the types to be
@@ -85,39 +236,148 @@ func (g *Gen) ParsePackages(context *generator.Context, arguments *args.Generato
 }
 
 func (g *Gen) Packages(context *generator.Context, arguments *args.GeneratorArgs) generator.Packages {
-	boilerplate, err := arguments.LoadGoBoilerplate()
-	if err != nil {
-		klog.Warningf("failed loading boilerplate, fallback to default boilerplate: %v", err)
-		boilerplate = getHeader()
-	}
 	g.p = generator.Packages{}
 
 	b := NewAPIsBuilder(context, arguments)
+	emitTests := false
+	emitTestClients := false
+	openAPIPerVersion := false
+	emitRegisterAll := false
+	extension := ".go"
+	license := ""
+	owner := ""
+	only := sets.NewString()
+	writeDocGo := false
+	verifyDocGo := false
+	if ca, ok := arguments.CustomArgs.(*CustomArgs); ok {
+		writeDocGo = ca.WriteDocGo
+		verifyDocGo = ca.VerifyDocGo
+		emitTests = ca.EmitTests
+		emitTestClients = ca.EmitTestClients
+		openAPIPerVersion = ca.OpenAPIPerVersion
+		emitRegisterAll = ca.EmitRegisterAll
+		if len(ca.OutputFileExtension) > 0 {
+			extension = ca.OutputFileExtension
+		}
+		license = ca.SPDXLicense
+		owner = ca.CopyrightOwner
+		if err := LoadTemplateOverrides(ca.TemplateOverrides); err != nil {
+			klog.Fatalf("%v", err)
+		}
+		for _, kind := range ca.OnlyGenerators {
+			if _, ok := templateKinds[kind]; !ok {
+				klog.Fatalf("unknown generator kind %q of --only-generators, expected one of %s",
+					kind, strings.Join(sets.StringKeySet(templateKinds).List(), ", "))
+			}
+		}
+		only.Insert(ca.OnlyGenerators...)
+		if ca.WriteIfChanged {
+			context.FileTypes[generator.GolangFileType] = writeIfChangedFileType{generator.NewGolangFile()}
+		}
+		if len(ca.MarkdownDocsDir) > 0 {
+			WriteMarkdownDocs(b.APIs, ca.MarkdownDocsDir)
+			return g.p
+		}
+		if len(ca.FuzzCorpusDir) > 0 {
+			WriteFuzzCorpus(b.APIs, ca.FuzzCorpusDir)
+			return g.p
+		}
+		if len(ca.WebhookManifest) > 0 {
+			WriteWebhookManifest(b.APIs, ca.WebhookManifest)
+			return g.p
+		}
+		if len(ca.FlowSchemaManifest) > 0 {
+			WriteFlowSchemaManifest(b.APIs, ca.FlowSchemaManifest, ca.FlowSchemaConcurrencyShares)
+			return g.p
+		}
+		if len(ca.TypesModuleDir) > 0 {
+			WriteTypesModule(b.APIs, ca.TypesModuleDir, ca.TypesModulePath, loadHeader(arguments, ca.SPDXLicense, ca.CopyrightOwner))
+			return g.p
+		}
+	}
+	boilerplate := loadHeader(arguments, license, owner)
+	if !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+	// generates returns true if the files of the kind of generator are generated
+	generates := func(kind string) bool {
+		return only.Len() == 0 || only.Has(kind)
+	}
+	docDrift := []string{}
 	for _, apigroup := range b.APIs.Groups {
 		for _, apiversion := range apigroup.Versions {
-			factory := &packageFactory{apiversion.Pkg.Path, arguments, boilerplate}
-			// Add generators for versioned types
-			gen := CreateVersionedGenerator(apiversion, apigroup, arguments.OutputFileBaseName)
-			g.p = append(g.p, factory.createPackage(gen))
+			factory := &packageFactory{apiversion.Pkg.Path, arguments, boilerplate, extension}
+			if generates("versioned") {
+				dir := filepath.Join(arguments.OutputBase, apiversion.Pkg.Path)
+				if writeDocGo {
+					WriteVersionDoc(apiversion, dir, boilerplate)
+				} else if verifyDocGo {
+					if drift := VerifyVersionDoc(apiversion, dir, boilerplate); len(drift) > 0 {
+						docDrift = append(docDrift, drift)
+					}
+				}
+
+				// Add generators for versioned types
+				gen := CreateVersionedGenerator(apiversion, apigroup, arguments.OutputFileBaseName)
+				g.p = append(g.p, factory.createPackage(gen))
+			}
+
+			if generates("conversion") {
+				gen := CreateConversionGenerator(apiversion, apigroup, arguments.OutputFileBaseName)
+				g.p = append(g.p, factory.createPackage(gen))
+			}
+
+			if generates("builder") && emitTests {
+				gen := CreateBuilderGenerator(apiversion, arguments.OutputFileBaseName)
+				g.p = append(g.p, factory.createPackage(gen))
+			}
+
+			if generates("testclient") && emitTestClients && hasStoredResources(apiversion) {
+				factory := &packageFactory{path.Join(apiversion.Pkg.Path, "testclient"), arguments, boilerplate, extension}
+				gen := CreateTestClientGenerator(apiversion, apigroup, arguments.OutputFileBaseName)
+				g.p = append(g.p, factory.createPackage(gen))
+			}
 		}
 
-		factory := &packageFactory{apigroup.Pkg.Path, arguments, boilerplate}
-		gen := CreateUnversionedGenerator(apigroup, arguments.OutputFileBaseName)
-		g.p = append(g.p, factory.createPackage(gen))
+		if generates("unversioned") {
+			factory := &packageFactory{apigroup.Pkg.Path, arguments, boilerplate, extension}
+			gen := CreateUnversionedGenerator(apigroup, arguments.OutputFileBaseName, emitTests)
+			g.p = append(g.p, factory.createPackage(gen))
+		}
 
-		factory = &packageFactory{path.Join(apigroup.Pkg.Path, "install"), arguments, boilerplate}
-		gen = CreateInstallGenerator(apigroup, arguments.OutputFileBaseName)
-		g.p = append(g.p, factory.createPackage(gen))
+		if generates("install") {
+			factory := &packageFactory{path.Join(apigroup.Pkg.Path, "install"), arguments, boilerplate, extension}
+			gen := CreateInstallGenerator(apigroup, arguments.OutputFileBaseName)
+			g.p = append(g.p, factory.createPackage(gen))
+			if emitTests {
+				gen := CreateConversionMatrixGenerator(apigroup, "zz_generated.conversion_matrix_test")
+				g.p = append(g.p, factory.createPackage(gen))
+			}
+		}
+		g.p = append(g.p, CreateTaggedPackages(apigroup, arguments, boilerplate, extension, emitTestClients, generates)...)
+	}
+	if len(docDrift) > 0 {
+		sort.Strings(docDrift)
+		klog.Fatalf("the doc.go of the api versions are out of date, run with --write-doc-go:\n%s",
+			strings.Join(docDrift, "\n"))
 	}
 
-	apisFactory := &packageFactory{b.APIs.Pkg.Path, arguments, boilerplate}
-	gen := CreateApisGenerator(b.APIs, arguments.OutputFileBaseName)
-	g.p = append(g.p, apisFactory.createPackage(gen))
+	if generates("apis") {
+		apisFactory := &packageFactory{b.APIs.Pkg.Path, arguments, boilerplate, extension}
+		gen := CreateApisGenerator(b.APIs, arguments.OutputFileBaseName, openAPIPerVersion)
+		g.p = append(g.p, apisFactory.createPackage(gen))
+		if emitRegisterAll {
+			g.p = append(g.p, apisFactory.createPackage(CreateRegisterAllGenerator(b.APIs, "zz_generated.register")))
+		}
+		g.p = append(g.p, CreateTaggedAPIsPackages(b.APIs, arguments, boilerplate, extension)...)
+	}
 
-	projectRootPath := filepath.Dir(filepath.Dir(b.APIs.Pkg.Path))
-	admissionFactory := &packageFactory{filepath.Join(projectRootPath, "plugin", "admission", "install"), arguments, boilerplate}
-	admissionGen := CreateAdmissionGenerator(b.APIs, arguments.OutputFileBaseName, projectRootPath, b.arguments.OutputBase)
-	g.p = append(g.p, admissionFactory.createPackage(admissionGen))
+	if generates("admission") {
+		projectRootPath := filepath.Dir(filepath.Dir(b.APIs.Pkg.Path))
+		admissionFactory := &packageFactory{filepath.Join(projectRootPath, "plugin", "admission", "install"), arguments, boilerplate, extension}
+		admissionGen := CreateAdmissionGenerator(b.APIs, arguments.OutputFileBaseName, projectRootPath, b.arguments.OutputBase)
+		g.p = append(g.p, admissionFactory.createPackage(admissionGen))
+	}
 	return g.p
 }
 
@@ -125,11 +385,16 @@ type packageFactory struct {
 	path       string
 	arguments  *args.GeneratorArgs
 	headerText []byte
+	// extension is the extension of the generated file
+	extension string
 }
 
 // Creates a package with a generator
 func (f *packageFactory) createPackage(gen generator.Generator) generator.Package {
 	path := f.path
+	if f.extension != ".go" {
+		gen = &extensionGenerator{gen, f.extension}
+	}
 	name := strings.Split(filepath.Base(f.path), ".")[0]
 	return &generator.DefaultPackage{
 		PackageName: name,
@@ -144,10 +409,48 @@ func (f *packageFactory) createPackage(gen generator.Generator) generator.Packag
 	}
 }
 
-// Returns the header for generated files
-func getHeader() []byte {
+// extensionGenerator writes the file of a generator with an extension other than .go, the package of the
+// file is unchanged
+type extensionGenerator struct {
+	generator.Generator
+	extension string
+}
+
+func (g *extensionGenerator) Filename() string {
+	return strings.TrimSuffix(g.Generator.Filename(), ".go") + g.extension
+}
+
+// loadHeader returns the header of the generated files, the SPDX header of license if set and else the
+// go header file.  The go header file is used as loaded by gengo, which replaces YEAR, as by the other code
+// generators.  YEAR and OWNER in the built-in headers are replaced with the current year and owner.
+func loadHeader(arguments *args.GeneratorArgs, license, owner string) []byte {
+	if len(license) == 0 {
+		boilerplate, err := arguments.LoadGoBoilerplate()
+		if err == nil {
+			return boilerplate
+		}
+		klog.Warningf("failed loading boilerplate, fallback to default boilerplate: %v", err)
+	}
+	if len(owner) == 0 {
+		owner = "The Kubernetes Authors"
+	}
+	header := bytes.Replace(getHeader(license), []byte("YEAR"), []byte(strconv.Itoa(time.Now().UTC().Year())), -1)
+	return bytes.Replace(header, []byte("OWNER"), []byte(owner), -1)
+}
+
+// Returns the header for generated files, the SPDX short header of license or the Apache license header
+// if license is empty
+func getHeader(license string) []byte {
+	if len(license) > 0 {
+		return []byte(`// SPDX-License-Identifier: ` + license + `
+// Copyright YEAR OWNER
+
+// This file was autogenerated by apiregister-gen. Do not edit it manually!
+
+`)
+	}
 	header := []byte(`/*
-Copyright 2017 The Kubernetes Authors.
+Copyright YEAR OWNER
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
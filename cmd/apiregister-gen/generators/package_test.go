@@ -0,0 +1,55 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/gengo/args"
+)
+
+// TestLoadHeader checks the copyright owner is only written to the built-in SPDX header, the go header file
+// keeping its OWNER words
+func TestLoadHeader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "header")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "boilerplate.go.txt")
+	boilerplate := "/*\nCopyright The Innsmouth OWNERS.\n\nSee the OWNERS file of the repository.\n*/\n"
+	if err := ioutil.WriteFile(path, []byte(boilerplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+	arguments := &args.GeneratorArgs{GoHeaderFilePath: path}
+
+	if header := string(loadHeader(arguments, "", "The Esoteric Order of Dagon")); header != boilerplate {
+		t.Errorf("expected the go header file %q as is, got %q", boilerplate, header)
+	}
+
+	header := string(loadHeader(arguments, "Apache-2.0", "The Esoteric Order of Dagon"))
+	expected := fmt.Sprintf("// Copyright %d The Esoteric Order of Dagon\n", time.Now().UTC().Year())
+	if !strings.HasPrefix(header, "// SPDX-License-Identifier: Apache-2.0\n"+expected) {
+		t.Errorf("expected the SPDX header with the copyright line %q, got %q", expected, header)
+	}
+}
@@ -20,13 +20,19 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"reflect"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/gengo/args"
 	"k8s.io/gengo/generator"
+	"k8s.io/gengo/namer"
 	"k8s.io/gengo/types"
 	"k8s.io/klog"
 )
@@ -51,9 +57,28 @@ type APIGroup struct {
 	GroupTitle string
 	// Versions is the list of all versions for this group keyed by name
 	Versions map[string]*APIVersion
+	// VersionPriority is the names of the versions from the most to the least preferred, GA versions before
+	// beta versions before alpha versions and higher numbers first - e.g. v1, v1beta1, v1alpha1
+	VersionPriority []string
 
 	UnversionedResources map[string]*APIResource
 
+	// FeatureGates are the feature gates of the resources of the group mapped to whether they are enabled
+	// by default
+	FeatureGates map[string]bool
+
+	// OpenAPIExamples are the json examples of the "+example" comments of the types of the versions of the
+	// group, keyed by the name of the OpenAPI definition of the type
+	OpenAPIExamples map[string]string
+
+	// OpenAPIUIDescriptors are the "+uiDescriptor" comments of the fields of the types of the versions of the
+	// group, keyed by the name of the OpenAPI definition of the type and the json name of the field
+	OpenAPIUIDescriptors map[string]map[string][]string
+
+	// PreviousGroupName is the name of the "+previousGroupName" comment of the group package - e.g.
+	// mushroomkingdom.example.com
+	PreviousGroupName string
+
 	// Structs is a list of unversioned definitions that must be generated
 	Structs []*Struct
 	Aliases map[string]*Alias
@@ -87,6 +112,8 @@ type Field struct {
 	// For versioned Kubernetes types, this is the unversioned package
 	UnversionedImport string
 	UnversionedType   string
+	// For map fields, these are the imports required by the key and element types
+	UnversionedImports []string
 }
 
 type APIVersion struct {
@@ -100,6 +127,17 @@ type APIVersion struct {
 	Resources map[string]*APIResource
 	// Pkg is the Package object from code-gen
 	Pkg *types.Package
+	// PreviousGroupName is the name the group was renamed from, its types are also registered under it
+	PreviousGroupName string
+	// PointerSliceElems are the names of the structs of the version which are the elements of []*T fields of
+	// its resources, sorted
+	PointerSliceElems []string
+	// FieldConversions are the structs of the version whose fields differ from the fields of their unversioned
+	// structs, sorted by name
+	FieldConversions []*FieldConversion
+	// MapConversions are the map fields of the structs of the version whose elements differ from the elements
+	// of the fields of their unversioned structs
+	MapConversions []*MapConversion
 }
 
 type APIResource struct {
@@ -129,6 +167,320 @@ type APIResource struct {
 	StatusStrategy string
 	// NonNamespaced indicates that the resource kind is non namespaced
 	NonNamespaced bool
+	// Singleton is the only name allowed for instances of the resource - e.g. cluster
+	// This field is optional and set by the "+singleton=" comment.
+	Singleton string
+	// HasSelector indicates that the resource Spec has a "Selector *metav1.LabelSelector" field
+	HasSelector bool
+	// PrintColumns are the additional columns printed by "kubectl get"
+	// This field is optional and set by "+resource:printColumn=" comments.
+	PrintColumns []*PrintColumn
+	// StorageMediaType is the media type used to store the resource in etcd - e.g. application/vnd.kubernetes.protobuf
+	// This field is optional and set by the "+resource:storageMediaType=" or "+storageMediaType=" comment.
+	// Defaults to the --storage-media-type of the apiserver.
+	StorageMediaType string
+	// StatusStorageMediaType is the media type used to store the objects written through the status subresource
+	// in etcd, e.g. to encode large statuses with a faster codec than the spec
+	// This field is optional and set by the "+subresource:status:storageMediaType=" comment.
+	// Defaults to the StorageMediaType.
+	StatusStorageMediaType string
+	// Indexes are the cache indexes of the resource by field value
+	// This field is optional and set by "+index=" comments.
+	Indexes []*Index
+	// OrphanDependents indicates that deletes of the resource orphan its dependents rather than
+	// deleting them unless the delete sets a propagation policy
+	// This field is optional and set by the "+resource:enableGarbageCollection=false" comment.
+	OrphanDependents bool
+	// ConversionWebhookFallback indicates that the fields not mapped by the generated conversion of the
+	// versioned resource to the internal resource are converted by the builders.ConversionWebhook
+	// This field is optional and set by the "+conversion:webhookFallback" comment.
+	ConversionWebhookFallback bool
+	// AnnotationConversion is the name of the function of the versioned package migrating the annotations
+	// of the resource after the generated conversion to and from the internal resource
+	// This field is optional and set by the "+annotationConversion=" comment.
+	AnnotationConversion string
+	// InterfaceFields describe the fields of the resource holding values of an interface type, which the
+	// generated conversions do not convert
+	InterfaceFields []string
+	// FeatureGate is the name of the feature gate the resource is served behind
+	// This field is optional and set by the "+resource:featureGate=" comment.
+	FeatureGate string
+	// FeatureGateDefault indicates that the FeatureGate is a beta feature enabled by default rather
+	// than an alpha feature disabled by default
+	// This field is optional and set by the "+resource:featureGate=<name>,default=true" comment.
+	FeatureGateDefault bool
+	// FieldConstraints are the cross-field constraints validated for the resource
+	// This field is optional and set by "+resource:oneOf=" and "+resource:allOrNone=" comments.
+	FieldConstraints []*FieldConstraint
+	// ValidatingAdmission are the names of the functions of the group package validating creates and updates
+	// of the resource in admission
+	// This field is optional and set by "+admission:validating=" comments.
+	ValidatingAdmission []string
+	// ObservedGeneration is the Go expression of the status.observedGeneration field of an object "o" of the
+	// resource, set to the generation of the object on status updates
+	// This field is optional and set by the "+status:observedGeneration" comment.
+	ObservedGeneration string
+	// Conditions are the types of the status conditions of the resource whose typed accessors are generated
+	// This field is optional and set by "+condition=" comments.
+	Conditions []*Condition
+	// EnumFields are the fields of the resource holding values of "+enum" types, validated by the
+	// ValidateEnums method generated for the versioned type
+	EnumFields []*EnumField
+	// EnumVersions are the versions of the unversioned resource with EnumFields, in which the created and
+	// updated objects are validated
+	EnumVersions []string
+	// EmbeddedObjectFields are the "+embeddedObject" RawExtension fields of the resource, whose objects the
+	// conversions to the internal resource decode and the conversions from it encode
+	EmbeddedObjectFields []*EmbeddedObjectField
+	// FieldDocs are the descriptions of the fields of the resource keyed by their dotted json path
+	FieldDocs []*FieldDoc
+	// RemovedFields are the fields of the resource cleared from the created, updated and read objects
+	// This field is optional and set by "+removedField=" comments.
+	RemovedFields []*RemovedField
+	// ReadOnlyFields are the fields of the resource cleared from the created objects and kept from the stored
+	// objects on updates.  This field is optional and set by "+readOnly" comments of the fields.
+	ReadOnlyFields []*ReadOnlyField
+	// RangeDefaults are the integer fields of the resource clamped into a range on create
+	// This field is optional and set by "+resource:rangeDefault=" comments.
+	RangeDefaults []*RangeDefault
+	// DefaultOnRead applies the defaults of the preferred version of the resource to the objects read from
+	// storage, e.g. to objects stored before the introduction of a defaulted field
+	// This field is optional and set by the "+resource:defaultOnRead" comment.
+	DefaultOnRead bool
+	// StampUser annotates the objects of the resource with the users creating and last updating them
+	// This field is optional and set by the "+resource:stampUser" comment.
+	StampUser bool
+	// GenerateNameRequired requires the created objects of the resource to have a generateName, their names are
+	// always generated from it
+	// This field is optional and set by the "+resource:generateNameRequired" comment.
+	GenerateNameRequired bool
+	// UTCTimes are the Go statements converting the times of an unversioned object "o" of the resource to UTC
+	// on create and update
+	// This field is optional and set by the "+resource:utcTimes" comment.
+	UTCTimes []string
+	// RetainConversion keeps the type of a version removed from serving the resource registered with the
+	// scheme along with its conversions, so the objects stored in the version can still be read and migrated
+	// This field is optional and set by the "+retainConversion" comment.
+	RetainConversion bool
+	// RuntimeWebhook generates the Default and Validate methods of the controller-runtime webhook interfaces
+	// on the versioned type, defaulting with the scheme and validating with the storage of the resource
+	// This field is optional and set by the "+webhook:runtime" comment.
+	RuntimeWebhook bool
+	// WebhookMatchConditions are the CEL expressions the admission requests must match to be sent to the
+	// controller-runtime webhooks of the resource, written to the webhook manifest
+	// This field is optional and set by "+webhook:matchCondition=" comments.
+	WebhookMatchConditions []*WebhookMatchCondition
+	// XValidations are the CEL rules of the versions of the resource validated for the unversioned resource
+	// This field is optional and set by "+kubebuilder:validation:XValidation" comments.
+	XValidations []*XValidation
+	// MetricsLabels are the labels of the gauge counting the objects of the resource, read from each object
+	// This field is optional and set by "+metrics:label=" comments.
+	MetricsLabels []*MetricsLabel
+	// NameValidation is the name of the function of the group package validating the names of the objects,
+	// apivalidation.NameIsDNSSubdomain if unset
+	// This field is optional and set by the "+resource:nameValidation=" comment.
+	NameValidation string
+	// NameRegex is the regex the names of the objects must match, in addition to NameValidation
+	// This field is optional and set by the "+resource:nameRegex=" comment.
+	NameRegex string
+	// CustomMetrics are the metrics of the objects of the resource served by the custom metrics API
+	// This field is optional and set by "+metric=" comments.
+	CustomMetrics []*CustomMetric
+	// BuildTag is the build tag constraining the generated files serving the resource, so the resource is only
+	// served by the apiserver built with the tag
+	// This field is optional and set by the "+buildTag=" comment.
+	BuildTag string
+}
+
+// StorageBuilder returns the expression constructing the StorageBuilder of the resource, wrapping its
+// strategy for each of the storage comments of the resource
+func (r *APIResource) StorageBuilder() string {
+	s := fmt.Sprintf("&%s{builders.StorageStrategySingleton}", r.Strategy)
+	if len(r.Singleton) > 0 {
+		s = fmt.Sprintf("builders.NewSingletonStorageStrategy(%q, %s)", r.Singleton, s)
+	}
+	if len(r.FieldConstraints) > 0 {
+		s = fmt.Sprintf("builders.NewFieldConstraintStorageStrategy(%s, %sFieldConstraints...)", s, r.Kind)
+	}
+	nameFunc := r.NameValidation
+	if len(nameFunc) == 0 {
+		nameFunc = "apivalidation.NameIsDNSSubdomain"
+	}
+	if len(r.NameRegex) > 0 {
+		nameFunc = fmt.Sprintf("builders.NameMatchesRegex(%q, %s)", r.NameRegex, nameFunc)
+	}
+	s = fmt.Sprintf("builders.NewObjectMetaValidationStorageStrategy(%s, %s)", s, nameFunc)
+	if r.DefaultOnRead {
+		s = fmt.Sprintf("builders.NewDefaultOnReadStorageStrategy(%s)", s)
+	}
+	if r.StampUser {
+		s = fmt.Sprintf("builders.NewStampUserStorageStrategy(%q, %s)", r.Group+"."+r.Domain, s)
+	}
+	if r.GenerateNameRequired {
+		s = fmt.Sprintf("builders.NewGenerateNameStorageStrategy(%s)", s)
+	}
+	if len(r.UTCTimes) > 0 {
+		s = fmt.Sprintf("builders.NewUTCTimesStorageStrategy(%s, Normalize%sTimes)", s, r.Kind)
+	}
+	if len(r.XValidations) > 0 {
+		s = fmt.Sprintf("builders.NewCELValidationStorageStrategy(%s, %sCELValidators...)", s, r.Kind)
+	}
+	if len(r.EnumVersions) > 0 {
+		versions := []string{}
+		for _, version := range r.EnumVersions {
+			versions = append(versions, fmt.Sprintf("schema.GroupVersion{Group: %q, Version: %q}", r.Group+"."+r.Domain, version))
+		}
+		s = fmt.Sprintf("builders.NewEnumValidationStorageStrategy(%s, %s)", s, strings.Join(versions, ", "))
+	}
+	if len(r.RemovedFields) > 0 {
+		s = fmt.Sprintf("builders.NewRemovedFieldStorageStrategy(%s, %sRemovedFields...)", s, r.Kind)
+	}
+	if len(r.ReadOnlyFields) > 0 {
+		s = fmt.Sprintf("builders.NewReadOnlyFieldStorageStrategy(%s, %sReadOnlyFields...)", s, r.Kind)
+	}
+	if len(r.RangeDefaults) > 0 {
+		s = fmt.Sprintf("builders.NewRangeDefaultStorageStrategy(%s, %sRangeDefaults...)", s, r.Kind)
+	}
+	if len(r.PrintColumns) > 0 {
+		s = fmt.Sprintf("builders.NewPrintColumnStorageStrategy(%s, %sPrintColumns...)", s, r.Kind)
+	}
+	if len(r.StorageMediaType) > 0 {
+		s = fmt.Sprintf("builders.NewStorageMediaTypeStorageStrategy(%q, %s)", r.StorageMediaType, s)
+	}
+	// The delete strategy of the store is the outermost StorageBuilder, so this must wrap the others
+	if r.OrphanDependents {
+		s = fmt.Sprintf("builders.NewGCPolicyStorageStrategy(DefaultGCPolicy, Resource(%q), %s)", r.Resource, s)
+	}
+	return s
+}
+
+// StatusStorageBuilder returns the expression constructing the StorageBuilder of the status subresource of the
+// resource in its versioned package, wrapping its status strategy for each of the status comments of the resource
+func (r *APIResource) StatusStorageBuilder() string {
+	s := fmt.Sprintf("&%s.%s{DefaultStatusStorageStrategy: builders.StatusStorageStrategySingleton}",
+		r.Group, r.StatusStrategy)
+	if len(r.ObservedGeneration) > 0 {
+		s = fmt.Sprintf("builders.NewObservedGenerationStorageStrategy(%s, %s.Set%sObservedGeneration)",
+			s, r.Group, r.Kind)
+	}
+	if len(r.StatusStorageMediaType) > 0 {
+		s = fmt.Sprintf("builders.NewStorageMediaTypeStorageStrategy(%q, %s)", r.StatusStorageMediaType, s)
+	}
+	return s
+}
+
+// PrintColumn is an additional column printed by "kubectl get" for a resource
+type PrintColumn struct {
+	// Name is the column header - e.g. Phase
+	Name string
+	// Type is the OpenAPI type of the column - e.g. string
+	Type string
+	// Format is the optional OpenAPI format of the column
+	Format string
+	// Description is the optional human readable description of the column
+	Description string
+	// Priority is the importance of the column, columns with a priority greater than 0 are only
+	// printed in wide output
+	Priority int
+	// JSONPath is the path of the column value in the versioned object - e.g. .status.phase
+	JSONPath string
+
+	// Guards are the Go conditions under which the JSONPath does not resolve for the unversioned object o
+	Guards []string
+	// Value is the Go expression of the column value for the unversioned object o
+	Value string
+}
+
+// FieldConstraint is a cross-field constraint of a resource
+type FieldConstraint struct {
+	// Rule is the name of the builders.FieldConstraintRule of the constraint - e.g. OneOf
+	Rule string
+	// Fields are the constrained fields
+	Fields []*ConstrainedField
+}
+
+// ConstrainedField is a field of a FieldConstraint
+type ConstrainedField struct {
+	// Path is the path of the field - e.g. spec.hall
+	Path string
+	// Guards are the Go conditions under which a parent of the field is not set for the unversioned object o
+	Guards []string
+	// Value is the Go expression of the field for the unversioned object o
+	Value string
+}
+
+// RemovedField is a field removed from a resource, which is kept in the types so the values of the objects
+// stored before its removal are cleared
+type RemovedField struct {
+	// Path is the path of the field - e.g. spec.oldThing
+	Path string
+	// Guards are the Go conditions under which a parent of the field is not set for the unversioned object o
+	Guards []string
+	// Field is the Go expression of the field for the unversioned object o
+	Field string
+}
+
+// RangeDefault is an integer field of a resource clamped into the [Min, Max] range on create, set to Min if it
+// is an unset pointer
+type RangeDefault struct {
+	// Path is the path of the field - e.g. spec.replicas
+	Path string
+	// Guards are the Go conditions under which a parent of the field is not set for the unversioned object o
+	Guards []string
+	// Field is the Go expression of the field for the unversioned object o
+	Field string
+	// Min and Max are the bounds of the range
+	Min, Max int64
+}
+
+// Index is a cache index of the objects of a resource by the value of a field
+type Index struct {
+	// Name is the name of the index, the path of the indexed field - e.g. spec.nodeName
+	Name string
+	// Suffix is the name of the indexed field in the names of the generated index functions - e.g. SpecNodeName
+	Suffix string
+
+	// Guards are the Go conditions under which the path does not resolve for the versioned object o
+	Guards []string
+	// Value is the Go expression of the indexed value for the versioned object o
+	Value string
+}
+
+// MetricsLabel is a label of the gauge of the objects of a resource
+type MetricsLabel struct {
+	// Name is the name of the label, the last field of the path - e.g. tier
+	Name string
+	// Path is the path of the field read into the label - e.g. spec.tier
+	Path string
+
+	// Guards are the Go conditions under which the path does not resolve for the unversioned object o
+	Guards []string
+	// Value is the Go expression of the label value for the unversioned object o
+	Value string
+}
+
+// Condition is a type of the status conditions of a resource, read and written by the generated accessors
+type Condition struct {
+	// Name is the type of the condition, in the names of the accessors - e.g. Ready
+	Name string
+	// Type is the name of the struct of the conditions in the versioned package - e.g. Condition
+	Type string
+	// Conditions is the Go expression of the status conditions for the versioned object o
+	Conditions string
+}
+
+// CustomMetric is a metric of the objects of a resource read from a numeric status field, served by the
+// custom.metrics.k8s.io API for the HorizontalPodAutoscalers
+type CustomMetric struct {
+	// Name is the name of the metric - e.g. fish
+	Name string
+	// Path is the path of the field read into the metric - e.g. status.actual_fish
+	Path string
+
+	// Guards are the Go conditions under which the path does not resolve for the unversioned object o
+	Guards []string
+	// Value is the Go expression of the resource.Quantity of the metric for the unversioned object o
+	Value string
 }
 
 type APISubresource struct {
@@ -205,6 +557,9 @@ func (b *APIsBuilder) ParseAPIs() {
 			Domain:               b.Domain,
 			Versions:             map[string]*APIVersion{},
 			UnversionedResources: map[string]*APIResource{},
+			FeatureGates:         map[string]bool{},
+			OpenAPIExamples:      map[string]string{},
+			OpenAPIUIDescriptors: map[string]map[string][]string{},
 			Aliases:              map[string]*Alias{},
 		}
 
@@ -229,6 +584,43 @@ func (b *APIsBuilder) ParseAPIs() {
 					Strategy:       resource.Strategy,
 					NonNamespaced:  resource.NonNamespaced,
 					ShortName:      resource.ShortName,
+					Singleton:      resource.Singleton,
+					HasSelector:    resource.HasSelector,
+					PrintColumns:   resource.PrintColumns,
+
+					StorageMediaType:       resource.StorageMediaType,
+					StatusStorageMediaType: resource.StatusStorageMediaType,
+					Indexes:                resource.Indexes,
+					OrphanDependents:       resource.OrphanDependents,
+
+					InterfaceFields:    resource.InterfaceFields,
+					FeatureGate:        resource.FeatureGate,
+					FeatureGateDefault: resource.FeatureGateDefault,
+
+					ConversionWebhookFallback: resource.ConversionWebhookFallback,
+					AnnotationConversion:      resource.AnnotationConversion,
+					FieldConstraints:          resource.FieldConstraints,
+					ValidatingAdmission:       resource.ValidatingAdmission,
+					ObservedGeneration:        resource.ObservedGeneration,
+					Conditions:                resource.Conditions,
+					EnumFields:                resource.EnumFields,
+					EmbeddedObjectFields:      resource.EmbeddedObjectFields,
+					FieldDocs:                 resource.FieldDocs,
+					RemovedFields:             resource.RemovedFields,
+					ReadOnlyFields:            resource.ReadOnlyFields,
+					RangeDefaults:             resource.RangeDefaults,
+					DefaultOnRead:             resource.DefaultOnRead,
+					StampUser:                 resource.StampUser,
+					GenerateNameRequired:      resource.GenerateNameRequired,
+					UTCTimes:                  resource.UTCTimes,
+					RuntimeWebhook:            resource.RuntimeWebhook,
+					WebhookMatchConditions:    resource.WebhookMatchConditions,
+					RetainConversion:          resource.RetainConversion,
+					MetricsLabels:             resource.MetricsLabels,
+					NameValidation:            resource.NameValidation,
+					NameRegex:                 resource.NameRegex,
+					CustomMetrics:             resource.CustomMetrics,
+					BuildTag:                  resource.BuildTag,
 				}
 				apiVersion.Resources[kind] = apiResource
 				// Set the package for the api version
@@ -237,18 +629,177 @@ func (b *APIsBuilder) ParseAPIs() {
 				apiGroup.Pkg = b.context.Universe[filepath.Dir(resource.Type.Name.Package)]
 				apiGroup.PkgPath = apiGroup.Pkg.Path
 
+				if previous, found := apiGroup.UnversionedResources[kind]; found && previous.BuildTag != resource.BuildTag {
+					klog.Fatalf("// +buildTag=%s of type %v conflicts with the build tag %q of another version of %s",
+						resource.BuildTag, resource.Type.Name, previous.BuildTag, kind)
+				}
 				apiGroup.UnversionedResources[kind] = apiResource
+
+				if len(resource.FeatureGate) > 0 {
+					if enabled, found := apiGroup.FeatureGates[resource.FeatureGate]; found && enabled != resource.FeatureGateDefault {
+						klog.Fatalf("// +resource:featureGate=%s of type %v conflicts with the default of the feature "+
+							"gate of another resource of the %s group", resource.FeatureGate, resource.Type.Name, group)
+					}
+					apiGroup.FeatureGates[resource.FeatureGate] = resource.FeatureGateDefault
+				}
 			}
 
+			elems := sets.NewString()
+			for _, resource := range apiVersion.Resources {
+				elems.Insert(PointerSliceElems(resource.Type)...)
+			}
+			apiVersion.PointerSliceElems = elems.List()
+
+			b.ParseExamples(apiGroup, apiVersion)
+			b.ParseUIDescriptors(apiGroup, apiVersion)
 			apiGroup.Versions[version] = apiVersion
+			apiGroup.VersionPriority = append(apiGroup.VersionPriority, version)
 		}
+		sort.Slice(apiGroup.VersionPriority, func(i, j int) bool {
+			return version.CompareKubeAwareVersionStrings(apiGroup.VersionPriority[i], apiGroup.VersionPriority[j]) > 0
+		})
+		b.ParseRetainedConversions(apiGroup)
+		b.ParseXValidations(apiGroup)
+		b.ParseEnumVersions(apiGroup)
 		b.ParseStructsAndAliases(apiGroup)
+		b.ParseFieldConversions(apiGroup)
+		b.ParsePreviousGroupName(apiGroup)
 		apis.Groups[group] = apiGroup
 	}
 	apis.Pkg = b.context.Universe[b.APIsPkg]
 	b.APIs = apis
 }
 
+// ParseRetainedConversions checks the resources of group with a "+retainConversion" comment, which are not
+// served by their version, are served by another version and that their version is not the most preferred
+// version of their kind, in which their objects are stored
+func (b *APIsBuilder) ParseRetainedConversions(group *APIGroup) {
+	for _, version := range group.VersionPriority {
+		for kind, resource := range group.Versions[version].Resources {
+			if !resource.RetainConversion {
+				continue
+			}
+			if len(resource.BuildTag) > 0 {
+				klog.Fatalf("// +retainConversion of type %v conflicts with its build tag %q", resource.Type.Name,
+					resource.BuildTag)
+			}
+			preferred, served := "", false
+			for _, other := range group.VersionPriority {
+				if r, found := group.Versions[other].Resources[kind]; found {
+					if len(preferred) == 0 {
+						preferred = other
+					}
+					served = served || !r.RetainConversion
+				}
+			}
+			if preferred == version {
+				klog.Fatalf("// +retainConversion of type %v requires a version of %s preferred over %s, in which "+
+					"the objects are stored", resource.Type.Name, kind, version)
+			}
+			if !served {
+				klog.Fatalf("// +retainConversion of type %v requires another version of group %s serving %s",
+					resource.Type.Name, group.Group, kind)
+			}
+		}
+	}
+}
+
+// ParseExamples adds the json examples of the "+example" comments of the types of version to the
+// OpenAPIExamples of group, failing if an example does not decode into its type
+func (b *APIsBuilder) ParseExamples(group *APIGroup, version *APIVersion) {
+	for _, t := range version.Pkg.Types {
+		example := Comments(t.CommentLines).GetTag("example", "=")
+		if len(example) == 0 {
+			continue
+		}
+		if err := CheckExample(t, example); err != nil {
+			klog.Fatalf("// +example of type %v must be a json example of the type: %v", t.Name, err)
+		}
+		if !HasOpenAPIDefinition(version.Pkg, t, b.writesDocGo()) {
+			klog.Warningf("%v has a +example comment but no OpenAPI definition, add // +k8s:openapi-gen=true "+
+				"to %v or its package to serve the example", t.Name, t.Name.Name)
+		}
+		group.OpenAPIExamples[t.Name.String()] = example
+	}
+}
+
+// ParseUIDescriptors adds the descriptors of the "+uiDescriptor" comments of the fields of the types of version
+// to the OpenAPIUIDescriptors of group, failing if a field has no json name
+func (b *APIsBuilder) ParseUIDescriptors(group *APIGroup, version *APIVersion) {
+	for _, t := range version.Pkg.Types {
+		if t.Kind != types.Struct {
+			continue
+		}
+		fields := map[string][]string{}
+		for _, m := range t.Members {
+			descriptors := Comments(m.CommentLines).GetTags("uiDescriptor", "=")
+			if len(descriptors) == 0 {
+				continue
+			}
+			name := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+			if len(name) == 0 || name == "-" {
+				klog.Fatalf("// +uiDescriptor of field %v.%s requires the field to have a json name", t.Name, m.Name)
+			}
+			fields[name] = descriptors
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		if !HasOpenAPIDefinition(version.Pkg, t, b.writesDocGo()) {
+			klog.Warningf("%v has +uiDescriptor comments but no OpenAPI definition, add // +k8s:openapi-gen=true "+
+				"to %v or its package to serve the descriptors", t.Name, t.Name.Name)
+		}
+		group.OpenAPIUIDescriptors[t.Name.String()] = fields
+	}
+}
+
+// ParseXValidations sets the XValidations of the unversioned resources of group to the CEL rules declared
+// by the types of each of their versions, in the order of the version priority
+func (b *APIsBuilder) ParseXValidations(group *APIGroup) {
+	for _, version := range group.VersionPriority {
+		for kind, resource := range group.Versions[version].Resources {
+			if rules := XValidationRules(resource.Type); len(rules) > 0 {
+				unversioned := group.UnversionedResources[kind]
+				unversioned.XValidations = append(unversioned.XValidations, &XValidation{Version: version, Rules: rules})
+			}
+		}
+	}
+}
+
+// ParseEnumVersions sets the EnumVersions of the unversioned resources of group to the versions declaring fields
+// of "+enum" types, in the order of the version priority
+func (b *APIsBuilder) ParseEnumVersions(group *APIGroup) {
+	for _, version := range group.VersionPriority {
+		for kind, resource := range group.Versions[version].Resources {
+			if len(resource.EnumFields) > 0 {
+				unversioned := group.UnversionedResources[kind]
+				unversioned.EnumVersions = append(unversioned.EnumVersions, version)
+			}
+		}
+	}
+}
+
+// ParsePreviousGroupName sets the PreviousGroupName of group and its versions from the "+previousGroupName"
+// comment of the group package
+func (b *APIsBuilder) ParsePreviousGroupName(group *APIGroup) {
+	c := Comments(append(append([]string{}, group.Pkg.Comments...), group.Pkg.DocComments...))
+	name := c.GetTag("previousGroupName", "=")
+	if len(name) == 0 {
+		return
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		klog.Fatalf("// +previousGroupName of package %s must be a group name: %s", group.PkgPath, strings.Join(errs, ", "))
+	}
+	if name == group.Group+"."+group.Domain {
+		klog.Fatalf("// +previousGroupName of package %s must differ from the name of the group %s.%s",
+			group.PkgPath, group.Group, group.Domain)
+	}
+	group.PreviousGroupName = name
+	for _, version := range group.Versions {
+		version.PreviousGroupName = name
+	}
+}
+
 // ParseIndex indexes all types with the comment "// +resource=RESOURCE" by GroupVersionKind and
 // GroupKindVersion
 func (b *APIsBuilder) ParseIndex() {
@@ -256,6 +807,8 @@ func (b *APIsBuilder) ParseIndex() {
 	b.ByGroupKindVersion = map[string]map[string]map[string]*APIResource{}
 
 	b.SubByGroupVersionKind = map[string]map[string]map[string]*types.Type{}
+	deviations := []string{}
+	listMapKeyErrors := []string{}
 	for _, c := range b.context.Order {
 		if IsAPISubresource(c) {
 			group := GetGroup(c)
@@ -282,6 +835,17 @@ func (b *APIsBuilder) ParseIndex() {
 		r.Version = GetVersion(c, r.Group)
 		r.Kind = GetKind(c, r.Group)
 		r.Domain = b.Domain
+		deviations = append(deviations, JSONTagDeviations(c)...)
+		listMapKeyErrors = append(listMapKeyErrors, ListMapKeyErrors(c)...)
+		r.InterfaceFields = InterfaceFields(c)
+		r.EnumFields = EnumFields(b.context.Universe, c)
+		r.EmbeddedObjectFields = EmbeddedObjectFields(c)
+		r.ReadOnlyFields = ReadOnlyFields(c)
+		r.FieldDocs = FieldDocs(c)
+		for _, field := range r.InterfaceFields {
+			klog.Warningf("%s, which the generated conversions share between the converted objects, "+
+				"convert the field in a conversion function of the enclosing type", field)
+		}
 
 		rt := ParseResourceTag(b.GetResourceTag(c))
 
@@ -290,6 +854,130 @@ func (b *APIsBuilder) ParseIndex() {
 		r.ShortName = rt.ShortName
 
 		r.Strategy = rt.Strategy
+		r.Singleton = Comments(c.CommentLines).GetTag("singleton", "=")
+		r.HasSelector = HasSpecSelector(c)
+		for _, tag := range Comments(c.CommentLines).GetTags("resource:printColumn", "=") {
+			r.PrintColumns = append(r.PrintColumns, ParsePrintColumnTag(c, tag))
+		}
+		for _, tag := range Comments(c.CommentLines).GetTags("resource:oneOf", "=") {
+			r.FieldConstraints = append(r.FieldConstraints, ParseFieldConstraintTag(c, "oneOf", tag))
+		}
+		for _, tag := range Comments(c.CommentLines).GetTags("resource:allOrNone", "=") {
+			r.FieldConstraints = append(r.FieldConstraints, ParseFieldConstraintTag(c, "allOrNone", tag))
+		}
+		for _, tag := range Comments(c.CommentLines).GetTags("index", "=") {
+			r.Indexes = append(r.Indexes, ParseIndexTag(c, tag))
+		}
+		for _, tag := range Comments(c.CommentLines).GetTags("metrics:label", "=") {
+			r.MetricsLabels = append(r.MetricsLabels, ParseMetricsLabelTag(c, r.MetricsLabels, tag))
+		}
+		for _, tag := range Comments(c.CommentLines).GetTags("metric", "=") {
+			r.CustomMetrics = append(r.CustomMetrics, ParseCustomMetricTag(c, r.CustomMetrics, tag))
+		}
+		for _, tag := range Comments(c.CommentLines).GetTags("admission:validating", "=") {
+			r.ValidatingAdmission = append(r.ValidatingAdmission, ParseAdmissionTag(b.context.Universe, c, tag))
+		}
+		for _, tag := range Comments(c.CommentLines).GetTags("removedField", "=") {
+			r.RemovedFields = append(r.RemovedFields, ParseRemovedFieldTag(c, tag))
+		}
+		for _, tag := range Comments(c.CommentLines).GetTags("resource:rangeDefault", "=") {
+			r.RangeDefaults = append(r.RangeDefaults, ParseRangeDefaultTag(c, tag))
+		}
+		for _, tag := range Comments(c.CommentLines).GetTags("condition", "=") {
+			r.Conditions = append(r.Conditions, ParseConditionTag(c, r.Conditions, tag))
+		}
+		if Comments(c.CommentLines).HasTag("status:observedGeneration") {
+			r.ObservedGeneration = ParseObservedGenerationTag(c)
+		}
+		if tag := Comments(c.CommentLines).GetTag("resource:nameValidation", "="); len(tag) > 0 {
+			r.NameValidation = ParseNameValidationTag(b.context.Universe, c, tag)
+		}
+		if tag := Comments(c.CommentLines).GetTag("resource:nameRegex", "="); len(tag) > 0 {
+			if _, err := regexp.Compile(tag); err != nil {
+				klog.Fatalf("// +resource:nameRegex=%s of type %v must be a valid regex: %v", tag, c.Name, err)
+			}
+			r.NameRegex = tag
+		}
+		if tag := Comments(c.CommentLines).GetTag("buildTag", "="); len(tag) > 0 {
+			if !buildTag.MatchString(tag) {
+				klog.Fatalf("// +buildTag=%s of type %v must be a single build tag of letters, digits, _ and .", tag, c.Name)
+			}
+			r.BuildTag = tag
+		}
+		r.ConversionWebhookFallback = Comments(c.CommentLines).HasTag("conversion:webhookFallback")
+		r.DefaultOnRead = Comments(c.CommentLines).HasTag("resource:defaultOnRead")
+		r.StampUser = Comments(c.CommentLines).HasTag("resource:stampUser")
+		r.GenerateNameRequired = Comments(c.CommentLines).HasTag("resource:generateNameRequired")
+		if Comments(c.CommentLines).HasTag("resource:utcTimes") {
+			r.UTCTimes = ParseUTCTimesTag(c)
+		}
+		r.RetainConversion = Comments(c.CommentLines).HasTag("retainConversion")
+		r.RuntimeWebhook = Comments(c.CommentLines).HasTag("webhook:runtime")
+		if r.RuntimeWebhook && len(r.REST) > 0 {
+			klog.Fatalf("// +webhook:runtime of type %v requires the storage of the apiserver, not the REST %s",
+				c.Name, r.REST)
+		}
+		for _, tag := range Comments(c.CommentLines).GetTags("webhook:matchCondition", "=") {
+			if !r.RuntimeWebhook {
+				klog.Fatalf("// +webhook:matchCondition=%s of type %v requires a +webhook:runtime comment", tag, c.Name)
+			}
+			r.WebhookMatchConditions = append(r.WebhookMatchConditions, ParseWebhookMatchConditionTag(c, r.WebhookMatchConditions, tag))
+		}
+		if tag := Comments(c.CommentLines).GetTag("annotationConversion", "="); len(tag) > 0 {
+			r.AnnotationConversion = ParseAnnotationConversionTag(b.context.Universe, c, tag)
+		}
+		switch gc := Comments(c.CommentLines).GetTag("resource:enableGarbageCollection", "="); gc {
+		case "", "true":
+		case "false":
+			r.OrphanDependents = true
+		default:
+			klog.Fatalf("// +resource:enableGarbageCollection must be true or false for type %v.  Got string: [%s]",
+				c.Name, gc)
+		}
+		if tag := Comments(c.CommentLines).GetTag("resource:featureGate", "="); len(tag) > 0 {
+			r.FeatureGate, r.FeatureGateDefault = ParseFeatureGateTag(c, tag)
+		}
+		r.StorageMediaType = Comments(c.CommentLines).GetTag("resource:storageMediaType", "=")
+		if len(r.StorageMediaType) == 0 {
+			r.StorageMediaType = Comments(c.CommentLines).GetTag("storageMediaType", "=")
+		}
+		// Resources with custom json marshaling are detected from their methods, the comment makes it explicit
+		customMarshal := len(CustomJSONMarshalers(c)) > 0
+		if Comments(c.CommentLines).HasTag("resource:customMarshal") {
+			if !customMarshal {
+				klog.Warningf("%v has a +resource:customMarshal comment, but neither it nor the types of its "+
+					"fields define MarshalJSON and UnmarshalJSON", c.Name)
+			}
+			customMarshal = true
+		}
+		if customMarshal {
+			switch r.StorageMediaType {
+			case "":
+				// The protobuf serializer does not call MarshalJSON and UnmarshalJSON
+				r.StorageMediaType = "application/json"
+			case "application/vnd.kubernetes.protobuf":
+				klog.Fatalf("// +resource:storageMediaType must be application/json or application/yaml for type %v "+
+					"with custom json marshaling.  Got string: [%s]", c.Name, r.StorageMediaType)
+			}
+		}
+		switch r.StorageMediaType {
+		case "", "application/json", "application/yaml", "application/vnd.kubernetes.protobuf":
+		default:
+			klog.Fatalf("// +resource:storageMediaType must be one of application/json, application/yaml or "+
+				"application/vnd.kubernetes.protobuf for type %v.  Got string: [%s]", c.Name, r.StorageMediaType)
+		}
+		r.StatusStorageMediaType = Comments(c.CommentLines).GetTag("subresource:status:storageMediaType", "=")
+		switch r.StatusStorageMediaType {
+		case "", "application/json", "application/yaml":
+		case "application/vnd.kubernetes.protobuf":
+			if customMarshal {
+				klog.Fatalf("// +subresource:status:storageMediaType must be application/json or application/yaml "+
+					"for type %v with custom json marshaling.  Got string: [%s]", c.Name, r.StatusStorageMediaType)
+			}
+		default:
+			klog.Fatalf("// +subresource:status:storageMediaType must be one of application/json, application/yaml "+
+				"or application/vnd.kubernetes.protobuf for type %v.  Got string: [%s]", c.Name, r.StatusStorageMediaType)
+		}
 
 		// If not defined, default the strategy to the {{.Kind}}Strategy for backwards compatibility
 		if len(r.Strategy) == 0 {
@@ -323,6 +1011,35 @@ func (b *APIsBuilder) ParseIndex() {
 		r.Type = c
 		r.Subresources = b.GetSubresources(r)
 	}
+	b.ReportJSONTagDeviations(deviations)
+	if len(listMapKeyErrors) > 0 {
+		klog.Fatalf("lists marked +listType=map must be merged by a +listMapKey:\n%s",
+			strings.Join(listMapKeyErrors, "\n"))
+	}
+}
+
+// ReportJSONTagDeviations warns about each json tag deviation, failing instead if --strict-json-tags is set
+func (b *APIsBuilder) ReportJSONTagDeviations(deviations []string) {
+	if len(deviations) == 0 {
+		return
+	}
+	if ca, ok := b.arguments.CustomArgs.(*CustomArgs); ok && ca.StrictJSONTags {
+		klog.Fatalf("json tags must be the lowerCamelCase of the field name or marked +json:allowDeviation:\n%s",
+			strings.Join(deviations, "\n"))
+	}
+	for _, d := range deviations {
+		klog.Warningf("%s, mark the field +json:allowDeviation if this is intentional", d)
+	}
+}
+
+// writesDocGo returns true if the doc.go of the api version packages get the "+k8s:openapi-gen=true" comment,
+// written with --write-doc-go or checked with --verify-doc-go
+func (b *APIsBuilder) writesDocGo() bool {
+	if b.arguments == nil {
+		return false
+	}
+	ca, ok := b.arguments.CustomArgs.(*CustomArgs)
+	return ok && (ca.WriteDocGo || ca.VerifyDocGo)
 }
 
 func (b *APIsBuilder) GetSubresources(c *APIResource) map[string]*APISubresource {
@@ -350,6 +1067,13 @@ func (b *APIsBuilder) GetSubresources(c *APIResource) map[string]*APISubresource
 			// Out of package Request types require an import and are prefixed with the
 			// package name - e.g. v1.Scale
 			sr.Request, sr.ImportPackage = b.GetNameAndImport(tags)
+		} else if pkg := b.context.Universe[c.Type.Name.Package]; pkg != nil {
+			sr.RequestType = pkg.Types[sr.Request]
+			if sr.RequestType != nil && !HasOpenAPIDefinition(pkg, sr.RequestType, b.writesDocGo()) {
+				klog.Fatalf("Subresource %s of %v has request type %v without an OpenAPI definition, which fails "+
+					"building the OpenAPI spec of the apiserver.  Add // +k8s:openapi-gen=true to %v or its package.",
+					sr.Path, c.Type.Name, sr.RequestType.Name, sr.Request)
+			}
 		}
 		if v, found := r[sr.Path]; found {
 			klog.Fatalf("Multiple subresources registered for path %s: %v %v",
@@ -445,9 +1169,512 @@ func ParseSubresourceTag(c *APIResource, tag string) SubresourceTags {
 	return result
 }
 
+// ParsePrintColumnTag parses the tags in a "+resource:printColumn=" comment into a PrintColumn and
+// resolves its JSONPath against the resource type c
+func ParsePrintColumnTag(c *types.Type, tag string) *PrintColumn {
+	result := &PrintColumn{Type: "string"}
+	for _, elem := range strings.Split(tag, ",") {
+		kv := strings.SplitN(elem, "=", 2)
+		if len(kv) != 2 {
+			klog.Fatalf("// +resource:printColumn tags must be key value pairs.  Expected "+
+				"keys [name=<header>,JSONPath=<path>,type=<type>,format=<format>,description=<description>,priority=<priority>] "+
+				"Got string: [%s]", tag)
+		}
+		value := kv[1]
+		switch kv[0] {
+		case "name":
+			result.Name = value
+		case "JSONPath":
+			result.JSONPath = value
+		case "type":
+			result.Type = value
+		case "format":
+			result.Format = value
+		case "description":
+			result.Description = value
+		case "priority":
+			priority, err := strconv.Atoi(value)
+			if err != nil {
+				klog.Fatalf("// +resource:printColumn priority must be an integer for type %v.  Got string: [%s]", c.Name, tag)
+			}
+			result.Priority = priority
+		}
+	}
+	if len(result.Name) == 0 || len(result.JSONPath) == 0 {
+		klog.Fatalf("// +resource:printColumn requires name and JSONPath for type %v.  Got string: [%s]", c.Name, tag)
+	}
+	switch result.Type {
+	case "integer", "number", "string", "boolean", "date":
+	default:
+		klog.Fatalf("// +resource:printColumn type must be one of integer, number, string, boolean or date for type %v.  Got string: [%s]", c.Name, tag)
+	}
+
+	guards, value, err := resolveJSONPath(c, result.JSONPath)
+	if err != nil {
+		klog.Fatalf("// +resource:printColumn JSONPath %s does not resolve for type %v: %v", result.JSONPath, c.Name, err)
+	}
+	result.Guards = guards
+	result.Value = value
+	return result
+}
+
+// ParseFieldConstraintTag parses the comma separated field paths of a "+resource:<marker>=" comment
+// into a FieldConstraint of the resource type c
+func ParseFieldConstraintTag(c *types.Type, marker, tag string) *FieldConstraint {
+	paths := strings.Split(tag, ",")
+	if len(paths) < 2 {
+		klog.Fatalf("// +resource:%s requires at least 2 comma separated field paths for type %v.  Got string: [%s]",
+			marker, c.Name, tag)
+	}
+	result := &FieldConstraint{Rule: namer.IC(marker)}
+	for _, path := range paths {
+		path = strings.TrimPrefix(strings.TrimSpace(path), ".")
+		guards, value, err := resolveJSONPathField(c, "."+path)
+		if err != nil {
+			klog.Fatalf("// +resource:%s field %s does not resolve for type %v: %v", marker, path, c.Name, err)
+		}
+		result.Fields = append(result.Fields, &ConstrainedField{Path: path, Guards: guards, Value: value})
+	}
+	return result
+}
+
+// ParseRemovedFieldTag parses the field path of a "+removedField=" comment of the resource type c
+func ParseRemovedFieldTag(c *types.Type, tag string) *RemovedField {
+	path := strings.TrimPrefix(strings.TrimSpace(tag), ".")
+	guards, field, err := resolveJSONPathField(c, "."+path)
+	if err != nil {
+		klog.Fatalf("// +removedField=%s does not resolve for type %v: %v", path, c.Name, err)
+	}
+	return &RemovedField{Path: path, Guards: guards, Field: field}
+}
+
+// ParseRangeDefaultTag parses a "+resource:rangeDefault=<path>:<min>:<max>" comment of the resource type c,
+// checking that the field is an integer, or a pointer to an integer, and that min is not greater than max
+func ParseRangeDefaultTag(c *types.Type, tag string) *RangeDefault {
+	values := strings.Split(strings.TrimSpace(tag), ":")
+	if len(values) != 3 {
+		klog.Fatalf("// +resource:rangeDefault requires <path>:<min>:<max> for type %v.  Got string: [%s]", c.Name, tag)
+	}
+	result := &RangeDefault{Path: strings.TrimPrefix(values[0], ".")}
+	var err error
+	if result.Min, err = strconv.ParseInt(values[1], 10, 64); err != nil {
+		klog.Fatalf("// +resource:rangeDefault=%s of type %v requires an integer min: %v", tag, c.Name, err)
+	}
+	if result.Max, err = strconv.ParseInt(values[2], 10, 64); err != nil {
+		klog.Fatalf("// +resource:rangeDefault=%s of type %v requires an integer max: %v", tag, c.Name, err)
+	}
+	if result.Min > result.Max {
+		klog.Fatalf("// +resource:rangeDefault=%s of type %v has a min greater than its max", tag, c.Name)
+	}
+
+	guards, field, t, _, err := walkJSONPath(c, "."+result.Path, false)
+	if err != nil {
+		klog.Fatalf("// +resource:rangeDefault=%s does not resolve for type %v: %v", tag, c.Name, err)
+	}
+	if t.Kind == types.Pointer {
+		t = t.Elem
+	}
+	for t.Kind == types.Alias {
+		t = t.Underlying
+	}
+	switch {
+	case len(t.Name.Package) > 0:
+		klog.Fatalf("// +resource:rangeDefault=%s of type %v requires an integer field, not a %v", tag, c.Name, t.Name)
+	case sets.NewString("int", "int8", "int16", "int32", "int64").Has(t.Name.Name):
+	case sets.NewString("uint", "uint8", "uint16", "uint32", "uint64").Has(t.Name.Name):
+		if result.Min < 0 {
+			klog.Fatalf("// +resource:rangeDefault=%s of type %v requires a min of at least 0 for an unsigned field",
+				tag, c.Name)
+		}
+	default:
+		klog.Fatalf("// +resource:rangeDefault=%s of type %v requires an integer field, not a %v", tag, c.Name, t.Name)
+	}
+	result.Guards, result.Field = guards, field
+	return result
+}
+
+// ParseFeatureGateTag returns the name of the feature gate of a "+resource:featureGate=<name>[,default=true]"
+// comment of the resource type c and whether the gate is enabled by default
+func ParseFeatureGateTag(c *types.Type, tag string) (string, bool) {
+	values := strings.Split(tag, ",")
+	name := strings.TrimSpace(values[0])
+	if len(name) == 0 || strings.ContainsAny(name, "= ") {
+		klog.Fatalf("// +resource:featureGate requires the name of the feature gate for type %v.  Got string: [%s]",
+			c.Name, tag)
+	}
+	enabled := false
+	for _, value := range values[1:] {
+		switch strings.TrimSpace(value) {
+		case "default=true":
+			enabled = true
+		case "default=false":
+			enabled = false
+		default:
+			klog.Fatalf("// +resource:featureGate only accepts default=true or default=false after the name of the "+
+				"feature gate for type %v.  Got string: [%s]", c.Name, tag)
+		}
+	}
+	return name, enabled
+}
+
+// ParseObservedGenerationTag returns the Go expression of the status.observedGeneration field of the resource
+// type c with a "+status:observedGeneration" comment, checking that the field is an int64
+func ParseObservedGenerationTag(c *types.Type) string {
+	guards, expr, t, _, err := walkJSONPath(c, ".status.observedGeneration", false)
+	if err != nil {
+		klog.Fatalf("// +status:observedGeneration requires a status.observedGeneration field for type %v: %v",
+			c.Name, err)
+	}
+	if len(guards) > 0 || t.Name != types.Int64.Name {
+		klog.Fatalf("// +status:observedGeneration requires status.observedGeneration of type %v to be an int64, "+
+			"not a %v", c.Name, t.Name)
+	}
+	return expr
+}
+
+// ParseConditionTag parses the type of a "+condition=" comment into a Condition of the resource type c, checking
+// that status.conditions is a slice of a struct of the package of c with the Type, Status, Reason and Message
+// string fields, and that the type differs from the types of conditions
+func ParseConditionTag(c *types.Type, conditions []*Condition, tag string) *Condition {
+	result := &Condition{Name: strings.TrimSpace(tag)}
+	if !conditionName.MatchString(result.Name) {
+		klog.Fatalf("// +condition=%s of type %v requires a CamelCase type starting with an upper case letter",
+			tag, c.Name)
+	}
+	for _, condition := range conditions {
+		if condition.Name == result.Name {
+			klog.Fatalf("// +condition=%s of type %v is declared twice", tag, c.Name)
+		}
+	}
+
+	guards, expr, t, _, err := walkJSONPath(c, ".status.conditions", false)
+	if err != nil {
+		klog.Fatalf("// +condition=%s requires a status.conditions field for type %v: %v", tag, c.Name, err)
+	}
+	if len(guards) > 0 || t.Kind != types.Slice || t.Elem.Kind != types.Struct ||
+		t.Elem.Name.Package != c.Name.Package {
+		klog.Fatalf("// +condition=%s requires status.conditions of type %v to be a slice of a struct of package %s, "+
+			"not a %v", tag, c.Name, c.Name.Package, t.Name)
+	}
+	for _, name := range []string{"Type", "Status", "Reason", "Message"} {
+		found := false
+		for _, m := range t.Elem.Members {
+			found = found || m.Name == name && m.Type.Name == types.String.Name
+		}
+		if !found {
+			klog.Fatalf("// +condition=%s of type %v requires a %s string field in %v", tag, c.Name, name, t.Elem.Name)
+		}
+	}
+	result.Type = t.Elem.Name.Name
+	result.Conditions = expr
+	return result
+}
+
+// ParseAnnotationConversionTag returns the function named by a "+annotationConversion=" comment of the resource
+// type c, checking that the versioned package of c declares it as
+// func(annotations map[string]string, toInternal bool)
+func ParseAnnotationConversionTag(universe types.Universe, c *types.Type, tag string) string {
+	name := strings.TrimSpace(tag)
+	signature := fmt.Sprintf("func %s(annotations map[string]string, toInternal bool)", name)
+	f, found := universe[c.Name.Package].Functions[name]
+	if !found || f.Underlying == nil || f.Underlying.Signature == nil {
+		klog.Fatalf("// +annotationConversion=%s for type %v requires %s in package %s",
+			tag, c.Name, signature, c.Name.Package)
+	}
+	sig := f.Underlying.Signature
+	params := sig.Parameters
+	if sig.Variadic || len(params) != 2 || len(sig.Results) != 0 ||
+		params[0].Kind != types.Map || params[0].Key.Name != (types.Name{Name: "string"}) ||
+		params[0].Elem.Name != (types.Name{Name: "string"}) ||
+		params[1].Name != (types.Name{Name: "bool"}) {
+		klog.Fatalf("// +annotationConversion=%s for type %v requires the signature %s", tag, c.Name, signature)
+	}
+	return name
+}
+
+// ParseAdmissionTag returns the function named by a "+admission:validating=" comment of the resource type c,
+// checking that the group package of c declares it as
+// func(ctx context.Context, obj *<Kind>, a admission.Attributes) error
+func ParseAdmissionTag(universe types.Universe, c *types.Type, tag string) string {
+	name := strings.TrimSpace(tag)
+	group := filepath.Dir(c.Name.Package)
+	signature := fmt.Sprintf("func %s(ctx context.Context, obj *%s, a admission.Attributes) error", name, c.Name.Name)
+	f, found := universe[group].Functions[name]
+	if !found || f.Underlying == nil || f.Underlying.Signature == nil {
+		klog.Fatalf("// +admission:validating=%s for type %v requires %s in package %s", tag, c.Name, signature, group)
+	}
+	sig := f.Underlying.Signature
+	params := sig.Parameters
+	results := sig.Results
+	if sig.Variadic || len(params) != 3 || len(results) != 1 ||
+		params[0].Name != (types.Name{Package: "context", Name: "Context"}) ||
+		params[1].Kind != types.Pointer || params[1].Elem.Name != (types.Name{Package: group, Name: c.Name.Name}) ||
+		params[2].Name != (types.Name{Package: "k8s.io/apiserver/pkg/admission", Name: "Attributes"}) ||
+		results[0].Name != (types.Name{Name: "error"}) {
+		klog.Fatalf("// +admission:validating=%s for type %v requires the signature %s", tag, c.Name, signature)
+	}
+	return name
+}
+
+// ParseNameValidationTag returns the function named by the "+resource:nameValidation=" comment of the resource
+// type c, checking that the group package of c declares it as func(name string, prefix bool) []string
+func ParseNameValidationTag(universe types.Universe, c *types.Type, tag string) string {
+	name := strings.TrimSpace(tag)
+	group := filepath.Dir(c.Name.Package)
+	signature := fmt.Sprintf("func %s(name string, prefix bool) []string", name)
+	f, found := universe[group].Functions[name]
+	if !found || f.Underlying == nil || f.Underlying.Signature == nil {
+		klog.Fatalf("// +resource:nameValidation=%s for type %v requires %s in package %s", tag, c.Name, signature, group)
+	}
+	sig := f.Underlying.Signature
+	params := sig.Parameters
+	results := sig.Results
+	if sig.Variadic || len(params) != 2 || len(results) != 1 ||
+		params[0].Name != (types.Name{Name: "string"}) || params[1].Name != (types.Name{Name: "bool"}) ||
+		results[0].Kind != types.Slice || results[0].Elem.Name != (types.Name{Name: "string"}) {
+		klog.Fatalf("// +resource:nameValidation=%s for type %v requires the signature %s", tag, c.Name, signature)
+	}
+	return name
+}
+
+// ParseIndexTag parses the field path of a "+index=" comment into an Index of the resource type c
+func ParseIndexTag(c *types.Type, tag string) *Index {
+	result := &Index{Name: strings.TrimPrefix(tag, ".")}
+	guards, value, err := resolveJSONPath(c, "."+result.Name)
+	if err != nil {
+		klog.Fatalf("// +index=%s does not resolve for type %v: %v", tag, c.Name, err)
+	}
+	for _, elem := range jsonPathElement.FindAllString("."+result.Name, -1) {
+		result.Suffix += namer.IC(strings.Trim(elem, ".[]"))
+	}
+	result.Guards = guards
+	result.Value = value
+	return result
+}
+
+// ParseMetricsLabelTag parses the field path of a "+metrics:label=" comment into a MetricsLabel of the resource
+// type c, named after the last field of the path, which must differ from the names of the labels
+func ParseMetricsLabelTag(c *types.Type, labels []*MetricsLabel, tag string) *MetricsLabel {
+	result := &MetricsLabel{Path: strings.TrimPrefix(tag, ".")}
+	guards, value, err := resolveJSONPath(c, "."+result.Path)
+	if err != nil {
+		klog.Fatalf("// +metrics:label=%s does not resolve for type %v: %v", tag, c.Name, err)
+	}
+	fields := jsonPathField.FindAllString("."+result.Path, -1)
+	result.Name = strings.TrimPrefix(fields[len(fields)-1], ".")
+	if result.Name == "namespace" {
+		klog.Fatalf("// +metrics:label=%s of type %v conflicts with the namespace label", tag, c.Name)
+	}
+	for _, label := range labels {
+		if label.Name == result.Name {
+			klog.Fatalf("// +metrics:label=%s of type %v conflicts with the label %s of %s", tag, c.Name, label.Name, label.Path)
+		}
+	}
+	result.Guards = guards
+	result.Value = value
+	return result
+}
+
+// ParseCustomMetricTag parses the tags of a "+metric=" comment into a CustomMetric of the resource type c, whose
+// path must resolve to a numeric or resource.Quantity field of the status and whose name must differ from the
+// names of the metrics
+func ParseCustomMetricTag(c *types.Type, metrics []*CustomMetric, tag string) *CustomMetric {
+	result := &CustomMetric{}
+	for _, elem := range strings.Split(tag, ",") {
+		kv := strings.SplitN(elem, "=", 2)
+		if len(kv) != 2 {
+			klog.Fatalf("// +metric tags must be key value pairs.  Expected keys [name=<metric>,path=<path>] "+
+				"Got string: [%s]", tag)
+		}
+		switch kv[0] {
+		case "name":
+			result.Name = kv[1]
+		case "path":
+			result.Path = strings.TrimPrefix(kv[1], ".")
+		default:
+			klog.Fatalf("// +metric=%s of type %v has the unknown key %s", tag, c.Name, kv[0])
+		}
+	}
+	if len(result.Name) == 0 || strings.ContainsAny(result.Name, "/%") || result.Name == "." || result.Name == ".." {
+		klog.Fatalf("// +metric=%s of type %v requires a name valid in a path segment", tag, c.Name)
+	}
+	if !strings.HasPrefix(result.Path, "status.") {
+		klog.Fatalf("// +metric=%s of type %v requires the path of a status field", tag, c.Name)
+	}
+	for _, metric := range metrics {
+		if metric.Name == result.Name {
+			klog.Fatalf("// +metric=%s of type %v conflicts with the metric %s of %s", tag, c.Name, metric.Name, metric.Path)
+		}
+	}
+
+	guards, expr, t, pointers, err := walkJSONPath(c, "."+result.Path, true)
+	if err != nil {
+		klog.Fatalf("// +metric=%s does not resolve for type %v: %v", tag, c.Name, err)
+	}
+	if pointers > 0 {
+		expr = fmt.Sprintf("(%s%s)", strings.Repeat("*", pointers), expr)
+	}
+	if t.Kind == types.Alias {
+		t = t.Underlying
+	}
+	switch {
+	case t.Name == types.Name{Package: "k8s.io/apimachinery/pkg/api/resource", Name: "Quantity"}:
+		result.Value = expr
+	case t.Kind == types.Builtin && strings.HasPrefix(t.Name.Name, "float"):
+		result.Value = fmt.Sprintf("*resource.NewMilliQuantity(int64(%s*1000), resource.DecimalSI)", expr)
+	case t.Kind == types.Builtin && (strings.HasPrefix(t.Name.Name, "int") || strings.HasPrefix(t.Name.Name, "uint")):
+		result.Value = fmt.Sprintf("*resource.NewQuantity(int64(%s), resource.DecimalSI)", expr)
+	default:
+		klog.Fatalf("// +metric=%s of type %v requires a number or a resource.Quantity, got %s", tag, c.Name, t.Name)
+	}
+	result.Guards = guards
+	return result
+}
+
+var buildTag = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+var conditionName = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+var jsonPathElement = regexp.MustCompile(`\.[A-Za-z0-9_]+|\[[0-9]+\]`)
+
+var jsonPathField = regexp.MustCompile(`\.[A-Za-z0-9_]+`)
+
+// resolveJSONPath returns the Go expression for the value at the JSONPath in an object "o" of type t,
+// along with the conditions under which the value is not set.  Fields of embedded structs are resolved
+// the same way they are serialized, as if they were fields of the embedding struct.
+func resolveJSONPath(t *types.Type, jsonPath string) ([]string, string, error) {
+	guards, expr, t, pointers, err := walkJSONPath(t, jsonPath, true)
+	if err != nil {
+		return nil, "", err
+	}
+	switch {
+	case t.Kind == types.Builtin, t.Kind == types.Alias:
+	case t.Name == types.Name{Package: "k8s.io/apimachinery/pkg/apis/meta/v1", Name: "Time"}:
+	default:
+		return nil, "", errors.Errorf("%s is a %s and cannot be printed", t.Name, t.Kind)
+	}
+	if pointers > 0 {
+		expr = fmt.Sprintf("(%s%s)", strings.Repeat("*", pointers), expr)
+	}
+	return guards, expr, nil
+}
+
+// resolveJSONPathField returns the Go expression for the field at the JSONPath in an object "o" of type t
+// without dereferencing the field, along with the conditions under which a parent of the field is not set
+func resolveJSONPathField(t *types.Type, jsonPath string) ([]string, string, error) {
+	guards, expr, _, _, err := walkJSONPath(t, jsonPath, false)
+	return guards, expr, err
+}
+
+// walkJSONPath returns the guards and Go expression of the JSONPath in an object "o" of type t, the type
+// of the expression and the number of pointers to dereference to reach it.  The pointers of the last
+// element are only dereferenced if derefLeaf is true.
+func walkJSONPath(t *types.Type, jsonPath string, derefLeaf bool) ([]string, string, *types.Type, int, error) {
+	elems := jsonPathElement.FindAllString(jsonPath, -1)
+	if strings.Join(elems, "") != jsonPath {
+		return nil, "", nil, 0, errors.Errorf("only .field and [index] elements are supported")
+	}
+
+	guards := []string{}
+	expr := "o"
+	// pointers counts the pointers dereferenced to reach the current type
+	pointers := 0
+	deref := func(m *types.Type) *types.Type {
+		pointers = 0
+		for {
+			switch {
+			case m.Kind == types.Pointer:
+				guards = append(guards, expr+" == nil")
+				pointers++
+				m = m.Elem
+			case m.Kind == types.Alias && !m.Underlying.IsPrimitive():
+				m = m.Underlying
+			default:
+				return m
+			}
+		}
+	}
+
+	for i, elem := range elems {
+		leaf := i == len(elems)-1 && !derefLeaf
+		if strings.HasPrefix(elem, "[") {
+			if t.Kind != types.Slice {
+				return nil, "", nil, 0, errors.Errorf("%s indexes %s which is not a slice", elem, t.Name)
+			}
+			index := strings.Trim(elem, "[]")
+			guards = append(guards, fmt.Sprintf("len(%s) <= %s", expr, index))
+			expr = fmt.Sprintf("%s[%s]", expr, index)
+			t = t.Elem
+			if !leaf {
+				t = deref(t)
+			}
+			continue
+		}
+
+		if t.Kind != types.Struct {
+			return nil, "", nil, 0, errors.Errorf("%s selects a field of %s which is not a struct", elem, t.Name)
+		}
+		members := findJSONMember(t, strings.TrimPrefix(elem, "."))
+		if members == nil {
+			return nil, "", nil, 0, errors.Errorf("%s is not a field of %s", elem, t.Name)
+		}
+		for j, m := range members {
+			expr = expr + "." + m.Name
+			t = m.Type
+			if !leaf || j < len(members)-1 {
+				t = deref(t)
+			}
+		}
+	}
+	return guards, expr, t, pointers, nil
+}
+
+// findJSONMember returns the members selecting the serialized field name of t.  Embedded structs without
+// a json name are inlined, so their members are searched after the members of t.
+func findJSONMember(t *types.Type, name string) []types.Member {
+	inlined := []types.Member{}
+	for _, m := range t.Members {
+		tag := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		switch {
+		case tag == "-":
+		case len(tag) == 0 && m.Embedded:
+			inlined = append(inlined, m)
+		case tag == name, len(tag) == 0 && m.Name == name:
+			return []types.Member{m}
+		}
+	}
+	for _, m := range inlined {
+		e := m.Type
+		if e.Kind == types.Pointer {
+			e = e.Elem
+		}
+		if e.Kind != types.Struct {
+			continue
+		}
+		if members := findJSONMember(e, name); members != nil {
+			return append([]types.Member{m}, members...)
+		}
+	}
+	return nil
+}
+
+// resourceMarkers are "+resource:<marker>=" comments configuring a resource rather than declaring it
+var resourceMarkers = []string{"printColumn", "storageMediaType", "customMarshal", "oneOf", "allOrNone",
+	"enableGarbageCollection", "featureGate", "nameValidation", "rangeDefault", "defaultOnRead", "stampUser",
+	"nameRegex", "utcTimes", "generateNameRequired"}
+
 // GetResourceTag returns the value of the "+resource=" comment tag
 func (b *APIsBuilder) GetResourceTag(c *types.Type) string {
-	comments := Comments(c.CommentLines)
+	comments := Comments{}
+	for _, line := range IgnoreDeprecatedMarkers(c, c.CommentLines) {
+		isMarker := false
+		for _, marker := range resourceMarkers {
+			isMarker = isMarker || strings.HasPrefix(line, "+resource:"+marker)
+		}
+		if !isMarker {
+			comments = append(comments, line)
+		}
+	}
 	resource := comments.GetTag("resource", ":")
 	kbResource := comments.GetTag("kubebuilder:resource", ":")
 	if len(resource) != 0 {
@@ -484,9 +1711,17 @@ func (b *APIsBuilder) GetControllerTag(c *types.Type) string {
 	panic(errors.Errorf("Must specify +controller or +kubebuilder:controller comment for type %v", c.Name))
 }
 
+// GetSubresourceTags returns the tags of the "+subresource:" comments declaring the subresources of c, the
+// "+subresource:status:" comments configure the status subresource
 func (b *APIsBuilder) GetSubresourceTags(c *types.Type) []string {
 	comments := Comments(c.CommentLines)
-	return comments.GetTags("subresource", ":")
+	tags := []string{}
+	for _, tag := range comments.GetTags("subresource", ":") {
+		if !strings.HasPrefix(tag, "status:") {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
 }
 
 // ParseGroupNames initializes b.GroupNames with the set of all groups
@@ -545,35 +1780,51 @@ type GenUnversionedType struct {
 	Resource *APIResource
 }
 
+// ParseStructsAndAliases generates the unversioned structs of the types of the resources of apigroup and of
+// the types of their fields.  The versions are visited from the most preferred one, so the fields declared by
+// several versions take the type of the most preferred version, and the fields declared by some versions
+// only, e.g. added by a newer version, are merged into the unversioned struct.
 func (b *APIsBuilder) ParseStructsAndAliases(apigroup *APIGroup) {
 	remaining := []GenUnversionedType{}
-	for _, version := range apigroup.Versions {
-		for _, resource := range version.Resources {
-			remaining = append(remaining, GenUnversionedType{resource.Type, resource})
+	for _, version := range apigroup.VersionPriority {
+		resources := apigroup.Versions[version].Resources
+		for _, kind := range sets.StringKeySet(resources).List() {
+			remaining = append(remaining, GenUnversionedType{resources[kind].Type, resources[kind]})
 		}
 	}
-	for _, version := range b.SubByGroupVersionKind[apigroup.Group] {
-		for _, kind := range version {
-			remaining = append(remaining, GenUnversionedType{kind, nil})
+	for _, version := range sets.StringKeySet(b.SubByGroupVersionKind[apigroup.Group]).List() {
+		kinds := b.SubByGroupVersionKind[apigroup.Group][version]
+		for _, kind := range sets.StringKeySet(kinds).List() {
+			remaining = append(remaining, GenUnversionedType{kinds[kind], nil})
 		}
 	}
 
-	done := sets.String{}
+	done := map[string]*Struct{}
+	visited := sets.NewString()
 	for len(remaining) > 0 {
 		// Pop the next element from the list
 		next := remaining[0]
-		remaining[0] = remaining[len(remaining)-1]
-		remaining = remaining[:len(remaining)-1]
+		remaining = remaining[1:]
 
-		// Already processed this type.  Skip it
-		if done.Has(next.Type.Name.Name) {
+		// Already processed this type of this version.  Skip it
+		if visited.Has(next.Type.Name.String()) {
 			continue
 		}
-		done.Insert(next.Type.Name.Name)
+		visited.Insert(next.Type.Name.String())
 
 		// Generate the struct and append to the list
 		result, additionalTypes := apigroup.DoType(next.Type)
 
+		// Already processed this type in another version.  Merge the fields it lacks
+		if existing, found := done[next.Type.Name.Name]; found {
+			existing.Fields = mergeFields(existing.Fields, result.Fields)
+			for _, at := range additionalTypes {
+				remaining = append(remaining, GenUnversionedType{at, nil})
+			}
+			continue
+		}
+		done[next.Type.Name.Name] = result
+
 		// This is a resource, so generate the client
 		if b.GenClient(next.Type) {
 			result.GenClient = true
@@ -600,6 +1851,20 @@ func (b *APIsBuilder) ParseStructsAndAliases(apigroup *APIGroup) {
 	})
 }
 
+// mergeFields appends the named fields of fields missing from existing to existing
+func mergeFields(existing, fields []*Field) []*Field {
+	names := sets.NewString()
+	for _, field := range existing {
+		names.Insert(field.Name)
+	}
+	for _, field := range fields {
+		if len(field.Name) > 0 && !names.Has(field.Name) {
+			existing = append(existing, field)
+		}
+	}
+	return existing
+}
+
 func (apigroup *APIGroup) DoType(t *types.Type) (*Struct, []*types.Type) {
 	remaining := []*types.Type{}
 
@@ -617,6 +1882,20 @@ func (apigroup *APIGroup) DoType(t *types.Type) (*Struct, []*types.Type) {
 	}
 
 	for _, member := range t.Members {
+		if member.Type.Kind == types.Map {
+			// The key and element types of a map may each come from a different package, so
+			// resolve them separately rather than parsing the type string
+			uType, uImports, additionalTypes := apigroup.unversionedMapType(t, member)
+			s.Fields = append(s.Fields, &Field{
+				Name:               member.Name,
+				VersionedPackage:   member.Type.Name.Package,
+				UnversionedType:    uType,
+				UnversionedImports: uImports,
+			})
+			remaining = append(remaining, additionalTypes...)
+			continue
+		}
+
 		uType := member.Type.Name.Name
 		memberName := member.Name
 		uImport := ""
@@ -720,6 +1999,11 @@ func (apigroup *APIGroup) DoType(t *types.Type) (*Struct, []*types.Type) {
 		if member.Embedded {
 			memberName = ""
 		}
+		if IsEnum(mSubType) && samepkg {
+			// The internal fields of +enum types are strings, so the versions may declare the field
+			// with the +enum type or with a string
+			uType = strings.Replace(uType, mSubType.Name.Name, "string", 1)
+		}
 
 		s.Fields = append(s.Fields, &Field{
 			Name:              memberName,
@@ -729,6 +2013,7 @@ func (apigroup *APIGroup) DoType(t *types.Type) (*Struct, []*types.Type) {
 		})
 
 		switch {
+		case IsEnum(mSubType):
 		case mSubType.Kind == types.Alias && mSubType.Underlying.IsPrimitive():
 			if _, ok := apigroup.Aliases[mSubType.Name.Name]; !ok {
 				apigroup.Aliases[mSubType.Name.Name] = &Alias{
@@ -744,3 +2029,62 @@ func (apigroup *APIGroup) DoType(t *types.Type) (*Struct, []*types.Type) {
 	}
 	return s, remaining
 }
+
+// unversionedMapType returns the unversioned type of a map member along with the imports it
+// requires and any types from the same API group that need an unversioned copy generated.
+func (apigroup *APIGroup) unversionedMapType(t *types.Type, member types.Member) (string, []string, []*types.Type) {
+	return apigroup.resolveUnversionedType(t, member, member.Type)
+}
+
+func (apigroup *APIGroup) resolveUnversionedType(t *types.Type, member types.Member, m *types.Type) (string, []string, []*types.Type) {
+	switch m.Kind {
+	case types.Pointer:
+		name, imports, additional := apigroup.resolveUnversionedType(t, member, m.Elem)
+		return "*" + name, imports, additional
+	case types.Slice:
+		name, imports, additional := apigroup.resolveUnversionedType(t, member, m.Elem)
+		return "[]" + name, imports, additional
+	case types.Map:
+		key, keyImports, keyAdditional := apigroup.resolveUnversionedType(t, member, m.Key)
+		elem, elemImports, elemAdditional := apigroup.resolveUnversionedType(t, member, m.Elem)
+		return fmt.Sprintf("map[%s]%s", key, elem),
+			append(keyImports, elemImports...),
+			append(keyAdditional, elemAdditional...)
+	case types.Builtin:
+		return m.Name.Name, nil, nil
+	case types.Struct, types.Alias:
+		pkg := m.Name.Package
+		switch {
+		case pkg == t.Name.Package:
+			if IsEnum(m) {
+				return "string", nil, nil
+			}
+			if m.Kind == types.Alias && m.Underlying.IsPrimitive() {
+				if _, ok := apigroup.Aliases[m.Name.Name]; !ok {
+					apigroup.Aliases[m.Name.Name] = &Alias{
+						Name:               m.Name.Name,
+						UnderlyingTypeName: m.Underlying.Name.Name,
+					}
+				}
+				return m.Name.Name, nil, nil
+			}
+			if GetGroup(m) == GetGroup(t) {
+				return m.Name.Name, nil, []*types.Type{m}
+			}
+			return m.Name.Name, nil, nil
+		case pkg == "k8s.io/apimachinery/pkg/apis/meta/v1":
+			// Use versioned types for meta/v1
+			return "metav1." + m.Name.Name, []string{`metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`}, nil
+		default:
+			// Import the package under an alias concatenated with its parent directory so it
+			// doesn't conflict with other groups having the same version
+			alias := path.Base(path.Dir(pkg)) + path.Base(pkg)
+			return alias + "." + m.Name.Name, []string{fmt.Sprintf("%s \"%s\"", alias, pkg)}, nil
+		}
+	}
+	// As the other members, the entries of the other kinds keep their type, conversion-gen copies them or leaves
+	// them to the conversion functions of the struct
+	klog.Warningf("The map entries of kind %v (%v) of field %s.%s are not converted, convert them in the "+
+		"conversion functions of %s", m.Kind, m.Name, t.Name, member.Name, t.Name.Name)
+	return m.Name.Name, nil, nil
+}
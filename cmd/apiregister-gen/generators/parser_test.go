@@ -0,0 +1,48 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"testing"
+
+	"k8s.io/gengo/types"
+)
+
+// TestResourceMarkers checks the "+resource:<marker>" comments written above the "+resource:path=" comment of a
+// type are not taken for its +resource tag
+func TestResourceMarkers(t *testing.T) {
+	markers := []string{
+		"+resource:defaultOnRead",
+		"+resource:stampUser",
+		"+resource:nameRegex=^[a-z]+$",
+		"+resource:utcTimes",
+		"+resource:generateNameRequired",
+	}
+	for _, marker := range markers {
+		shoggoth := &types.Type{
+			Name: types.Name{Package: "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1", Name: "Shoggoth"},
+			Kind: types.Struct,
+			CommentLines: []string{
+				marker,
+				"+resource:path=shoggoths,strategy=ShoggothStrategy",
+			},
+		}
+		if resource := (&APIsBuilder{}).GetResourceTag(shoggoth); resource != "path=shoggoths,strategy=ShoggothStrategy" {
+			t.Errorf("expected the marker %q to be skipped, got the +resource tag %q", marker, resource)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+)
+
+// PointerSliceElems returns the names of the structs declared in the package of the resource t which are the
+// elements of []*T fields, reached from t through the fields of types declared in its package.  Their element
+// conversions are generated for conversion-gen to convert the elements through the scope rather than by
+// reflection.
+func PointerSliceElems(t *types.Type) []string {
+	elems := sets.NewString()
+	findPointerSliceElems(t, t.Name.Package, sets.NewString(), elems)
+	return elems.List()
+}
+
+func findPointerSliceElems(t *types.Type, pkg string, visited, elems sets.String) {
+	if t.Kind != types.Struct || t.Name.Package != pkg || visited.Has(t.Name.Name) {
+		return
+	}
+	visited.Insert(t.Name.Name)
+	for _, m := range t.Members {
+		if m.Type.Kind == types.Slice && m.Type.Elem.Kind == types.Pointer {
+			elem := m.Type.Elem.Elem
+			if elem.Kind == types.Struct && elem.Name.Package == pkg {
+				elems.Insert(elem.Name.Name)
+			}
+		}
+		findPointerSliceElems(elemType(m.Type), pkg, visited, elems)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"reflect"
+	"strings"
+
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// ReadOnlyField is a field of a resource with a "+readOnly" comment, which the clients may not set through the
+// resource
+type ReadOnlyField struct {
+	// Path is the path of the field - e.g. status.phase
+	Path string
+	// Guards are the Go conditions under which a parent of the field is not set for the unversioned object o
+	Guards []string
+	// Field is the Go expression of the field for the unversioned object o
+	Field string
+}
+
+// ReadOnlyFields returns the "+readOnly" fields of the resource t.  Only the fields reached from t through the
+// struct fields, and pointers to struct fields, of types declared in the package of t are returned.
+func ReadOnlyFields(t *types.Type) []*ReadOnlyField {
+	paths := []string{}
+	findReadOnlyFields(t, t.Name.Package, "", &paths)
+
+	fields := []*ReadOnlyField{}
+	for _, path := range paths {
+		guards, field, err := resolveJSONPathField(t, "."+path)
+		if err != nil {
+			klog.Fatalf("+readOnly field %s does not resolve for type %v: %v", path, t.Name, err)
+		}
+		fields = append(fields, &ReadOnlyField{Path: path, Guards: guards, Field: field})
+	}
+	return fields
+}
+
+func findReadOnlyFields(t *types.Type, pkg, path string, paths *[]string) {
+	for _, m := range t.Members {
+		name := strings.Split(reflect.StructTag(m.Tags).Get("json"), ",")[0]
+		if name == "-" || m.Embedded {
+			continue
+		}
+		if len(name) == 0 {
+			name = m.Name
+		}
+		mPath := strings.TrimPrefix(path+"."+name, ".")
+
+		mType := m.Type
+		if mType.Kind == types.Pointer {
+			mType = mType.Elem
+		}
+		switch {
+		case Comments(m.CommentLines).HasTag("readOnly"):
+			*paths = append(*paths, mPath)
+		case mType.Kind == types.Struct && mType.Name.Package == pkg:
+			findReadOnlyFields(mType, pkg, mPath, paths)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"text/template"
+
+	"k8s.io/gengo/namer"
+	"k8s.io/gengo/types"
+)
+
+// templateKinds maps the kinds of generators whose template may be overridden to their built-in template
+var templateKinds = map[string]*string{
+	"versioned":   &VersionedAPITemplate,
+	"conversion":  &ConversionTemplate,
+	"builder":     &BuilderTemplate,
+	"unversioned": &UnversionedAPITemplate,
+	"install":     &InstallAPITemplate,
+	"apis":        &APIsTemplate,
+	"admission":   &AdmissionsInstallTemplate,
+	"testclient":  &TestClientTemplate,
+}
+
+// overriddenTemplates maps the kinds of generators whose template was overridden to the template file
+var overriddenTemplates = map[string]string{}
+
+// templateFuncs are the functions available to the templates of all the generators
+var templateFuncs = template.FuncMap{
+	"public":               namer.IC,
+	"plural":               func(t *types.Type) string { return namer.NewPublicPluralNamer(nil).Name(t) },
+	"hasCustomConversions": hasCustomConversions,
+	"untagged":             untagged,
+	"served":               served,
+	"join":                 strings.Join,
+}
+
+// untagged returns the resources without a build tag, the code serving the others is generated in a file of
+// its own constrained by the build tag of the resource
+func untagged(resources map[string]*APIResource) map[string]*APIResource {
+	result := map[string]*APIResource{}
+	for name, resource := range resources {
+		if len(resource.BuildTag) == 0 {
+			result[name] = resource
+		}
+	}
+	return result
+}
+
+// served returns the resources without a build tag served by their version, leaving out the resources of a
+// version removed from serving them, whose types are only kept for their conversions
+func served(resources map[string]*APIResource) map[string]*APIResource {
+	result := map[string]*APIResource{}
+	for name, resource := range untagged(resources) {
+		if !resource.RetainConversion {
+			result[name] = resource
+		}
+	}
+	return result
+}
+
+// LoadTemplateOverrides replaces the built-in template of each kind of generator in overrides with the
+// template read from the file it maps to.  The templates are parsed to fail before any generation.
+func LoadTemplateOverrides(overrides map[string]string) error {
+	for kind, file := range overrides {
+		builtin, ok := templateKinds[kind]
+		if !ok {
+			kinds := []string{}
+			for k := range templateKinds {
+				kinds = append(kinds, k)
+			}
+			sort.Strings(kinds)
+			return fmt.Errorf("unknown generator kind %q of template override %s, expected one of %s",
+				kind, file, strings.Join(kinds, ", "))
+		}
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read the %s template override: %v", kind, err)
+		}
+		if _, err := template.New(kind).Funcs(templateFuncs).Parse(string(content)); err != nil {
+			return fmt.Errorf("failed to parse the %s template override %s: %v", kind, file, err)
+		}
+		*builtin = string(content)
+		overriddenTemplates[kind] = file
+	}
+	return nil
+}
+
+// executeTemplate writes the template of the kind of generator executed with data to w.  The output of
+// an overridden template must parse as go declarations, so that a broken override is reported against its
+// file rather than as unformattable generated code.
+func executeTemplate(w io.Writer, kind string, temp *template.Template, data interface{}) error {
+	file, overridden := overriddenTemplates[kind]
+	if !overridden {
+		return temp.Execute(w, data)
+	}
+	out := &bytes.Buffer{}
+	if err := temp.Execute(out, data); err != nil {
+		return fmt.Errorf("failed to execute the %s template override %s: %v", kind, file, err)
+	}
+	// The package clause shares the first line so that the positions of the errors are those of the output
+	src := "package generated;" + out.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors); err != nil {
+		return fmt.Errorf("the output of the %s template override %s is not valid go: %v", kind, file, err)
+	}
+	_, err := w.Write(out.Bytes())
+	return err
+}
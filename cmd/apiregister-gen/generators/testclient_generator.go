@@ -0,0 +1,310 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"io"
+	"path"
+	"text/template"
+
+	"k8s.io/gengo/generator"
+)
+
+type testClientGenerator struct {
+	generator.DefaultGen
+	apiversion *APIVersion
+	apigroup   *APIGroup
+}
+
+var _ generator.Generator = &testClientGenerator{}
+
+// CreateTestClientGenerator generates the testclient package of apiversion, holding a client of each
+// resource issuing the requests of the tests through the storage of the apiserver
+func CreateTestClientGenerator(apiversion *APIVersion, apigroup *APIGroup, filename string) generator.Generator {
+	return &testClientGenerator{
+		generator.DefaultGen{OptionalName: filename},
+		apiversion,
+		apigroup,
+	}
+}
+
+// hasStoredResources returns true if a resource of version is served by the generated storage rather than
+// a REST implementation or not served by version
+func hasStoredResources(version *APIVersion) bool {
+	for _, v := range version.Resources {
+		if len(v.REST) == 0 && !v.RetainConversion {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *testClientGenerator) Imports(c *generator.Context) []string {
+	return []string{
+		"context",
+		"fmt",
+		`metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`,
+		"k8s.io/apimachinery/pkg/apis/meta/internalversion",
+		"k8s.io/apimachinery/pkg/runtime",
+		"k8s.io/apiserver/pkg/endpoints/request",
+		"k8s.io/apiserver/pkg/registry/rest",
+		"sigs.k8s.io/apiserver-builder-alpha/pkg/builders",
+		d.apigroup.Pkg.Path,
+		d.apiversion.Pkg.Path,
+		// Registers the types of the group with the builders.Scheme
+		`_ "` + path.Join(d.apigroup.Pkg.Path, "install") + `"`,
+	}
+}
+
+func (d *testClientGenerator) Finalize(context *generator.Context, w io.Writer) error {
+	temp := template.Must(template.New("testclient-template").Funcs(templateFuncs).Parse(TestClientTemplate))
+	template.Must(temp.Parse(TestClientResourceTemplates))
+	return executeTemplate(w, "testclient", temp, d.apiversion)
+}
+
+var TestClientTemplate = `
+// The clients convert the {{.Version}} objects to and from the internal objects of the storage of the
+// apiserver, so the apiserver must have been built, e.g. by starting it in the test.  The objects are
+// defaulted and validated as for http requests, admission plugins are not run.
+
+{{ range $api := served .Resources -}}
+{{ template "testclient-resource" $api }}
+{{ end -}}
+`
+
+// TestClientResourceTemplates are the templates of the client of a resource of the testclient package, executed
+// with the resource either by the TestClientTemplate or, for a resource with a build tag, by the
+// TaggedTestClientTemplate
+var TestClientResourceTemplates = `
+{{ define "testclient-resource" -}}
+{{ $api := . -}}
+{{ if not $api.REST -}}
+// {{ $api.Kind }}Client creates and reads {{ $api.Resource }} through the storage of the apiserver
+type {{ $api.Kind }}Client struct {
+	storage   builders.StandardStorageProvider
+	namespace string
+	// PageSize is the number of {{ $api.Resource }} listed per page by ForEach, 500 if unset
+	PageSize int64
+}
+
+{{ if $api.NonNamespaced -}}
+// {{ plural $api.Type }} returns a client of the {{ $api.Resource }}
+func {{ plural $api.Type }}() *{{ $api.Kind }}Client {
+	return &{{ $api.Kind }}Client{storage: {{ $api.Group }}.{{ $api.Group|public }}{{ $api.Kind }}Storage}
+}
+{{ else -}}
+// {{ plural $api.Type }} returns a client of the {{ $api.Resource }} of namespace
+func {{ plural $api.Type }}(namespace string) *{{ $api.Kind }}Client {
+	return &{{ $api.Kind }}Client{storage: {{ $api.Group }}.{{ $api.Group|public }}{{ $api.Kind }}Storage, namespace: namespace}
+}
+{{ end }}
+func (c *{{ $api.Kind }}Client) standardStorage(ctx context.Context) (context.Context, rest.StandardStorage, error) {
+	st := c.storage.GetStandardStorage()
+	if st == nil {
+		return nil, nil, fmt.Errorf("the storage of {{ $api.Resource }} is not built, start the apiserver first")
+	}
+	return request.WithNamespace(ctx, c.namespace), st, nil
+}
+
+// Create creates obj, defaulted as the body of a create request, and returns the created {{ $api.Kind }}
+func (c *{{ $api.Kind }}Client) Create(obj *{{ $api.Version }}.{{ $api.Kind }}) (*{{ $api.Version }}.{{ $api.Kind }}, error) {
+	ctx, st, err := c.standardStorage(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	obj = obj.DeepCopy()
+	builders.Scheme.Default(obj)
+	internal := &{{ $api.Group }}.{{ $api.Kind }}{}
+	if err := builders.Scheme.Convert(obj, internal, nil); err != nil {
+		return nil, err
+	}
+	created, err := st.Create(ctx, internal, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := &{{ $api.Version }}.{{ $api.Kind }}{}
+	return out, c.convert(created, out)
+}
+
+// Get returns the {{ $api.Kind }} named name
+func (c *{{ $api.Kind }}Client) Get(name string) (*{{ $api.Version }}.{{ $api.Kind }}, error) {
+	ctx, st, err := c.standardStorage(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	obj, err := st.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := &{{ $api.Version }}.{{ $api.Kind }}{}
+	return out, c.convert(obj, out)
+}
+
+// List returns the {{ $api.Resource }}
+func (c *{{ $api.Kind }}Client) List() (*{{ $api.Version }}.{{ $api.Kind }}List, error) {
+	ctx, st, err := c.standardStorage(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	obj, err := st.List(ctx, &internalversion.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := &{{ $api.Version }}.{{ $api.Kind }}List{}
+	return out, c.convert(obj, out)
+}
+
+// ForEach calls fn with each of the {{ $api.Resource }}, listed in pages of PageSize {{ $api.Resource }} by following
+// the continue tokens of the lists, until fn returns an error
+func (c *{{ $api.Kind }}Client) ForEach(ctx context.Context, fn func(*{{ $api.Version }}.{{ $api.Kind }}) error) error {
+	ctx, st, err := c.standardStorage(ctx)
+	if err != nil {
+		return err
+	}
+	options := &internalversion.ListOptions{Limit: c.PageSize}
+	if options.Limit <= 0 {
+		options.Limit = 500
+	}
+	for {
+		obj, err := st.List(ctx, options)
+		if err != nil {
+			return err
+		}
+		page := &{{ $api.Version }}.{{ $api.Kind }}List{}
+		if err := c.convert(obj, page); err != nil {
+			return err
+		}
+		for i := range page.Items {
+			if err := fn(&page.Items[i]); err != nil {
+				return err
+			}
+		}
+		if len(page.Continue) == 0 {
+			return nil
+		}
+		options.Continue = page.Continue
+	}
+}
+
+// JSONPatch applies the JSON patch, written against {{ $api.Version }}, to the {{ $api.Kind }} named name and returns
+// the patched {{ $api.Kind }}.  The paths of the patch are those of {{ $api.Version }}, the stored {{ $api.Kind }} is
+// converted to {{ $api.Version }} before applying the patch.
+func (c *{{ $api.Kind }}Client) JSONPatch(name string, patch []byte) (*{{ $api.Version }}.{{ $api.Kind }}, error) {
+	ctx, st, err := c.standardStorage(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	info := builders.NewJSONPatchObjectInfo(patch, func() runtime.Object { return &{{ $api.Version }}.{{ $api.Kind }}{} })
+	patched, _, err := st.Update(ctx, name, info, rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := &{{ $api.Version }}.{{ $api.Kind }}{}
+	return out, c.convert(patched, out)
+}
+
+// UpdateStatus updates the status of obj through the {{ $api.Resource }}/status endpoint, leaving the rest of the
+// {{ $api.Kind }} unchanged, and returns the updated {{ $api.Kind }}
+func (c *{{ $api.Kind }}Client) UpdateStatus(obj *{{ $api.Version }}.{{ $api.Kind }}) (*{{ $api.Version }}.{{ $api.Kind }}, error) {
+	ctx, st, err := c.endpointStorage({{ $api.Version }}.{{ $api.Kind }}StatusStorage, "status")
+	if err != nil {
+		return nil, err
+	}
+	obj = obj.DeepCopy()
+	builders.Scheme.Default(obj)
+	internal := &{{ $api.Group }}.{{ $api.Kind }}{}
+	if err := builders.Scheme.Convert(obj, internal, nil); err != nil {
+		return nil, err
+	}
+	updated, _, err := st.(rest.Updater).Update(ctx, obj.Name, rest.DefaultUpdatedObjectInfo(internal),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := &{{ $api.Version }}.{{ $api.Kind }}{}
+	return out, c.convert(updated, out)
+}
+{{ range $subresource := $api.Subresources -}}
+{{ if and (eq $subresource.Path "scale") (not $subresource.ImportPackage) }}
+// GetScale returns the scale of the {{ $api.Kind }} named name, read through the {{ $api.Resource }}/scale endpoint
+func (c *{{ $api.Kind }}Client) GetScale(name string) (*{{ $api.Version }}.{{ $subresource.Request }}, error) {
+	ctx, st, err := c.endpointStorage({{ $api.Version }}.{{ $api.Kind }}ScaleStorage, "scale")
+	if err != nil {
+		return nil, err
+	}
+	getter, ok := st.(rest.Getter)
+	if !ok {
+		return nil, fmt.Errorf("{{ $api.Resource }}/scale does not support get")
+	}
+	obj, err := getter.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := &{{ $api.Version }}.{{ $subresource.Request }}{}
+	return out, c.convert(obj, out)
+}
+
+// UpdateScale updates the scale of the {{ $api.Kind }} named scale.Name through the {{ $api.Resource }}/scale endpoint
+// and returns the updated scale
+func (c *{{ $api.Kind }}Client) UpdateScale(scale *{{ $api.Version }}.{{ $subresource.Request }}) (*{{ $api.Version }}.{{ $subresource.Request }}, error) {
+	ctx, st, err := c.endpointStorage({{ $api.Version }}.{{ $api.Kind }}ScaleStorage, "scale")
+	if err != nil {
+		return nil, err
+	}
+	updater, ok := st.(rest.Updater)
+	if !ok {
+		return nil, fmt.Errorf("{{ $api.Resource }}/scale does not support update")
+	}
+	internal := &{{ $api.Group }}.{{ $subresource.Request }}{}
+	if err := builders.Scheme.Convert(scale, internal, nil); err != nil {
+		return nil, err
+	}
+	updated, _, err := updater.Update(ctx, scale.Name, rest.DefaultUpdatedObjectInfo(internal),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	out := &{{ $api.Version }}.{{ $subresource.Request }}{}
+	return out, c.convert(updated, out)
+}
+{{ end -}}
+{{ end }}
+// endpointStorage returns the storage of the {{ $api.Resource }}/path endpoint of the apiserver
+func (c *{{ $api.Kind }}Client) endpointStorage(provider builders.StorageProvider, path string) (context.Context, rest.Storage, error) {
+	st := provider.GetStorage()
+	if st == nil {
+		return nil, nil, fmt.Errorf("the storage of {{ $api.Resource }}/%s is not built, start the apiserver first", path)
+	}
+	return request.WithNamespace(context.Background(), c.namespace), st, nil
+}
+
+// convert converts the internal object in to the {{ $api.Version }} object out, setting its apiVersion and kind
+func (c *{{ $api.Kind }}Client) convert(in runtime.Object, out runtime.Object) error {
+	if err := builders.Scheme.Convert(in, out, nil); err != nil {
+		return err
+	}
+	gvks, _, err := builders.Scheme.ObjectKinds(out)
+	if err != nil {
+		return err
+	}
+	out.GetObjectKind().SetGroupVersionKind(gvks[0])
+	return nil
+}
+
+{{ end -}}
+{{ end }}
+`
@@ -0,0 +1,267 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+)
+
+// typesModuleAPIMachinery is the module required by the types of every types module
+const typesModuleAPIMachinery = "k8s.io/apimachinery"
+
+// WriteTypesModule writes the versioned type packages of apis to dir as a go module of path modulePath
+// which builds independently of the apiserver.  Each version package gets its *_types.go files, its
+// zz_generated.deepcopy.go file and a generated zz_generated.register.go file declaring the lists of the
+// resources and registering the types with a scheme.  The packages of apis imported by the types, e.g. a
+// package of shared constants, are copied as is.  The imports of the copied packages are rewritten to
+// modulePath, and the go.mod requires the modules of the other packages they import, i.e. apimachinery
+// and e.g. k8s.io/api, at the versions of the current module.
+func WriteTypesModule(apis *APIs, dir, modulePath string, boilerplate []byte) {
+	if len(modulePath) == 0 {
+		klog.Fatalf("--types-module-path is required to write the types module to %s", dir)
+	}
+	rel := func(pkg string) string {
+		return strings.TrimPrefix(strings.TrimPrefix(pkg, apis.Package), "/")
+	}
+
+	// The version packages and the packages of apis they import, keyed by import path
+	sources := map[string][]string{}
+	for _, group := range apis.Groups {
+		for _, version := range group.Versions {
+			sources[version.Pkg.Path] = typesModuleFiles(version.Pkg.SourcePath, func(name string) bool {
+				return strings.HasSuffix(name, "_types.go") || name == "zz_generated.deepcopy.go"
+			})
+		}
+	}
+	external := sets.NewString(typesModuleAPIMachinery + "/pkg/runtime")
+	files := map[string][]byte{}
+	for pending := sets.StringKeySet(sources).List(); len(pending) > 0; pending = pending[1:] {
+		pkg := pending[0]
+		for _, file := range sources[pkg] {
+			src, imports := rewriteTypesModuleImports(file, apis.Package, modulePath)
+			files[filepath.Join(rel(pkg), filepath.Base(file))] = src
+			for _, imported := range imports {
+				switch {
+				case imported == apis.Package || strings.HasPrefix(imported, apis.Package+"/"):
+					if _, found := sources[imported]; !found {
+						sources[imported] = typesModuleFiles(filepath.Join(apis.Pkg.SourcePath, rel(imported)),
+							func(string) bool { return true })
+						pending = append(pending, imported)
+					}
+				case strings.Contains(strings.Split(imported, "/")[0], "."):
+					external.Insert(imported)
+				}
+			}
+		}
+	}
+
+	for _, group := range apis.Groups {
+		for _, version := range group.Versions {
+			var b bytes.Buffer
+			b.Write(boilerplate)
+			temp := template.Must(template.New("types-module-register-template").Funcs(templateFuncs).Parse(TypesModuleRegisterTemplate))
+			if err := executeTemplate(&b, "types-module-register", temp, version); err != nil {
+				klog.Fatalf("failed to generate the register file of %s: %v", version.Pkg.Path, err)
+			}
+			src, err := format.Source(b.Bytes())
+			if err != nil {
+				klog.Fatalf("failed to format the register file of %s: %v", version.Pkg.Path, err)
+			}
+			files[filepath.Join(rel(version.Pkg.Path), "zz_generated.register.go")] = src
+		}
+	}
+
+	requires := typesModuleRequires(external.List())
+	goMod := fmt.Sprintf("module %s\n\ngo 1.13\n\nrequire (\n", modulePath)
+	for _, module := range sets.StringKeySet(requires).List() {
+		goMod += fmt.Sprintf("\t%s %s\n", module, requires[module])
+	}
+	files["go.mod"] = []byte(goMod + ")\n")
+	// The go.sum of the current module holds the checksums of the required modules and their dependencies
+	if goSum, err := ioutil.ReadFile(filepath.Join(goModDir(), "go.sum")); err == nil {
+		files["go.sum"] = goSum
+	}
+
+	for name, src := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+			klog.Fatalf("failed to create %s: %v", filepath.Dir(file), err)
+		}
+		if err := ioutil.WriteFile(file, src, 0644); err != nil {
+			klog.Fatalf("failed to write %s: %v", file, err)
+		}
+	}
+}
+
+// typesModuleFiles returns the go files of the package in dir, other than tests, matching include
+func typesModuleFiles(dir string, include func(name string) bool) []string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		klog.Fatalf("failed to read %s: %v", dir, err)
+	}
+	files := []string{}
+	for _, info := range infos {
+		name := info.Name()
+		if !info.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") && include(name) {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	return files
+}
+
+// rewriteTypesModuleImports returns the source of the go file with the imports of the packages of
+// apisPackage rewritten to modulePath, and the paths of its original imports
+func rewriteTypesModuleImports(file, apisPackage, modulePath string) ([]byte, []string) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+	if err != nil {
+		klog.Fatalf("failed to parse %s: %v", file, err)
+	}
+	imports := []string{}
+	for _, spec := range f.Imports {
+		imported, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			klog.Fatalf("failed to parse the imports of %s: %v", file, err)
+		}
+		imports = append(imports, imported)
+		if imported == apisPackage || strings.HasPrefix(imported, apisPackage+"/") {
+			spec.Path.Value = strconv.Quote(modulePath + strings.TrimPrefix(imported, apisPackage))
+		}
+	}
+	// The import comment of the package clause would require the original import path
+	for _, group := range f.Comments {
+		if group.Pos() > f.Name.End() && fset.Position(group.Pos()).Line == fset.Position(f.Name.End()).Line {
+			group.List = []*ast.Comment{}
+		}
+	}
+	var b bytes.Buffer
+	if err := format.Node(&b, fset, f); err != nil {
+		klog.Fatalf("failed to format %s: %v", file, err)
+	}
+	return b.Bytes(), imports
+}
+
+// typesModuleRequires returns the versions of the modules of the packages, keyed by module path, as resolved
+// by the current module.  The packages of replaced modules and of the current module fail the generation
+// since they would not resolve from the types module.
+func typesModuleRequires(packages []string) map[string]string {
+	args := append([]string{"list", "-f", "{{.ImportPath}}|{{with .Module}}{{.Path}}|{{.Version}}|{{if .Replace}}replaced{{end}}{{end}}"}, packages...)
+	cmd := exec.Command("go", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		klog.Fatalf("failed to list the modules of the packages imported by the types: %v\n%s", err, stderr.String())
+	}
+	requires := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 4 || len(fields[2]) == 0 || len(fields[3]) > 0 {
+			klog.Fatalf("the types import %s, which is not provided by a versioned module", fields[0])
+		}
+		requires[fields[1]] = fields[2]
+	}
+	if _, found := requires[typesModuleAPIMachinery]; !found {
+		klog.Fatalf("the current module does not require %s", typesModuleAPIMachinery)
+	}
+	return requires
+}
+
+// goModDir returns the directory of the go.mod of the current module
+func goModDir() string {
+	out, err := exec.Command("go", "env", "GOMOD").Output()
+	if err != nil {
+		klog.Fatalf("failed to find the go.mod of the current module: %v", err)
+	}
+	return path.Dir(strings.TrimSpace(string(out)))
+}
+
+var TypesModuleRegisterTemplate = `
+// This file registers the types of the package with a scheme, independently of the apiserver
+
+package {{ .Version }}
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is the group version of the types of the package
+var SchemeGroupVersion = schema.GroupVersion{Group: "{{ .Group }}.{{ .Domain }}", Version: "{{ .Version }}"}
+
+var (
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme registers the types of the package with a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+{{ range $api := .Resources -}}
+		&{{ $api.Kind }}{},
+		&{{ $api.Kind }}List{},
+  {{ range $subresource := $api.Subresources -}}
+		&{{ $subresource.Kind }}{},
+  {{ end -}}
+{{ end -}}
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
+
+// Kind takes an unqualified kind and returns a Group qualified GroupKind
+func Kind(kind string) schema.GroupKind {
+	return SchemeGroupVersion.WithKind(kind).GroupKind()
+}
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+{{ range $api := .Resources }}
+// {{ $api.Kind }}List is a list of {{ $api.Kind }}
+type {{ $api.Kind }}List struct {
+	metav1.TypeMeta ` + "`json:\",inline\"`" + `
+	metav1.ListMeta ` + "`json:\"metadata,omitempty\"`" + `
+	Items           []{{ $api.Kind }} ` + "`json:\"items\"`" + `
+}
+{{ range $subresource := $api.Subresources }}
+// {{ $subresource.Request }}List is a list of {{ $subresource.Request }}
+type {{ $subresource.Request }}List struct {
+	metav1.TypeMeta ` + "`json:\",inline\"`" + `
+	metav1.ListMeta ` + "`json:\"metadata,omitempty\"`" + `
+	Items           []{{ $subresource.Request }} ` + "`json:\"items\"`" + `
+}
+{{ end }}{{ end -}}
+`
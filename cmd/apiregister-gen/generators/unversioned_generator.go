@@ -22,20 +22,21 @@ import (
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/gengo/generator"
-	"k8s.io/gengo/namer"
 )
 
 type unversionedGenerator struct {
 	generator.DefaultGen
-	apigroup *APIGroup
+	apigroup  *APIGroup
+	emitTests bool
 }
 
 var _ generator.Generator = &unversionedGenerator{}
 
-func CreateUnversionedGenerator(apigroup *APIGroup, filename string) generator.Generator {
+func CreateUnversionedGenerator(apigroup *APIGroup, filename string, emitTests bool) generator.Generator {
 	return &unversionedGenerator{
 		generator.DefaultGen{OptionalName: filename},
 		apigroup,
+		emitTests,
 	}
 }
 
@@ -43,11 +44,41 @@ func (d *unversionedGenerator) Imports(c *generator.Context) []string {
 	imports := sets.NewString(
 		"fmt",
 		"context",
+		"github.com/spf13/pflag",
 		"sigs.k8s.io/apiserver-builder-alpha/pkg/builders",
 		"k8s.io/apimachinery/pkg/apis/meta/internalversion",
+		"metav1 \"k8s.io/apimachinery/pkg/apis/meta/v1\"",
 		"k8s.io/apimachinery/pkg/runtime",
 		"k8s.io/apimachinery/pkg/runtime/schema",
-		"k8s.io/apiserver/pkg/registry/rest")
+		"k8s.io/apimachinery/pkg/watch",
+		"k8s.io/apiserver/pkg/admission",
+		"k8s.io/apiserver/pkg/registry/rest",
+		"net/http",
+		"apiequality \"k8s.io/apimachinery/pkg/api/equality\"",
+		"apierrors \"k8s.io/apimachinery/pkg/api/errors\"",
+		"k8s.io/apimachinery/pkg/util/validation/field",
+		"utilruntime \"k8s.io/apimachinery/pkg/util/runtime\"",
+		"utilfeature \"k8s.io/apiserver/pkg/util/feature\"",
+		"k8s.io/component-base/featuregate")
+	if d.emitTests {
+		imports.Insert(
+			"io/ioutil",
+			"path/filepath",
+			"strings",
+			"goruntime \"runtime\"")
+	}
+
+	for _, r := range d.apigroup.UnversionedResources {
+		if len(r.MetricsLabels) > 0 {
+			imports.Insert("k8s.io/component-base/metrics/legacyregistry")
+		}
+		if len(r.CustomMetrics) > 0 {
+			imports.Insert("k8s.io/apimachinery/pkg/api/resource")
+		}
+		if len(r.REST) == 0 && len(r.NameValidation) == 0 {
+			imports.Insert("apivalidation \"k8s.io/apimachinery/pkg/api/validation\"")
+		}
+	}
 
 	// Get imports for all fields
 	for _, s := range d.apigroup.Structs {
@@ -55,6 +86,7 @@ func (d *unversionedGenerator) Imports(c *generator.Context) []string {
 			if len(f.UnversionedImport) > 0 {
 				imports.Insert(f.UnversionedImport)
 			}
+			imports.Insert(f.UnversionedImports...)
 		}
 	}
 
@@ -63,68 +95,54 @@ func (d *unversionedGenerator) Imports(c *generator.Context) []string {
 
 func (d *unversionedGenerator) Finalize(context *generator.Context, w io.Writer) error {
 	temp := template.
-		Must(template.New("unversioned-wiring-template").Funcs(map[string]interface{}{
-			"public": namer.IC,
-		}).Parse(UnversionedAPITemplate))
+		Must(template.New("unversioned-wiring-template").Funcs(templateFuncs).Parse(UnversionedAPITemplate))
+	template.Must(temp.Parse(UnversionedResourceTemplates))
 
-	err := temp.Execute(w, d.apigroup)
+	err := executeTemplate(w, "unversioned", temp, d.apigroup)
 	if err != nil {
 		return err
 	}
+	if d.emitTests {
+		err = template.Must(template.New("fixture-loader-template").Parse(FixtureLoaderTemplate)).
+			Execute(w, d.apigroup)
+	}
 	return err
 }
 
+var FixtureLoaderTemplate = `
+// LoadFixture decodes the versioned {{.Group}} object of the given version and kind checked in as the
+// testdata/<version>/<lowercase kind>.json file of the {{.Group}} package, e.g. testdata/v1/foo.json
+func LoadFixture(version, kind string) (runtime.Object, error) {
+	_, file, _, ok := goruntime.Caller(0)
+	if !ok {
+		return nil, fmt.Errorf("unable to find the testdata directory of the {{.Group}} package")
+	}
+	path := filepath.Join(filepath.Dir(file), "testdata", version, strings.ToLower(kind)+".json")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	gvk := schema.GroupVersionKind{Group: "{{.Group}}.{{.Domain}}", Version: version, Kind: kind}
+	obj, actual, err := builders.Codecs.UniversalDeserializer().Decode(data, &gvk, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	if *actual != gvk {
+		return nil, fmt.Errorf("%s contains %v, expected %v", path, *actual, gvk)
+	}
+	return obj, nil
+}
+`
+
 var UnversionedAPITemplate = `
 var (
-	{{ range $api := .UnversionedResources -}}
-	{{ if $api.REST -}}
-		{{$api.Group|public}}{{$api.Kind}}Storage = builders.NewApiResourceWithStorage( // Resource status endpoint
-			Internal{{ $api.Kind }},
-			func() runtime.Object { return &{{ $api.Kind }}{} },     // Register versioned resource
-			func() runtime.Object { return &{{ $api.Kind }}List{} }, // Register versioned resource list
-			New{{ $api.REST }},
-		)
-	{{ else -}}
-		{{$api.Group|public}}{{$api.Kind}}Storage = builders.NewApiResource( // Resource status endpoint
-			Internal{{ $api.Kind }},
-			func() runtime.Object { return &{{ $api.Kind }}{} },     // Register versioned resource
-			func() runtime.Object { return &{{ $api.Kind }}List{} }, // Register versioned resource list
-			&{{ $api.Strategy }}{builders.StorageStrategySingleton},
-		)
-	{{ end -}}
-	{{ end -}}
-	{{ range $api := .UnversionedResources -}}
-	{{- if $api.ShortName -}}
-	Internal{{ $api.Kind }} = builders.NewInternalResourceWithShortcuts(
-	{{ else -}}
-	Internal{{ $api.Kind }} = builders.NewInternalResource(
-	{{ end -}}
-		"{{ $api.Resource }}",
-        "{{ $api.Kind }}",
-		func() runtime.Object { return &{{ $api.Kind }}{} },
-		func() runtime.Object { return &{{ $api.Kind }}List{} },
-	{{ if $api.ShortName -}}
-		[]string{"{{ $api.ShortName }}"},
-		[]string{"aggregation"}, // TBD
-	{{ end -}}
-	)
-	Internal{{ $api.Kind }}Status = builders.NewInternalResourceStatus(
-		"{{ $api.Resource }}",
-        "{{ $api.Kind }}Status",
-		func() runtime.Object { return &{{ $api.Kind }}{} },
-		func() runtime.Object { return &{{ $api.Kind }}List{} },
-	)
-	{{ range $subresource := .Subresources -}}
-	Internal{{$subresource.Kind}}REST = builders.NewInternalSubresource(
-		"{{$subresource.Resource}}", "{{$subresource.Request}}", "{{$subresource.Path}}",
-		func() runtime.Object { return &{{$subresource.Request}}{} },
-	)
-	{{ end -}}
+	{{ range $api := untagged .UnversionedResources -}}
+	{{ template "unversioned-resource-storage" $api }}
 	{{ end -}}
 
 	// Registered resources and subresources
 	ApiVersion = builders.NewApiGroup("{{.Group}}.{{.Domain}}").WithKinds(
-		{{ range $api := .UnversionedResources -}}
+		{{ range $api := untagged .UnversionedResources -}}
 		Internal{{$api.Kind}},
 		Internal{{$api.Kind}}Status,
 		{{ range $subresource := $api.Subresources -}}
@@ -143,6 +161,142 @@ var (
 	SchemeGroupVersion = ApiVersion.GroupVersion
 )
 
+// VersionPriority lists the versions of the {{.Group}} group from the most to the least preferred, GA versions
+// before beta versions before alpha versions and higher numbers first.  Discovery presents the versions in this
+// order and the resources are stored in the first version serving them.
+var VersionPriority = []schema.GroupVersion{
+	{{ range $version := .VersionPriority -}}
+	{Group: "{{ $.Group }}.{{ $.Domain }}", Version: "{{ $version }}"},
+	{{ end -}}
+}
+
+// StorageMediaTypes are the media types of the resources of the {{.Group}} group stored in etcd with a media
+// type other than the --storage-media-type of the apiserver
+var StorageMediaTypes = map[schema.GroupResource]string{
+	{{ range $api := untagged .UnversionedResources -}}
+	{{ if $api.StorageMediaType -}}
+	Resource("{{ $api.Resource }}"): "{{ $api.StorageMediaType }}",
+	{{ end -}}
+	{{ end -}}
+}
+
+// DefaultGCPolicy are the propagation policies of deletes of the resources of the {{.Group}} group that do not
+// set one, resources without a policy delete their dependents
+var DefaultGCPolicy = map[schema.GroupResource]metav1.DeletionPropagation{
+	{{ range $api := untagged .UnversionedResources -}}
+	{{ if $api.OrphanDependents -}}
+	Resource("{{ $api.Resource }}"): metav1.DeletePropagationOrphan,
+	{{ end -}}
+	{{ end -}}
+}
+
+// AdmissionPlugins are the admission plugins scoped to the resources of the {{.Group}} group
+var AdmissionPlugins = map[string]admission.Interface{
+	{{ range $api := untagged .UnversionedResources -}}
+	{{ range $func := $api.ValidatingAdmission -}}
+	"{{ public $.Group }}{{ $func }}": builders.NewValidatingAdmissionPlugin(Resource("{{ $api.Resource }}"),
+		func(ctx context.Context, obj runtime.Object, a admission.Attributes) error {
+			o, ok := obj.(*{{ $api.Kind }})
+			if !ok {
+				return fmt.Errorf("expected *{{ $api.Kind }}, got %T", obj)
+			}
+			return {{ $func }}(ctx, o, a)
+		}),
+	{{ end -}}
+	{{ end -}}
+}
+
+// FeatureGates are the feature gates of the resources of the {{.Group}} group, added to the --feature-gates
+// flag of the apiserver.  A resource is not served while its feature gate is disabled.
+var FeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	{{ range $name, $enabled := .FeatureGates -}}
+	"{{ $name }}": {Default: {{ $enabled }}, PreRelease: featuregate.{{ if $enabled }}Beta{{ else }}Alpha{{ end }}},
+	{{ end -}}
+}
+
+func init() {
+	utilruntime.Must(utilfeature.DefaultMutableFeatureGate.Add(FeatureGates))
+	{{ range $api := untagged .UnversionedResources -}}
+	{{ if $api.MetricsLabels -}}
+	legacyregistry.CustomMustRegister({{ $api.Kind }}MetricsCollector)
+	{{ end -}}
+	{{ if $api.CustomMetrics -}}
+	builders.AddCustomMetrics({{ $api.Kind }}CustomMetrics...)
+	{{ end -}}
+	{{ end -}}
+}
+
+// Handlers are served under each version of the {{.Group}} group, the features handler lists whether each
+// of the FeatureGates is enabled
+var Handlers = map[string]http.Handler{
+	"features": builders.NewFeaturesHandler(FeatureGates, utilfeature.DefaultFeatureGate),
+}
+
+// ConversionReviewHandler is a conversion webhook handler converting the {{.Group}} objects of the
+// ConversionReviews of a CustomResourceDefinition to the desired version, with its defaults applied
+var ConversionReviewHandler = builders.NewConversionReviewHandler("{{.Group}}.{{.Domain}}")
+
+// OpenAPIExamples are the examples of the "+example" comments of the types of the {{.Group}} group, keyed
+// by the name of their OpenAPI definition
+var OpenAPIExamples = map[string]string{
+	{{ range $name, $example := .OpenAPIExamples -}}
+	{{ printf "%q" $name }}: {{ printf "%q" $example }},
+	{{ end -}}
+}
+
+// OpenAPIUIDescriptors are the descriptors of the "+uiDescriptor" comments of the fields of the types of the
+// {{.Group}} group, keyed by the name of their OpenAPI definition and the json name of the field
+var OpenAPIUIDescriptors = map[string]map[string][]string{
+	{{ range $name, $fields := .OpenAPIUIDescriptors -}}
+	{{ printf "%q" $name }}: {
+		{{ range $field, $descriptors := $fields -}}
+		{{ printf "%q" $field }}: { {{- range $descriptor := $descriptors }}{{ printf "%q" $descriptor }}, {{ end -}} },
+		{{ end -}}
+	},
+	{{ end -}}
+}
+
+// ResourceOptions enables and disables serving the resources of the {{.Group}} group
+// +k8s:deepcopy-gen=false
+type ResourceOptions struct {
+	{{ range $api := .UnversionedResources -}}
+	// Enable{{ $api.Kind }} serves the {{ $api.Resource }} resource when true
+	Enable{{ $api.Kind }} bool
+	{{ end -}}
+}
+
+// NewResourceOptions returns ResourceOptions serving all resources of the {{.Group}} group
+func NewResourceOptions() *ResourceOptions {
+	return &ResourceOptions{
+		{{ range $api := .UnversionedResources -}}
+		Enable{{ $api.Kind }}: true,
+		{{ end -}}
+	}
+}
+
+// AddFlags adds an --enable-<resource> flag for each resource of the {{.Group}} group to fs
+func (o *ResourceOptions) AddFlags(fs *pflag.FlagSet) {
+	{{ range $api := .UnversionedResources -}}
+	fs.BoolVar(&o.Enable{{ $api.Kind }}, "enable-{{ $api.Resource }}", o.Enable{{ $api.Kind }},
+		"Serve the {{ $api.Resource }} resource of the {{ $.Group }}.{{ $.Domain }} group")
+	{{ end -}}
+}
+
+// DisabledResources returns the resources of the {{.Group}} group which should not be served, either disabled
+// by their flag or by their feature gate
+func (o *ResourceOptions) DisabledResources() []string {
+	disabled := []string{}
+	{{ range $api := .UnversionedResources -}}
+	if !o.Enable{{ $api.Kind }}{{ if $api.FeatureGate }} || !utilfeature.DefaultFeatureGate.Enabled("{{ $api.FeatureGate }}"){{ end }} {
+		disabled = append(disabled, "{{ $api.Resource }}")
+	}
+	{{ end -}}
+	return disabled
+}
+
+{{ range $api := untagged .UnversionedResources -}}
+{{ template "unversioned-resource-serving" $api }}
+{{ end -}}
 // Required by code generated by go2idl
 // Kind takes an unqualified kind and returns a Group qualified GroupKind
 func Kind(kind string) schema.GroupKind {
@@ -177,16 +331,6 @@ type {{ $s.Name }} struct {
 //
 // {{.Kind}} Functions and Structs
 //
-// +k8s:deepcopy-gen=false
-type {{.Strategy}} struct {
-	builders.DefaultStorageStrategy
-}
-
-// +k8s:deepcopy-gen=false
-type {{.StatusStrategy}} struct {
-	builders.DefaultStatusStorageStrategy
-}
-
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type {{$api.Kind}}List struct {
@@ -237,10 +381,349 @@ func (pc {{$api.Kind}}) GetGeneration() int64 {
 	return pc.ObjectMeta.Generation
 }
 
+// {{.Kind}}SemanticEqual reports whether a and b, {{.Kind}}s of any version of the {{$.Group}} group, have the same
+// spec, labels and annotations once converted to the internal version.  The status and the metadata set by
+// the apiserver, e.g. the resourceVersion, are not compared.
+func {{.Kind}}SemanticEqual(a, b runtime.Object) (bool, error) {
+	internalA, internalB := &{{.Kind}}{}, &{{.Kind}}{}
+	if err := builders.Scheme.Convert(a, internalA, nil); err != nil {
+		return false, err
+	}
+	if err := builders.Scheme.Convert(b, internalB, nil); err != nil {
+		return false, err
+	}
+	return apiequality.Semantic.DeepEqual(internalA.Spec, internalB.Spec) &&
+		apiequality.Semantic.DeepEqual(internalA.Labels, internalB.Labels) &&
+		apiequality.Semantic.DeepEqual(internalA.Annotations, internalB.Annotations), nil
+}
+
+// {{.Kind}}SpecEqual reports whether the {{.Kind}}s a and b have semantically equal specs, e.g. for a controller
+// to detect the drift of the observed {{.Kind}} from the desired one.  The metadata and status are not compared.
+func {{.Kind}}SpecEqual(a, b *{{.Kind}}) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return apiequality.Semantic.DeepEqual(a.Spec, b.Spec)
+}
+
+// New{{.Kind}}NotFound returns the NotFound error of the {{.Kind}} name
+func New{{.Kind}}NotFound(name string) *apierrors.StatusError {
+	return apierrors.NewNotFound(Resource("{{ $api.Resource }}"), name)
+}
+
+// New{{.Kind}}AlreadyExists returns the AlreadyExists error of the {{.Kind}} name
+func New{{.Kind}}AlreadyExists(name string) *apierrors.StatusError {
+	return apierrors.NewAlreadyExists(Resource("{{ $api.Resource }}"), name)
+}
+
+// New{{.Kind}}Conflict returns the Conflict error of an update of the {{.Kind}} name failing with err
+func New{{.Kind}}Conflict(name string, err error) *apierrors.StatusError {
+	return apierrors.NewConflict(Resource("{{ $api.Resource }}"), name, err)
+}
+
+// New{{.Kind}}Invalid returns the Invalid error of the {{.Kind}} name failing validation with errs
+func New{{.Kind}}Invalid(name string, errs field.ErrorList) *apierrors.StatusError {
+	return apierrors.NewInvalid(Kind("{{ .Kind }}"), name, errs)
+}
+
+{{ end -}}
+`
+
+// UnversionedResourceTemplates are the templates of the code serving a resource of the unversioned package,
+// executed with the resource either by the UnversionedAPITemplate or, for a resource with a build tag, by the
+// TaggedUnversionedAPITemplate
+var UnversionedResourceTemplates = `
+{{ define "unversioned-resource-storage" -}}
+{{ $api := . -}}
+	{{ if $api.REST -}}
+		{{$api.Group|public}}{{$api.Kind}}Storage = builders.NewApiResourceWithStorage( // Resource status endpoint
+			Internal{{ $api.Kind }},
+			func() runtime.Object { return &{{ $api.Kind }}{} },     // Register versioned resource
+			func() runtime.Object { return &{{ $api.Kind }}List{} }, // Register versioned resource list
+			New{{ $api.REST }},
+		)
+	{{ else -}}
+		{{$api.Group|public}}{{$api.Kind}}Storage = builders.NewApiResource( // Resource status endpoint
+			Internal{{ $api.Kind }},
+			func() runtime.Object { return &{{ $api.Kind }}{} },     // Register versioned resource
+			func() runtime.Object { return &{{ $api.Kind }}List{} }, // Register versioned resource list
+			{{ $api.StorageBuilder }},
+		)
+	{{ end -}}
+	{{ if $api.ShortName -}}
+	Internal{{ $api.Kind }} = builders.NewInternalResourceWithShortcuts(
+	{{ else -}}
+	Internal{{ $api.Kind }} = builders.NewInternalResource(
+	{{ end -}}
+		"{{ $api.Resource }}",
+        "{{ $api.Kind }}",
+		func() runtime.Object { return &{{ $api.Kind }}{} },
+		func() runtime.Object { return &{{ $api.Kind }}List{} },
+	{{ if $api.ShortName -}}
+		[]string{"{{ $api.ShortName }}"},
+		[]string{"aggregation"}, // TBD
+	{{ end -}}
+	)
+	Internal{{ $api.Kind }}Status = builders.NewInternalResourceStatus(
+		"{{ $api.Resource }}",
+        "{{ $api.Kind }}Status",
+		func() runtime.Object { return &{{ $api.Kind }}{} },
+		func() runtime.Object { return &{{ $api.Kind }}List{} },
+	)
+	{{ range $subresource := .Subresources -}}
+	Internal{{$subresource.Kind}}REST = builders.NewInternalSubresource(
+		"{{$subresource.Resource}}", "{{$subresource.Request}}", "{{$subresource.Path}}",
+		func() runtime.Object { return &{{$subresource.Request}}{} },
+	)
+	{{ end -}}
+{{ end }}
+
+{{ define "unversioned-resource-serving" -}}
+{{ $api := . -}}
+{{ if $api.PrintColumns -}}
+// {{ $api.Kind }}PrintColumns are the additional columns printed by "kubectl get {{ $api.Resource }}"
+var {{ $api.Kind }}PrintColumns = []builders.PrintColumn{
+	{{ range $column := $api.PrintColumns -}}
+	{
+		TableColumnDefinition: metav1.TableColumnDefinition{
+			Name:        {{ printf "%q" $column.Name }},
+			Type:        {{ printf "%q" $column.Type }},
+			Format:      {{ printf "%q" $column.Format }},
+			Description: {{ printf "%q" $column.Description }},
+			Priority:    {{ $column.Priority }},
+		},
+		// {{ $column.JSONPath }}
+		Value: func(obj runtime.Object) interface{} {
+			o, ok := obj.(*{{ $api.Kind }})
+			if !ok {
+				return nil
+			}
+			{{ range $guard := $column.Guards -}}
+			if {{ $guard }} {
+				return nil
+			}
+			{{ end -}}
+			return {{ $column.Value }}
+		},
+	},
+	{{ end -}}
+}
+
+{{ end -}}
+{{ if $api.MetricsLabels -}}
+// {{ $api.Kind }}MetricsCollector collects the {{ $api.Group }}_{{ $api.Resource }}_objects gauge counting the {{ $api.Resource }}
+// by namespace and by the fields of the "+metrics:label" comments, served under /metrics of the apiserver
+var {{ $api.Kind }}MetricsCollector = builders.NewResourceMetricsCollector(
+	"{{ $api.Group }}_{{ $api.Resource }}_objects",
+	"Number of {{ $api.Resource }} by namespace{{ range $label := $api.MetricsLabels }} and {{ $label.Path }}{{ end }}",
+	{{ $api.Group|public }}{{ $api.Kind }}Storage,
+	{{ range $label := $api.MetricsLabels -}}
+	builders.MetricsLabel{
+		Name: "{{ $label.Name }}",
+		// {{ $label.Path }}
+		Value: func(obj runtime.Object) string {
+			o, ok := obj.(*{{ $api.Kind }})
+			if !ok {
+				return ""
+			}
+			{{ range $guard := $label.Guards -}}
+			if {{ $guard }} {
+				return ""
+			}
+			{{ end -}}
+			return fmt.Sprint({{ $label.Value }})
+		},
+	},
+	{{ end -}}
+)
+
+{{ end -}}
+{{ if $api.CustomMetrics -}}
+// {{ $api.Kind }}CustomMetrics are the metrics of the "+metric" comments of {{ $api.Resource }}, served by the
+// custom.metrics.k8s.io API of the apiserver
+var {{ $api.Kind }}CustomMetrics = []builders.CustomMetric{
+	{{ range $metric := $api.CustomMetrics -}}
+	{
+		Name:            "{{ $metric.Name }}",
+		Resource:        schema.GroupResource{Group: "{{ $api.Group }}.{{ $api.Domain }}", Resource: "{{ $api.Resource }}"},
+		NamespaceScoped: {{ not $api.NonNamespaced }},
+		Storage:         {{ $api.Group|public }}{{ $api.Kind }}Storage,
+		// {{ $metric.Path }}
+		Value: func(obj runtime.Object) (resource.Quantity, bool) {
+			o, ok := obj.(*{{ $api.Kind }})
+			if !ok {
+				return resource.Quantity{}, false
+			}
+			{{ range $guard := $metric.Guards -}}
+			if {{ $guard }} {
+				return resource.Quantity{}, false
+			}
+			{{ end -}}
+			return {{ $metric.Value }}, true
+		},
+	},
+	{{ end -}}
+}
+
+{{ end -}}
+{{ if $api.FieldConstraints -}}
+// {{ $api.Kind }}FieldConstraints are the cross-field constraints validated for {{ $api.Resource }}
+var {{ $api.Kind }}FieldConstraints = []builders.FieldConstraint{
+	{{ range $constraint := $api.FieldConstraints -}}
+	{
+		Rule: builders.{{ $constraint.Rule }},
+		Fields: []builders.ConstrainedField{
+			{{ range $field := $constraint.Fields -}}
+			{
+				Path: {{ printf "%q" $field.Path }},
+				Value: func(obj runtime.Object) interface{} {
+					o, ok := obj.(*{{ $api.Kind }})
+					if !ok {
+						return nil
+					}
+					{{ range $guard := $field.Guards -}}
+					if {{ $guard }} {
+						return nil
+					}
+					{{ end -}}
+					return {{ $field.Value }}
+				},
+			},
+			{{ end -}}
+		},
+	},
+	{{ end -}}
+}
+
+{{ end -}}
+{{ if $api.XValidations -}}
+// {{ $api.Kind }}CELValidators evaluate the "+kubebuilder:validation:XValidation" rules of {{ $api.Resource }}
+var {{ $api.Kind }}CELValidators = []*builders.CELValidator{
+	{{ range $validation := $api.XValidations -}}
+	builders.NewCELValidator(schema.GroupVersion{Group: "{{ $.Group }}.{{ $.Domain }}", Version: "{{ $validation.Version }}"},
+		{{ range $rule := $validation.Rules -}}
+		builders.CELRule{
+			Path: {{ printf "%q" $rule.Path }},
+			Rule: {{ printf "%q" $rule.Rule }},
+			Message: {{ printf "%q" $rule.Message }},
+		},
+		{{ end -}}
+	),
+	{{ end -}}
+}
+
+{{ end -}}
+{{ if $api.RemovedFields -}}
+// {{ $api.Kind }}RemovedFields are the removed fields cleared from {{ $api.Resource }}
+var {{ $api.Kind }}RemovedFields = []builders.RemovedField{
+	{{ range $field := $api.RemovedFields -}}
+	{
+		Path: {{ printf "%q" $field.Path }},
+		Field: func(obj runtime.Object) interface{} {
+			o, ok := obj.(*{{ $api.Kind }})
+			if !ok {
+				return nil
+			}
+			{{ range $guard := $field.Guards -}}
+			if {{ $guard }} {
+				return nil
+			}
+			{{ end -}}
+			return &{{ $field.Field }}
+		},
+	},
+	{{ end -}}
+}
+
+{{ end -}}
+{{ if $api.ReadOnlyFields -}}
+// {{ $api.Kind }}ReadOnlyFields are the read-only fields the clients may not set through {{ $api.Resource }}
+var {{ $api.Kind }}ReadOnlyFields = []builders.ReadOnlyField{
+	{{ range $field := $api.ReadOnlyFields -}}
+	{
+		Path: {{ printf "%q" $field.Path }},
+		Field: func(obj runtime.Object) interface{} {
+			o, ok := obj.(*{{ $api.Kind }})
+			if !ok {
+				return nil
+			}
+			{{ range $guard := $field.Guards -}}
+			if {{ $guard }} {
+				return nil
+			}
+			{{ end -}}
+			return &{{ $field.Field }}
+		},
+	},
+	{{ end -}}
+}
+
+{{ end -}}
+{{ if $api.RangeDefaults -}}
+// {{ $api.Kind }}RangeDefaults are the ranges the fields of the created {{ $api.Resource }} are clamped into
+var {{ $api.Kind }}RangeDefaults = []builders.RangeDefault{
+	{{ range $field := $api.RangeDefaults -}}
+	{
+		Path: {{ printf "%q" $field.Path }},
+		Min: {{ $field.Min }},
+		Max: {{ $field.Max }},
+		Field: func(obj runtime.Object) interface{} {
+			o, ok := obj.(*{{ $api.Kind }})
+			if !ok {
+				return nil
+			}
+			{{ range $guard := $field.Guards -}}
+			if {{ $guard }} {
+				return nil
+			}
+			{{ end -}}
+			return &{{ $field.Field }}
+		},
+	},
+	{{ end -}}
+}
+
+{{ end -}}
+{{ if $api.UTCTimes -}}
+// Normalize{{ $api.Kind }}Times converts the times of a {{ $api.Kind }} to UTC on the creates and updates of
+// {{ $api.Resource }}
+func Normalize{{ $api.Kind }}Times(obj runtime.Object) {
+	o, ok := obj.(*{{ $api.Kind }})
+	if !ok {
+		return
+	}
+	{{ range $statement := $api.UTCTimes -}}
+	{{ $statement }}
+	{{ end -}}
+}
+
+{{ end -}}
+{{ if $api.ObservedGeneration -}}
+// Set{{ $api.Kind }}ObservedGeneration sets the status.observedGeneration of a {{ $api.Kind }} to generation on the
+// status updates of {{ $api.Resource }}
+func Set{{ $api.Kind }}ObservedGeneration(obj runtime.Object, generation int64) {
+	if o, ok := obj.(*{{ $api.Kind }}); ok {
+		{{ $api.ObservedGeneration }} = generation
+	}
+}
+
+{{ end -}}
+// +k8s:deepcopy-gen=false
+type {{.Strategy}} struct {
+	builders.DefaultStorageStrategy
+}
+
+// +k8s:deepcopy-gen=false
+type {{.StatusStrategy}} struct {
+	builders.DefaultStatusStorageStrategy
+}
+
 // Registry is an interface for things that know how to store {{.Kind}}.
 // +k8s:deepcopy-gen=false
 type {{.Kind}}Registry interface {
 	List{{.Kind}}s(ctx context.Context, options *internalversion.ListOptions) (*{{.Kind}}List, error)
+	List{{.Kind}}sWithResourceVersionMatch(ctx context.Context, options *internalversion.ListOptions, match builders.ResourceVersionMatch) (*{{.Kind}}List, error)
+	Watch{{.Kind}}s(ctx context.Context, options *internalversion.ListOptions) (watch.Interface, error)
 	Get{{.Kind}}(ctx context.Context, id string, options *metav1.GetOptions) (*{{.Kind}}, error)
 	Create{{.Kind}}(ctx context.Context, id *{{.Kind}}) (*{{.Kind}}, error)
 	Update{{.Kind}}(ctx context.Context, id *{{.Kind}}) (*{{.Kind}}, error)
@@ -271,6 +754,15 @@ func (s *storage{{.Kind}}) List{{.Kind}}s(ctx context.Context, options *internal
 	return obj.(*{{.Kind}}List), err
 }
 
+func (s *storage{{.Kind}}) List{{.Kind}}sWithResourceVersionMatch(ctx context.Context, options *internalversion.ListOptions, match builders.ResourceVersionMatch) (*{{.Kind}}List, error) {
+	return s.List{{.Kind}}s(builders.ContextWithResourceVersionMatch(ctx, match), options)
+}
+
+func (s *storage{{.Kind}}) Watch{{.Kind}}s(ctx context.Context, options *internalversion.ListOptions) (watch.Interface, error) {
+	st := s.GetStandardStorage()
+	return st.Watch(ctx, options)
+}
+
 func (s *storage{{.Kind}}) Get{{.Kind}}(ctx context.Context, id string, options *metav1.GetOptions) (*{{.Kind}}, error) {
 	st := s.GetStandardStorage()
 	obj, err := st.Get(ctx, id, options)
@@ -304,5 +796,5 @@ func (s *storage{{.Kind}}) Delete{{.Kind}}(ctx context.Context, id string) (bool
 	return sync, err
 }
 
-{{ end -}}
+{{ end }}
 `
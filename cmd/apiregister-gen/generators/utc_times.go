@@ -0,0 +1,110 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// utcTimeTypes maps the time types to the format of the statement converting a time "%[1]s" to UTC
+var utcTimeTypes = map[types.Name]string{
+	{Package: "k8s.io/apimachinery/pkg/apis/meta/v1", Name: "Time"}:      "%[1]s.Time = %[1]s.Time.UTC()",
+	{Package: "k8s.io/apimachinery/pkg/apis/meta/v1", Name: "MicroTime"}: "%[1]s.Time = %[1]s.Time.UTC()",
+	{Package: "time", Name: "Time"}:                                      "%[1]s = %[1]s.UTC()",
+}
+
+// ParseUTCTimesTag returns the Go statements converting the metav1.Time, metav1.MicroTime and time.Time fields
+// of an unversioned object "o" of the resource type c to UTC, for its "+resource:utcTimes" comment.  The fields
+// are reached through the structs declared in the package of the resource, and the pointers, slices and maps
+// of the fields.  The times of the object metadata are set by the apiserver and left unchanged.
+func ParseUTCTimesTag(c *types.Type) []string {
+	statements := []string{}
+	for _, m := range c.Members {
+		if reflect.StructTag(m.Tags).Get("json") == "-" {
+			continue
+		}
+		statements = append(statements, utcTimeStatements(m.Type, "o."+m.Name, c.Name.Package, 0, sets.NewString())...)
+	}
+	if len(statements) == 0 {
+		klog.Fatalf("// +resource:utcTimes of type %v requires a metav1.Time, metav1.MicroTime or time.Time field", c.Name)
+	}
+	return statements
+}
+
+// utcTimeStatements returns the statements converting the times of the value expr of type t to UTC.  depth
+// numbers the variables of the loops over the slices and maps.
+func utcTimeStatements(t *types.Type, expr, pkg string, depth int, visited sets.String) []string {
+	for t.Kind == types.Alias {
+		t = t.Underlying
+	}
+	if format, ok := utcTimeTypes[t.Name]; ok {
+		return []string{fmt.Sprintf(format, expr)}
+	}
+	switch t.Kind {
+	case types.Pointer:
+		// The fields of the pointed structs are selected through the pointer, a pointed time.Time is dereferenced
+		elem := expr
+		if t.Elem.Name == (types.Name{Package: "time", Name: "Time"}) {
+			elem = "(*" + expr + ")"
+		}
+		return utcTimeBlock(fmt.Sprintf("if %s != nil {", expr), utcTimeStatements(t.Elem, elem, pkg, depth, visited))
+	case types.Slice, types.Array:
+		i := fmt.Sprintf("i%d", depth)
+		return utcTimeBlock(fmt.Sprintf("for %s := range %s {", i, expr),
+			utcTimeStatements(t.Elem, fmt.Sprintf("%s[%s]", expr, i), pkg, depth+1, visited))
+	case types.Map:
+		// The values of maps are not addressable, they are converted in a copy stored back in the map
+		k, v := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth)
+		statements := utcTimeStatements(t.Elem, v, pkg, depth+1, visited)
+		if len(statements) == 0 {
+			return nil
+		}
+		return utcTimeBlock(fmt.Sprintf("for %s, %s := range %s {", k, v, expr), append(statements, fmt.Sprintf("%s[%s] = %s", expr, k, v)))
+	case types.Struct:
+		if t.Name.Package != pkg || visited.Has(t.Name.Name) {
+			return nil
+		}
+		visited.Insert(t.Name.Name)
+		defer visited.Delete(t.Name.Name)
+		statements := []string{}
+		for _, m := range t.Members {
+			if reflect.StructTag(m.Tags).Get("json") == "-" {
+				continue
+			}
+			statements = append(statements, utcTimeStatements(m.Type, expr+"."+m.Name, pkg, depth, visited)...)
+		}
+		return statements
+	}
+	return nil
+}
+
+// utcTimeBlock returns the statements enclosed in the block opened by open, none if there are no statements
+func utcTimeBlock(open string, statements []string) []string {
+	if len(statements) == 0 {
+		return nil
+	}
+	result := []string{open}
+	for _, s := range statements {
+		result = append(result, "\t"+s)
+	}
+	return append(result, "}")
+}
@@ -23,9 +23,39 @@ import (
 
 	"github.com/pkg/errors"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/gengo/types"
+	"k8s.io/klog"
 )
 
+// DeprecatedMarkers are comment tags that are no longer supported.  They are recognized and
+// ignored with a warning rather than being parsed as part of a tag sharing the same prefix.
+var DeprecatedMarkers = map[string]string{
+	"+resource:initializers": "initializers were removed in Kubernetes 1.14",
+}
+
+// warnedDeprecatedMarkers records the marker and type pairs already warned about
+var warnedDeprecatedMarkers = sets.NewString()
+
+// IgnoreDeprecatedMarkers returns the comment lines of t without any deprecated markers,
+// logging a warning the first time each marker is found on the type
+func IgnoreDeprecatedMarkers(t *types.Type, lines []string) Comments {
+	result := Comments{}
+	for _, l := range lines {
+		marker := strings.SplitN(strings.TrimSpace(l), "=", 2)[0]
+		reason, deprecated := DeprecatedMarkers[marker]
+		if !deprecated {
+			result = append(result, l)
+			continue
+		}
+		if key := fmt.Sprintf("%s %v", marker, t.Name); !warnedDeprecatedMarkers.Has(key) {
+			warnedDeprecatedMarkers.Insert(key)
+			klog.Warningf("ignoring deprecated marker %s on resource %v: %s", marker, t.Name, reason)
+		}
+	}
+	return result
+}
+
 // IsAPIResource returns true if t has a +resource comment tag
 func IsAPIResource(t *types.Type) bool {
 	for _, c := range t.CommentLines {
@@ -67,6 +97,16 @@ func IsAPISubresource(t *types.Type) bool {
 	return false
 }
 
+// HasOpenAPIDefinition returns true if openapi-gen generates a definition for t of package pkg, i.e. t or pkg
+// has a +k8s:openapi-gen=true comment tag and t is not excluded with +k8s:openapi-gen=false.  With docGo, a
+// version package without the tag gets it from the doc.go written by WriteVersionDoc.
+func HasOpenAPIDefinition(pkg *types.Package, t *types.Type, docGo bool) bool {
+	if tag := Comments(pkg.Comments).GetTag("k8s:openapi-gen", "="); tag == "true" || (docGo && len(tag) == 0) {
+		return Comments(t.CommentLines).GetTag("k8s:openapi-gen", "=") != "false"
+	}
+	return Comments(t.CommentLines).GetTag("k8s:openapi-gen", "=") == "true"
+}
+
 // HasSubresource returns true if t is an APIResource with one or more Subresources
 func HasSubresource(t *types.Type) bool {
 	if !IsAPIResource(t) {
@@ -80,6 +120,26 @@ func HasSubresource(t *types.Type) bool {
 	return false
 }
 
+// HasSpecSelector returns true if t has a Spec with a "Selector *metav1.LabelSelector" field
+func HasSpecSelector(t *types.Type) bool {
+	for _, m := range t.Members {
+		if m.Name != "Spec" {
+			continue
+		}
+		spec := m.Type
+		if spec.Kind == types.Pointer {
+			spec = spec.Elem
+		}
+		for _, f := range spec.Members {
+			if f.Name == "Selector" && f.Type.Kind == types.Pointer &&
+				f.Type.Elem.Name == (types.Name{Package: "k8s.io/apimachinery/pkg/apis/meta/v1", Name: "LabelSelector"}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func IsUnversioned(t *types.Type, group string) bool {
 	return IsApisDir(filepath.Base(filepath.Dir(t.Name.Package))) && GetGroup(t) == group
 }
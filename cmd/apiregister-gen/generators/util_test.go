@@ -0,0 +1,98 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// TestIgnoreDeprecatedMarkers checks a +resource:initializers marker is ignored in the +resource tag of a type,
+// logging a warning naming the marker and the resource the first time only
+func TestIgnoreDeprecatedMarkers(t *testing.T) {
+	flags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(flags)
+	if err := flags.Set("logtostderr", "false"); err != nil {
+		t.Fatal(err)
+	}
+	// The warnings are also written to the output of the infos
+	logs := &bytes.Buffer{}
+	klog.SetOutputBySeverity("INFO", ioutil.Discard)
+	klog.SetOutputBySeverity("WARNING", logs)
+	defer func() {
+		klog.SetOutput(os.Stderr)
+		flags.Set("logtostderr", "true")
+	}()
+
+	cultist := &types.Type{
+		Name: types.Name{Package: "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1", Name: "Cultist"},
+		Kind: types.Struct,
+		CommentLines: []string{
+			"+resource:initializers=dagon",
+			"+resource:path=cultists,strategy=CultistStrategy",
+		},
+	}
+	b := &APIsBuilder{}
+	for i := 0; i < 2; i++ {
+		if resource := b.GetResourceTag(cultist); resource != "path=cultists,strategy=CultistStrategy" {
+			t.Errorf("expected the deprecated marker to be ignored, got the +resource tag %q", resource)
+		}
+	}
+	klog.Flush()
+
+	warning := "ignoring deprecated marker +resource:initializers on resource " +
+		"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1.Cultist: " +
+		"initializers were removed in Kubernetes 1.14"
+	if count := strings.Count(logs.String(), warning); count != 1 {
+		t.Errorf("expected the warning %q to be logged once, got %d times in:\n%s", warning, count, logs.String())
+	}
+}
+
+// TestHasOpenAPIDefinition checks the types of a package without a +k8s:openapi-gen tag have OpenAPI definitions
+// only if the tag is added to the doc.go of the package, unless they opt out
+func TestHasOpenAPIDefinition(t *testing.T) {
+	tests := []struct {
+		pkg      []string
+		t        []string
+		docGo    bool
+		expected bool
+	}{
+		{expected: false},
+		{docGo: true, expected: true},
+		{t: []string{"+k8s:openapi-gen=true"}, expected: true},
+		{t: []string{"+k8s:openapi-gen=false"}, docGo: true, expected: false},
+		{pkg: []string{"+k8s:openapi-gen=true"}, expected: true},
+		{pkg: []string{"+k8s:openapi-gen=true"}, t: []string{"+k8s:openapi-gen=false"}, expected: false},
+		{pkg: []string{"+k8s:openapi-gen=partial"}, docGo: true, expected: false},
+		{pkg: []string{"+k8s:openapi-gen=partial"}, t: []string{"+k8s:openapi-gen=true"}, expected: true},
+	}
+	for _, test := range tests {
+		pkg := &types.Package{Comments: test.pkg}
+		scale := &types.Type{Name: types.Name{Name: "DeepOneScale"}, Kind: types.Struct, CommentLines: test.t}
+		if actual := HasOpenAPIDefinition(pkg, scale, test.docGo); actual != test.expected {
+			t.Errorf("expected HasOpenAPIDefinition %v for the package comments %q, the type comments %q and "+
+				"docGo %v, got %v", test.expected, test.pkg, test.t, test.docGo, actual)
+		}
+	}
+}
@@ -18,7 +18,6 @@ package generators
 
 import (
 	"io"
-	"k8s.io/gengo/namer"
 	"text/template"
 
 	"k8s.io/gengo/generator"
@@ -40,9 +39,29 @@ func CreateVersionedGenerator(apiversion *APIVersion, apigroup *APIGroup, filena
 	}
 }
 
+// hasSubresources returns true if a resource served by version has subresources, whose storage is generated
 func hasSubresources(version *APIVersion) bool {
 	for _, v := range version.Resources {
-		if len(v.Subresources) != 0 {
+		if len(v.Subresources) != 0 && !v.RetainConversion {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSelectors(version *APIVersion) bool {
+	for _, v := range version.Resources {
+		if v.HasSelector {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCustomConversions(version *APIVersion) bool {
+	for _, v := range version.Resources {
+		if v.ConversionWebhookFallback || len(v.AnnotationConversion) > 0 || len(v.EnumFields) > 0 ||
+			len(v.EmbeddedObjectFields) > 0 {
 			return true
 		}
 	}
@@ -55,21 +74,30 @@ func (d *versionedGenerator) Imports(c *generator.Context) []string {
 		"k8s.io/apimachinery/pkg/runtime",
 		"sigs.k8s.io/apiserver-builder-alpha/pkg/builders",
 		"k8s.io/apimachinery/pkg/runtime/schema",
+		"k8s.io/apimachinery/pkg/watch",
 		"k8s.io/apiserver/pkg/registry/generic",
+		"context",
+		"authorizationv1 \"k8s.io/api/authorization/v1\"",
+		"authorizationv1client \"k8s.io/client-go/kubernetes/typed/authorization/v1\"",
+		"k8s.io/client-go/tools/cache",
+		"fmt",
+		"utilruntime \"k8s.io/apimachinery/pkg/util/runtime\"",
 		d.apigroup.Pkg.Path,
 	}
 	if hasSubresources(d.apiversion) {
 		imports = append(imports, "k8s.io/apiserver/pkg/registry/rest")
 	}
+	if hasSelectors(d.apiversion) {
+		imports = append(imports, "k8s.io/apimachinery/pkg/labels")
+	}
 
 	return imports
 }
 
 func (d *versionedGenerator) Finalize(context *generator.Context, w io.Writer) error {
-	temp := template.Must(template.New("versioned-template").Funcs(map[string]interface{}{
-		"public": namer.IC,
-	}).Parse(VersionedAPITemplate))
-	return temp.Execute(w, d.apiversion)
+	temp := template.Must(template.New("versioned-template").Funcs(templateFuncs).Parse(VersionedAPITemplate))
+	template.Must(temp.Parse(VersionedResourceTemplates))
+	return executeTemplate(w, "versioned", temp, d.apiversion)
 }
 
 var VersionedAPITemplate = `
@@ -87,27 +115,36 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	return nil
 }
 
+{{ if .PreviousGroupName -}}
+// PreviousSchemeGroupVersion is the version of the group previously named {{ .PreviousGroupName }}.  The objects
+// of the previous group, e.g. stored before the group was renamed, decode as the types of SchemeGroupVersion.
+var PreviousSchemeGroupVersion = schema.GroupVersion{Group: "{{ .PreviousGroupName }}", Version: "{{ .Version }}"}
+
+// addPreviousKnownTypes registers the types under PreviousSchemeGroupVersion.  It is added to the scheme after
+// addKnownTypes, so the types are encoded with the apiVersion of SchemeGroupVersion.
+func addPreviousKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(PreviousSchemeGroupVersion,
+{{ range $api := .Resources -}}
+		&{{ $api.Kind }}{},
+		&{{ $api.Kind }}List{},
+{{ end -}}
+	)
+	return nil
+}
+
+{{ end -}}
 var (
+	{{ range $api := served .Resources -}}
+	{{ template "versioned-resource-storage" $api }}
+	{{ end }}
 	ApiVersion = builders.NewApiVersion("{{.Group}}.{{.Domain}}", "{{.Version}}").WithResources(
-		{{ range $api := .Resources -}}
+		{{ range $api := served .Resources -}}
 		{{$api.Group}}.{{$api.Group|public}}{{$api.Kind}}Storage,
-		{{ if $api.REST }}{{ else -}}
-		builders.NewApiResource( // Resource status endpoint
-			{{ $api.Group }}.Internal{{ $api.Kind }}Status,
-			func() runtime.Object { return &{{ $api.Kind }}{} },     // Register versioned resource
-			func() runtime.Object { return &{{ $api.Kind }}List{} }, // Register versioned resource list
-			&{{ $api.Group }}.{{ $api.StatusStrategy }}{DefaultStatusStorageStrategy: builders.StatusStorageStrategySingleton},
-		),{{ end -}}
-
+		{{ if not $api.REST -}}
+		{{ $api.Kind }}StatusStorage,
+		{{ end -}}
 		{{ range $subresource := $api.Subresources -}}
-		builders.NewApiResourceWithStorage(
-			{{ $api.Group }}.Internal{{ $subresource.Kind }}REST,
-			func() runtime.Object { return &{{ $subresource.Request }}{} }, // Register versioned resource
-			nil,
-            {{ if $subresource.REST }}{{ $api.Group }}.New{{ $subresource.REST }}{{ else -}}
-			func(generic.RESTOptionsGetter) rest.Storage { return &{{ $api.Group }}.{{ $subresource.Kind }}REST{Registry: {{$api.Group}}.New{{$api.Kind}}Registry({{$api.Group}}.{{$api.Group|public}}{{$api.Kind}}Storage) } },
-			{{ end -}}
-		),
+		{{ $api.Kind }}{{ $subresource.Path|public }}Storage,
 		{{ end -}}
 		{{ end -}}
 	)
@@ -117,7 +154,11 @@ var (
 		ApiVersion.SchemeBuilder.AddToScheme, 
 		RegisterDefaults, 
 		RegisterConversions,
+		RegisterCustomConversions,
 		addKnownTypes,
+		{{ if .PreviousGroupName -}}
+		addPreviousKnownTypes,
+		{{ end -}}
 		func(scheme *runtime.Scheme) error {
 			metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 			return nil
@@ -151,9 +192,259 @@ type {{$api.Kind}}List struct {
 	Items           []{{$api.Kind}} ` + "`json:\"items\"`" + `
 }
 
+// {{$api.Kind}}FieldDocs are the descriptions of the fields of {{$api.Kind}} keyed by their dotted json path,
+// e.g. "spec.replicas", for tooling explaining the fields without reading the OpenAPI definitions
+var {{$api.Kind}}FieldDocs = map[string]string{
+	{{ range $field := $api.FieldDocs -}}
+	{{ printf "%q" $field.Path }}: {{ printf "%q" $field.Description }},
+	{{ end -}}
+}
+
+// {{$api.Kind}}WatchBookmark tracks the newest resource version observed while watching {{$api.Kind}}
+// objects, so a restarted watch can resume from it rather than relisting.
+// +k8s:deepcopy-gen=false
+type {{$api.Kind}}WatchBookmark struct {
+	ResourceVersion string
+}
+
+// Observe records the resource version of a watch event, including Bookmark events, and returns
+// true if it is newer than the resource version already tracked.
+func (b *{{$api.Kind}}WatchBookmark) Observe(event watch.Event) bool {
+	if event.Type == watch.Error {
+		return false
+	}
+	o, ok := event.Object.(*{{$api.Kind}})
+	if !ok {
+		return false
+	}
+	if newer, err := builders.IsNewerResourceVersion(o.ResourceVersion, b.ResourceVersion); err != nil || !newer {
+		return false
+	}
+	b.ResourceVersion = o.ResourceVersion
+	return true
+}
+
+// {{$api.Kind}}WatchEvent is a watch event of a {{$api.Kind}}.  Object is set for Added, Modified, Deleted
+// and Bookmark events, Status for Error events.
+// +k8s:deepcopy-gen=false
+type {{$api.Kind}}WatchEvent struct {
+	Type   watch.EventType
+	Object *{{$api.Kind}}
+	Status *metav1.Status
+}
+
+// Watch{{$api.Kind}}Events returns the events of w typed.  An object other than a *{{$api.Kind}} or, for Error
+// events, a *metav1.Status is returned as an Error event.  The channel is closed with the result channel
+// of w, it must be read until then.
+func Watch{{$api.Kind}}Events(w watch.Interface) <-chan {{$api.Kind}}WatchEvent {
+	events := make(chan {{$api.Kind}}WatchEvent)
+	go func() {
+		defer close(events)
+		for event := range w.ResultChan() {
+			typed := {{$api.Kind}}WatchEvent{Type: event.Type}
+			switch o := event.Object.(type) {
+			case *{{$api.Kind}}:
+				typed.Object = o
+			case *metav1.Status:
+				typed.Status = o
+			default:
+				typed.Type = watch.Error
+				typed.Status = builders.UnexpectedWatchObjectStatus(event, "*{{$api.Kind}}")
+			}
+			events <- typed
+		}
+	}()
+	return events
+}
+
+{{ if $api.HasSelector -}}
+// Selector returns spec.selector compiled into a labels.Selector.  Compiled selectors are cached, so
+// the selector is only parsed once.
+func (o *{{$api.Kind}}) Selector() (labels.Selector, error) {
+	return builders.LabelSelectorAsSelector(o.Spec.Selector)
+}
+
+{{ end -}}
+{{ if $api.ObservedGeneration -}}
+// Is{{$api.Kind}}UpToDate returns true if the status of o observed the current generation of o, i.e. the
+// status was updated since the last change of its spec
+func Is{{$api.Kind}}UpToDate(o *{{$api.Kind}}) bool {
+	return {{ $api.ObservedGeneration }} == o.Generation
+}
+
+{{ end -}}
+{{ range $condition := $api.Conditions -}}
+// Get{{$api.Kind}}{{$condition.Name}}Condition returns the {{$condition.Name}} condition of the status of o, nil if the
+// status has none
+func Get{{$api.Kind}}{{$condition.Name}}Condition(o *{{$api.Kind}}) *{{$condition.Type}} {
+	for i := range {{ $condition.Conditions }} {
+		if {{ $condition.Conditions }}[i].Type == "{{$condition.Name}}" {
+			return &{{ $condition.Conditions }}[i]
+		}
+	}
+	return nil
+}
+
+// Set{{$api.Kind}}{{$condition.Name}}Condition sets the status, reason and message of the {{$condition.Name}} condition of
+// the status of o, adding the condition if the status has none
+func Set{{$api.Kind}}{{$condition.Name}}Condition(o *{{$api.Kind}}, status, reason, message string) {
+	if c := Get{{$api.Kind}}{{$condition.Name}}Condition(o); c != nil {
+		c.Status, c.Reason, c.Message = status, reason, message
+		return
+	}
+	{{ $condition.Conditions }} = append({{ $condition.Conditions }},
+		{{$condition.Type}}{Type: "{{$condition.Name}}", Status: status, Reason: reason, Message: message})
+}
+
+{{ end -}}
+// On{{$api.Kind}}Add registers fn to be called with each {{$api.Kind}} added to the informer
+func On{{$api.Kind}}Add(informer cache.SharedInformer, fn func(obj *{{$api.Kind}})) {
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			o, ok := obj.(*{{$api.Kind}})
+			if !ok {
+				utilruntime.HandleError(fmt.Errorf("expected *{{$api.Kind}}, got %T", obj))
+				return
+			}
+			fn(o)
+		},
+	})
+}
+
+// On{{$api.Kind}}Update registers fn to be called with the old and new {{$api.Kind}} of each update
+// observed by the informer, including resyncs
+func On{{$api.Kind}}Update(informer cache.SharedInformer, fn func(oldObj, newObj *{{$api.Kind}})) {
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			o, ok := oldObj.(*{{$api.Kind}})
+			if !ok {
+				utilruntime.HandleError(fmt.Errorf("expected *{{$api.Kind}}, got %T", oldObj))
+				return
+			}
+			n, ok := newObj.(*{{$api.Kind}})
+			if !ok {
+				utilruntime.HandleError(fmt.Errorf("expected *{{$api.Kind}}, got %T", newObj))
+				return
+			}
+			fn(o, n)
+		},
+	})
+}
+
+// On{{$api.Kind}}Delete registers fn to be called with each {{$api.Kind}} deleted from the informer.
+// If the informer missed the delete, fn is called with the last known state of the {{$api.Kind}}.
+func On{{$api.Kind}}Delete(informer cache.SharedInformer, fn func(obj *{{$api.Kind}})) {
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			o, ok := obj.(*{{$api.Kind}})
+			if !ok {
+				utilruntime.HandleError(fmt.Errorf("expected *{{$api.Kind}}, got %T", obj))
+				return
+			}
+			fn(o)
+		},
+	})
+}
+
+{{ if and $api.RuntimeWebhook (not $api.BuildTag) -}}
+{{ template "versioned-resource-webhook" $api }}
+{{ end -}}
+{{ if $api.NonNamespaced -}}
+// {{$api.Kind}}Key returns the key of obj in the stores of informers, its name as {{$api.Kind}} is cluster scoped
+func {{$api.Kind}}Key(obj *{{$api.Kind}}) string {
+	return obj.Name
+}
+{{ else -}}
+// {{$api.Kind}}Key returns the key of obj in the stores of informers, <namespace>/<name>
+func {{$api.Kind}}Key(obj *{{$api.Kind}}) string {
+	return obj.Namespace + "/" + obj.Name
+}
+{{ end }}
+// Split{{$api.Kind}}Key returns the namespace and name of the {{$api.Kind}} of key, returned by {{$api.Kind}}Key.
+{{- if $api.NonNamespaced }}  The
+// namespace is always empty as {{$api.Kind}} is cluster scoped.{{ end }}
+func Split{{$api.Kind}}Key(key string) (namespace, name string, err error) {
+	return builders.SplitObjectKey(key, {{ not $api.NonNamespaced }})
+}
+
+{{ if $api.NonNamespaced -}}
+// New{{$api.Kind}}AccessReview returns a SelfSubjectAccessReview of whether the user may verb the {{$api.Kind}} named
+// name, or all of them with an empty name
+func New{{$api.Kind}}AccessReview(verb, name string) *authorizationv1.SelfSubjectAccessReview {
+	return builders.NewSelfSubjectAccessReview(SchemeGroupVersion.WithResource("{{$api.Resource}}"), verb, "", name)
+}
+
+// Can{{$api.Kind}} returns true if the user of client may verb the {{$api.Kind}} named name, or all of them with an
+// empty name, by creating a SelfSubjectAccessReview
+func Can{{$api.Kind}}(ctx context.Context, client authorizationv1client.SelfSubjectAccessReviewsGetter,
+	verb, name string) (bool, error) {
+	return builders.ReviewSelfSubjectAccess(ctx, client, New{{$api.Kind}}AccessReview(verb, name))
+}
+{{ else -}}
+// New{{$api.Kind}}AccessReview returns a SelfSubjectAccessReview of whether the user may verb the {{$api.Kind}} named
+// name in namespace.  An empty name reviews the access to all the {{$api.Kind}} objects of the namespace, an empty
+// namespace the access to the {{$api.Kind}} objects of all namespaces.
+func New{{$api.Kind}}AccessReview(verb, namespace, name string) *authorizationv1.SelfSubjectAccessReview {
+	return builders.NewSelfSubjectAccessReview(SchemeGroupVersion.WithResource("{{$api.Resource}}"), verb, namespace, name)
+}
+
+// Can{{$api.Kind}} returns true if the user of client may verb the {{$api.Kind}} named name in namespace, by creating
+// a SelfSubjectAccessReview
+func Can{{$api.Kind}}(ctx context.Context, client authorizationv1client.SelfSubjectAccessReviewsGetter,
+	verb, namespace, name string) (bool, error) {
+	return builders.ReviewSelfSubjectAccess(ctx, client, New{{$api.Kind}}AccessReview(verb, namespace, name))
+}
+{{ end }}
+{{ if $api.Indexes -}}
+{{ range $index := $api.Indexes -}}
+// {{$api.Kind}}{{$index.Suffix}}Index is the name of the index of {{$api.Kind}} objects by {{$index.Name}}
+const {{$api.Kind}}{{$index.Suffix}}Index = "{{$index.Name}}"
+
+{{ end -}}
+// Add{{$api.Kind}}Indexers adds the indexes of {{$api.Kind}} objects declared by +index comments to the
+// informer.  Indexers must be added before the informer is started.
+func Add{{$api.Kind}}Indexers(informer cache.SharedIndexInformer) error {
+	return informer.AddIndexers(cache.Indexers{
+		{{ range $index := $api.Indexes -}}
+		{{$api.Kind}}{{$index.Suffix}}Index: func(obj interface{}) ([]string, error) {
+			o, ok := obj.(*{{$api.Kind}})
+			if !ok {
+				return nil, fmt.Errorf("expected *{{$api.Kind}}, got %T", obj)
+			}
+			{{ range $guard := $index.Guards -}}
+			if {{ $guard }} {
+				return nil, nil
+			}
+			{{ end -}}
+			return []string{fmt.Sprint({{ $index.Value }})}, nil
+		},
+		{{ end -}}
+	})
+}
+
+{{ range $index := $api.Indexes -}}
+// List{{$api.Kind}}sBy{{$index.Suffix}} returns the {{$api.Kind}} objects of the indexer whose {{$index.Name}} is value
+func List{{$api.Kind}}sBy{{$index.Suffix}}(indexer cache.Indexer, value string) ([]*{{$api.Kind}}, error) {
+	objs, err := indexer.ByIndex({{$api.Kind}}{{$index.Suffix}}Index, value)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*{{$api.Kind}}, 0, len(objs))
+	for _, obj := range objs {
+		result = append(result, obj.(*{{$api.Kind}}))
+	}
+	return result, nil
+}
+
+{{ end -}}
+{{ end -}}
 {{ range $subresource := $api.Subresources -}}
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// +k8s:openapi-gen=true
 type {{$subresource.Request}}List struct {
 	metav1.TypeMeta ` + "`json:\",inline\"`" + `
 	metav1.ListMeta ` + "`json:\"metadata,omitempty\"`" + `
@@ -161,3 +452,59 @@ type {{$subresource.Request}}List struct {
 }
 {{ end }}{{ end -}}
 `
+
+// VersionedResourceTemplates are the templates of the code serving a resource of a versioned package, executed
+// with the resource either by the VersionedAPITemplate or, for a resource with a build tag, by the
+// TaggedVersionedAPITemplate
+var VersionedResourceTemplates = `
+{{ define "versioned-resource-storage" -}}
+{{ $api := . -}}
+	{{ if not $api.REST -}}
+	// {{ $api.Kind }}StatusStorage builds the storage of the {{ $api.Resource }}/status endpoint
+	{{ $api.Kind }}StatusStorage = builders.NewApiResource(
+		{{ $api.Group }}.Internal{{ $api.Kind }}Status,
+		func() runtime.Object { return &{{ $api.Kind }}{} },     // Register versioned resource
+		func() runtime.Object { return &{{ $api.Kind }}List{} }, // Register versioned resource list
+		{{ $api.StatusStorageBuilder }},
+	)
+	{{ end -}}
+	{{ range $subresource := $api.Subresources -}}
+	// {{ $api.Kind }}{{ $subresource.Path|public }}Storage builds the storage of the {{ $api.Resource }}/{{ $subresource.Path }} endpoint
+	{{ $api.Kind }}{{ $subresource.Path|public }}Storage = builders.NewApiResourceWithStorage(
+		{{ $api.Group }}.Internal{{ $subresource.Kind }}REST,
+		func() runtime.Object { return &{{ $subresource.Request }}{} }, // Register versioned resource
+		nil,
+		{{ if $subresource.REST }}{{ $api.Group }}.New{{ $subresource.REST }}{{ else -}}
+		func(generic.RESTOptionsGetter) rest.Storage { return &{{ $api.Group }}.{{ $subresource.Kind }}REST{Registry: {{$api.Group}}.New{{$api.Kind}}Registry({{$api.Group}}.{{$api.Group|public}}{{$api.Kind}}Storage) } },
+		{{ end -}}
+	)
+	{{ end -}}
+{{ end }}
+{{ define "versioned-resource-webhook" -}}
+{{ $api := . -}}
+// Default applies the defaults of the scheme to o.  It implements the webhook.Defaulter of controller-runtime.
+func (o *{{ $api.Kind }}) Default() {
+	builders.Scheme.Default(o)
+}
+
+// ValidateCreate validates o as the storage of the apiserver validates created {{ $api.Resource }}.  It implements
+// the webhook.Validator of controller-runtime.
+func (o *{{ $api.Kind }}) ValidateCreate() error {
+	return builders.ValidateRuntimeWebhookCreate({{ $api.Group }}.{{ $api.Group|public }}{{ $api.Kind }}Storage.StorageBuilder, o,
+		func() runtime.Object { return &{{ $api.Group }}.{{ $api.Kind }}{} })
+}
+
+// ValidateUpdate validates o updating old as the storage of the apiserver validates updated {{ $api.Resource }}.
+// It implements the webhook.Validator of controller-runtime.
+func (o *{{ $api.Kind }}) ValidateUpdate(old runtime.Object) error {
+	return builders.ValidateRuntimeWebhookUpdate({{ $api.Group }}.{{ $api.Group|public }}{{ $api.Kind }}Storage.StorageBuilder, o, old,
+		func() runtime.Object { return &{{ $api.Group }}.{{ $api.Kind }}{} })
+}
+
+// ValidateDelete allows deleting o, the storage of the apiserver does not validate deletes.  It implements the
+// webhook.Validator of controller-runtime.
+func (o *{{ $api.Kind }}) ValidateDelete() error {
+	return nil
+}
+{{ end -}}
+`
@@ -0,0 +1,199 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// WebhookMatchCondition is a CEL expression the admission requests must match to be sent to the webhooks of
+// a resource
+type WebhookMatchCondition struct {
+	// Name identifies the condition in the errors of the apiserver - e.g. exclude-system-namespaces
+	Name string
+	// Expression is the CEL expression of the condition evaluated against object, oldObject and request - e.g.
+	// !request.namespace.startsWith("kube-")
+	Expression string
+}
+
+// ParseWebhookMatchConditionTag parses the name and the CEL expression of a "+webhook:matchCondition=name:expr"
+// comment of the resource type c, whose name must differ from the names of the conditions.  The expression is
+// compiled, failing the generation on invalid expressions.
+func ParseWebhookMatchConditionTag(c *types.Type, conditions []*WebhookMatchCondition, tag string) *WebhookMatchCondition {
+	kv := strings.SplitN(tag, ":", 2)
+	if len(kv) != 2 || len(strings.TrimSpace(kv[1])) == 0 {
+		klog.Fatalf("// +webhook:matchCondition=%s of type %v must be name:expression", tag, c.Name)
+	}
+	result := &WebhookMatchCondition{Name: strings.TrimSpace(kv[0]), Expression: strings.TrimSpace(kv[1])}
+	if errs := validation.IsQualifiedName(result.Name); len(errs) > 0 {
+		klog.Fatalf("// +webhook:matchCondition=%s of type %v must be named by a qualified name: %s",
+			tag, c.Name, strings.Join(errs, ", "))
+	}
+	for _, condition := range conditions {
+		if condition.Name == result.Name {
+			klog.Fatalf("// +webhook:matchCondition=%s of type %v conflicts with the condition %s", tag, c.Name, condition.Name)
+		}
+	}
+	if err := compileMatchCondition(result.Expression); err != nil {
+		klog.Fatalf("// +webhook:matchCondition=%s of type %v does not compile: %v", tag, c.Name, err)
+	}
+	return result
+}
+
+// compileMatchCondition compiles the CEL expression of a match condition, declaring the variables the
+// apiserver evaluates it with
+func compileMatchCondition(expression string) error {
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewIdent("object", decls.Dyn, nil),
+		decls.NewIdent("oldObject", decls.Dyn, nil),
+		decls.NewIdent("request", decls.Dyn, nil),
+	))
+	if err != nil {
+		return err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return issues.Err()
+	}
+	if t := ast.ResultType(); !proto.Equal(t, decls.Bool) && !proto.Equal(t, decls.Dyn) {
+		return fmt.Errorf("the expression must evaluate to a bool")
+	}
+	return nil
+}
+
+// WriteWebhookManifest writes the MutatingWebhookConfiguration and the ValidatingWebhookConfiguration of the
+// "+webhook:runtime" resources of apis to file
+func WriteWebhookManifest(apis *APIs, file string) {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		klog.Fatalf("failed to create %s: %v", filepath.Dir(file), err)
+	}
+	if err := ioutil.WriteFile(file, WebhookManifest(apis), 0644); err != nil {
+		klog.Fatalf("failed to write %s: %v", file, err)
+	}
+}
+
+// WebhookManifest returns the webhook configurations of the "+webhook:runtime" resources of apis, calling the
+// paths controller-runtime serves the Default and Validate methods of the resources on.  The webhooks are
+// called through the webhook-service Service of the system namespace, to be replaced when deploying.
+func WebhookManifest(apis *APIs) []byte {
+	resources := []*APIResource{}
+	for _, group := range apis.Groups {
+		for _, version := range group.Versions {
+			for _, resource := range untagged(version.Resources) {
+				if resource.RuntimeWebhook {
+					resources = append(resources, resource)
+				}
+			}
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		ri, rj := resources[i], resources[j]
+		if ri.Group != rj.Group {
+			return ri.Group < rj.Group
+		}
+		if ri.Version != rj.Version {
+			return ri.Version < rj.Version
+		}
+		return ri.Kind < rj.Kind
+	})
+
+	var b bytes.Buffer
+	temp := template.Must(template.New("webhook-manifest-template").Funcs(template.FuncMap{
+		"lower": strings.ToLower,
+		"path": func(r *APIResource) string {
+			return strings.Replace(r.Group+"."+r.Domain, ".", "-", -1) + "-" + r.Version + "-" + strings.ToLower(r.Kind)
+		},
+		"webhook": func(prefix string, r *APIResource) interface{} {
+			return struct {
+				Prefix   string
+				Resource *APIResource
+			}{prefix, r}
+		},
+		"quote": func(s string) (string, error) {
+			quoted, err := json.Marshal(s)
+			return string(quoted), err
+		},
+	}).Parse(WebhookManifestTemplate))
+	if err := temp.Execute(&b, resources); err != nil {
+		klog.Fatalf("failed to write the webhook manifest: %v", err)
+	}
+	return b.Bytes()
+}
+
+var WebhookManifestTemplate = `{{- define "webhook" -}}
+- admissionReviewVersions:
+  - v1
+  clientConfig:
+    service:
+      name: webhook-service
+      namespace: system
+      path: /{{ .Prefix }}-{{ path .Resource }}
+  failurePolicy: Fail
+  {{- if .Resource.WebhookMatchConditions }}
+  matchConditions:
+  {{- range $condition := .Resource.WebhookMatchConditions }}
+  - name: {{ $condition.Name }}
+    expression: {{ quote $condition.Expression }}
+  {{- end }}
+  {{- end }}
+  name: {{ slice .Prefix 0 1 }}{{ lower .Resource.Kind }}.{{ .Resource.Group }}.{{ .Resource.Domain }}
+  rules:
+  - apiGroups:
+    - {{ .Resource.Group }}.{{ .Resource.Domain }}
+    apiVersions:
+    - {{ .Resource.Version }}
+    operations:
+    - CREATE
+    - UPDATE
+    {{- if eq .Prefix "validate" }}
+    - DELETE
+    {{- end }}
+    resources:
+    - {{ .Resource.Resource }}
+  sideEffects: None
+{{ end -}}
+{{- if . -}}
+apiVersion: admissionregistration.k8s.io/v1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: mutating-webhook-configuration
+webhooks:
+{{ range $resource := . }}{{ template "webhook" (webhook "mutate" $resource) }}{{ end -}}
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: validating-webhook-configuration
+webhooks:
+{{ range $resource := . }}{{ template "webhook" (webhook "validate" $resource) }}{{ end -}}
+{{- end -}}
+`
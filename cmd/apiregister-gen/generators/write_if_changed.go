@@ -0,0 +1,51 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generators
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"k8s.io/gengo/generator"
+	"k8s.io/klog"
+)
+
+// writeIfChangedFileType assembles the go files like the golang file type of gengo, but leaves the files
+// whose content is unchanged untouched, preserving their modification times for build caches keyed on them
+type writeIfChangedFileType struct {
+	*generator.DefaultFileType
+}
+
+func (ft writeIfChangedFileType) AssembleFile(f *generator.File, pathname string) error {
+	b := &bytes.Buffer{}
+	et := generator.NewErrorTracker(b)
+	ft.Assemble(et, f)
+	if et.Error() != nil {
+		return et.Error()
+	}
+	formatted, err := ft.Format(b.Bytes())
+	if err != nil {
+		// gengo writes the unformatted file and reports the error, so the generator can be fixed
+		return ft.DefaultFileType.AssembleFile(f, pathname)
+	}
+	if existing, err := ioutil.ReadFile(pathname); err == nil && bytes.Equal(existing, formatted) {
+		klog.V(2).Infof("Skipping unchanged file %q", pathname)
+		return nil
+	}
+	klog.V(2).Infof("Assembling file %q", pathname)
+	return ioutil.WriteFile(pathname, formatted, 0644)
+}
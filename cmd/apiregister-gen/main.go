@@ -17,9 +17,11 @@ limitations under the License.
 package main
 
 import (
+	goflag "flag"
 	"os"
 	"runtime"
 
+	"github.com/spf13/pflag"
 	"k8s.io/gengo/args"
 	"k8s.io/klog"
 	"sigs.k8s.io/apiserver-builder-alpha/cmd/apiregister-gen/generators"
@@ -31,14 +33,19 @@ func main() {
 		runtime.GOMAXPROCS(runtime.NumCPU())
 	}
 
-	arguments := args.Default()
+	// Parse the flags before executing the generator, which reads the profile flags of the custom args
+	arguments := args.Default().WithoutDefaultFlagParsing()
 
 	// Override defaults.
 	arguments.OutputFileBaseName = "zz_generated.api.register"
 
 	// Custom args.
 	customArgs := &generators.CustomArgs{}
+	customArgs.AddFlags(pflag.CommandLine)
 	arguments.CustomArgs = customArgs
+	arguments.AddFlags(pflag.CommandLine)
+	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
+	pflag.Parse()
 
 	g := generators.Gen{}
 	if err := g.Execute(arguments); err != nil {
@@ -25,6 +25,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -158,6 +159,9 @@ func buildResourceConfig() {
 		ImagePullSecrets:      ImagePullSecrets,
 		ServiceAccount:        ServiceAccount,
 		StorageClass:          StorageClass,
+		ConversionWebhook:     hasConversionWebhookFallback(),
+		CustomMetrics:         hasCustomMetrics(),
+		Priorities:            discoveryPriorities(),
 	}
 	path := filepath.Join(ResourceConfigDir, "apiserver.yaml")
 
@@ -249,6 +253,85 @@ func initVersionedApis() {
 	}
 }
 
+var conversionWebhookFallbackComment = regexp.MustCompile(`(?m)^// \+conversion:webhookFallback\s*$`)
+
+// hasConversionWebhookFallback returns true if a resource of pkg/apis has a "+conversion:webhookFallback"
+// comment, whose apiserver calls the conversion webhook
+func hasConversionWebhookFallback() bool {
+	return hasAPITypesComment(conversionWebhookFallbackComment)
+}
+
+var customMetricComment = regexp.MustCompile(`(?m)^// \+metric=`)
+
+// hasCustomMetrics returns true if a resource of pkg/apis has a "+metric=" comment, whose apiserver serves
+// the custom metrics API
+func hasCustomMetrics() bool {
+	return hasAPITypesComment(customMetricComment)
+}
+
+// hasAPITypesComment returns true if a file of the versions of pkg/apis has a comment matching re
+func hasAPITypesComment(re *regexp.Regexp) bool {
+	files, err := filepath.Glob(filepath.Join("pkg", "apis", "*", "*", "*.go"))
+	if err != nil {
+		klog.Fatalf("could not list the api types: %v", err)
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			klog.Fatalf("could not read %s: %v", f, err)
+		}
+		if re.Match(data) {
+			return true
+		}
+	}
+	return false
+}
+
+var groupPriorityMinimumComment = regexp.MustCompile(`(?m)^// \+discovery:groupPriorityMinimum=(\d+)\s*$`)
+var versionPriorityComment = regexp.MustCompile(`(?m)^// \+discovery:versionPriority=(\d+)\s*$`)
+
+// apiServicePriority is the discovery priority of the APIService of a version
+type apiServicePriority struct {
+	GroupPriorityMinimum int
+	VersionPriority      int
+}
+
+// discoveryPriorities returns the priorities of the APIServices of the Versions, keyed by group/version.  The
+// groupPriorityMinimum is set by a "+discovery:groupPriorityMinimum=" comment in the doc.go of the group
+// package, the versionPriority by a "+discovery:versionPriority=" comment in the doc.go of the version
+// package, defaulting to 2000 and 10.
+func discoveryPriorities() map[string]apiServicePriority {
+	priorities := map[string]apiServicePriority{}
+	for _, gv := range Versions {
+		priorities[gv.String()] = apiServicePriority{
+			GroupPriorityMinimum: docGoPriority(filepath.Join("pkg", "apis", gv.Group, "doc.go"), groupPriorityMinimumComment, 2000),
+			VersionPriority:      docGoPriority(filepath.Join("pkg", "apis", gv.Group, gv.Version, "doc.go"), versionPriorityComment, 10),
+		}
+	}
+	return priorities
+}
+
+// docGoPriority returns the priority of the comment matching re in the doc.go file, or defaultPriority if the
+// file or the comment are missing
+func docGoPriority(file string, re *regexp.Regexp, defaultPriority int) int {
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return defaultPriority
+	}
+	if err != nil {
+		klog.Fatalf("could not read %s: %v", file, err)
+	}
+	match := re.FindSubmatch(data)
+	if match == nil {
+		return defaultPriority
+	}
+	priority, err := strconv.Atoi(string(match[1]))
+	if err != nil || priority <= 0 {
+		klog.Fatalf("the discovery priority of %s must be a positive integer, got %s", file, match[1])
+	}
+	return priority
+}
+
 type resourceConfigTemplateArgs struct {
 	Versions              []schema.GroupVersion
 	CACert                string
@@ -267,11 +350,19 @@ type resourceConfigTemplateArgs struct {
 	ServiceAccount        string
 	StorageClass          string
 	ImagePullSecrets      []string
+	// ConversionWebhook adds the service, service account and RBAC of the conversion webhook of the
+	// "+conversion:webhookFallback" resources, and points the apiserver at the service
+	ConversionWebhook bool
+	// CustomMetrics adds the APIService of the custom metrics API served for the "+metric" comments
+	CustomMetrics bool
+	// Priorities are the discovery priorities of the APIServices of the Versions, keyed by group/version
+	Priorities map[string]apiServicePriority
 }
 
 var resourceConfigTemplate = `
 {{ $config := . -}}
 {{ range $api := .Versions -}}
+{{ $priority := index $config.Priorities $api.String -}}
 apiVersion: apiregistration.k8s.io/v1
 kind: APIService
 metadata:
@@ -282,14 +373,33 @@ metadata:
 spec:
   version: {{ $api.Version }}
   group: {{ $api.Group }}.{{ $config.Domain }}
-  groupPriorityMinimum: 2000
+  groupPriorityMinimum: {{ $priority.GroupPriorityMinimum }}
   service:
     name: {{ $config.Name }}
     namespace: {{ $config.Namespace }}
-  versionPriority: 10
+  versionPriority: {{ $priority.VersionPriority }}
   caBundle: "{{ $config.CACert }}"
 ---
 {{ end -}}
+{{ if .CustomMetrics -}}
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1beta1.custom.metrics.k8s.io
+  labels:
+    api: {{ .Name }}
+    apiserver: "true"
+spec:
+  version: v1beta1
+  group: custom.metrics.k8s.io
+  groupPriorityMinimum: 100
+  service:
+    name: {{ .Name }}
+    namespace: {{ .Namespace }}
+  versionPriority: 100
+  caBundle: "{{ .CACert }}"
+---
+{{ end -}}
 apiVersion: v1
 kind: Service
 metadata:
@@ -342,6 +452,11 @@ spec:
         - name: apiserver-certs
           mountPath: /apiserver.local.config/certificates
           readOnly: true
+        {{- if .ConversionWebhook }}
+        - name: conversion-webhook-ca
+          mountPath: /conversion-webhook
+          readOnly: true
+        {{- end }}
         command:
         - "./apiserver"
         args:
@@ -350,7 +465,9 @@ spec:
         - "--tls-private-key-file=/apiserver.local.config/certificates/tls.key"
         - "--audit-log-path=-"
         - "--audit-log-maxage=0"
-        - "--audit-log-maxbackup=0"{{ range $arg := .ApiserverArgs }}
+        - "--audit-log-maxbackup=0"{{ if .ConversionWebhook }}
+        - "--conversion-webhook-url=https://{{ .Name }}-conversion-webhook.{{ .Namespace }}.svc/convert"
+        - "--conversion-webhook-ca-file=/conversion-webhook/ca.crt"{{ end }}{{ range $arg := .ApiserverArgs }}
         - "{{ $arg }}"{{ end }}
         resources:
           requests:
@@ -376,6 +493,11 @@ spec:
       - name: apiserver-certs
         secret:
           secretName: {{ .Name }}
+      {{- if .ConversionWebhook }}
+      - name: conversion-webhook-ca
+        configMap:
+          name: {{ .Name }}-conversion-webhook-ca
+      {{- end }}
 ---
 apiVersion: apps/v1
 kind: StatefulSet
@@ -475,11 +597,108 @@ metadata:
 data:
   tls.crt: {{ .ClientCert }}
   tls.key: {{ .ClientKey }}
+{{- if .ConversionWebhook }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{.Name}}-conversion-webhook
+  namespace: {{.Namespace}}
+  labels:
+    api: {{.Name}}
+    conversion-webhook: "true"
+spec:
+  ports:
+  - port: 443
+    protocol: TCP
+    targetPort: 443
+  selector:
+    api: {{.Name}}
+    conversion-webhook: "true"
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{.Name}}-conversion-webhook
+  namespace: {{.Namespace}}
+  labels:
+    api: {{.Name}}
+    conversion-webhook: "true"
+---
+# Lets the conversion webhook authenticate and authorize the requests of the apiserver
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: {{.Name}}-conversion-webhook:system:auth-delegator
+  labels:
+    api: {{.Name}}
+    conversion-webhook: "true"
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:auth-delegator
+subjects:
+- kind: ServiceAccount
+  name: {{.Name}}-conversion-webhook
+  namespace: {{.Namespace}}
+---
+# Lets the conversion webhook watch the kubernetes.io/tls Secret of its serving certificate, reloaded on
+# rotation by builders.WebhookCertificate
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: {{.Name}}-conversion-webhook-tls
+  namespace: {{.Namespace}}
+  labels:
+    api: {{.Name}}
+    conversion-webhook: "true"
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  resourceNames:
+  - {{.Name}}-conversion-webhook-tls
+  verbs:
+  - get
+  - list
+  - watch
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: {{.Name}}-conversion-webhook-tls
+  namespace: {{.Namespace}}
+  labels:
+    api: {{.Name}}
+    conversion-webhook: "true"
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: {{.Name}}-conversion-webhook-tls
+subjects:
+- kind: ServiceAccount
+  name: {{.Name}}-conversion-webhook
+  namespace: {{.Namespace}}
+---
+# Replace CA_BUNDLE with the PEM certificate authorities of the serving certificate of the conversion webhook
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{.Name}}-conversion-webhook-ca
+  namespace: {{.Namespace}}
+  labels:
+    api: {{.Name}}
+    conversion-webhook: "true"
+data:
+  ca.crt: CA_BUNDLE
+{{- end }}
 `
 
 var localConfigTemplate = `
 {{ $config := . -}}
 {{ range $api := .Versions -}}
+{{ $priority := index $config.Priorities $api.String -}}
 apiVersion: apiregistration.k8s.io/v1beta1
 kind: APIService
 metadata:
@@ -490,15 +709,35 @@ metadata:
 spec:
   version: {{ $api.Version }}
   group: {{ $api.Group }}.{{ $config.Domain }}
-  groupPriorityMinimum: 2000
+  groupPriorityMinimum: {{ $priority.GroupPriorityMinimum }}
   priority: 200
   service:
     name: {{ $config.Name }}
     namespace: {{ $config.Namespace }}
-  versionPriority: 10
+  versionPriority: {{ $priority.VersionPriority }}
   caBundle: "{{ $config.CACert }}"
 ---
 {{ end -}}
+{{ if .CustomMetrics -}}
+apiVersion: apiregistration.k8s.io/v1beta1
+kind: APIService
+metadata:
+  name: v1beta1.custom.metrics.k8s.io
+  labels:
+    api: {{ .Name }}
+    apiserver: "true"
+spec:
+  version: v1beta1
+  group: custom.metrics.k8s.io
+  groupPriorityMinimum: 100
+  priority: 200
+  service:
+    name: {{ .Name }}
+    namespace: {{ .Namespace }}
+  versionPriority: 100
+  caBundle: "{{ .CACert }}"
+---
+{{ end -}}
 apiVersion: v1
 kind: Service
 metadata:
@@ -28,6 +28,7 @@ import (
 	"bytes"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/apiserver-builder-alpha/cmd/apiserver-boot/boot/util"
 )
 
 var docsCmd = &cobra.Command{
@@ -51,6 +52,10 @@ apiserver-boot build docs --build-openapi=false
 # Use the server at my/bin/apiserver
 apiserver-boot build docs --server my/bin/apiserver
 
+# Write the markdown reference of each resource from the api types to
+# docs/markdown/<group>/<version>/<resource>.md, without starting a server
+apiserver-boot build docs --markdown
+
 # Instead of generating the table of contents, use the statically defined configuration
 # from docs/config.yaml
 # See an example config.yaml at in kubernetes-incubator/reference-docs
@@ -83,6 +88,7 @@ var server string
 var disableDelegatedAuth bool
 var cleanup bool
 var outputDir string
+var markdown bool
 
 func AddDocs(cmd *cobra.Command) {
 	docsCmd.Flags().StringVar(&server, "server", "bin/apiserver", "path to apiserver binary to run to get swagger.json")
@@ -92,6 +98,7 @@ func AddDocs(cmd *cobra.Command) {
 	docsCmd.Flags().BoolVar(&generateToc, "generate-toc", true, "If true, generate the table of contents from the api groups instead of using a statically configured ToC.")
 	docsCmd.Flags().BoolVar(&disableDelegatedAuth, "disable-delegated-auth", true, "If true, disable delegated auth in the apiserver with --delegated-auth=false.")
 	docsCmd.Flags().StringVar(&outputDir, "output-dir", "docs", "Build docs into this directory")
+	docsCmd.Flags().BoolVar(&markdown, "markdown", false, "If true, write the markdown reference of each resource from the api types to <output-dir>/markdown instead of building the docs from the openapi spec.")
 	cmd.AddCommand(docsCmd)
 	docsCmd.AddCommand(docsCleanCmd)
 }
@@ -107,10 +114,15 @@ var docsCleanCmd = &cobra.Command{
 func RunCleanDocs(cmd *cobra.Command, args []string) {
 	os.RemoveAll(filepath.Join(outputDir, "build"))
 	os.RemoveAll(filepath.Join(outputDir, "includes"))
+	os.RemoveAll(filepath.Join(outputDir, "markdown"))
 	os.Remove(filepath.Join(outputDir, "manifest.json"))
 }
 
 func RunDocs(cmd *cobra.Command, args []string) {
+	if markdown {
+		runMarkdownDocs()
+		return
+	}
 	if len(server) == 0 && buildOpenapi {
 		klog.Fatal("Must specifiy --server or --build-openapi=false")
 	}
@@ -206,6 +218,28 @@ func RunDocs(cmd *cobra.Command, args []string) {
 	}
 }
 
+// runMarkdownDocs writes the markdown reference of each resource with apiregister-gen, replacing the
+// previously written reference
+func runMarkdownDocs() {
+	dir, err := os.Executable()
+	if err != nil {
+		klog.Fatalf("error: %v", err)
+	}
+	dir = filepath.Dir(dir)
+
+	markdownDir := filepath.Join(outputDir, "markdown")
+	os.RemoveAll(markdownDir)
+	c := exec.Command(filepath.Join(dir, "apiregister-gen"),
+		"--input-dirs", filepath.Join(util.Repo, "pkg", "apis", "..."),
+		"--markdown-docs-dir", markdownDir)
+	klog.Infof("%s", strings.Join(c.Args, " "))
+	c.Stderr = os.Stderr
+	c.Stdout = os.Stdout
+	if err := c.Run(); err != nil {
+		klog.Fatalf("error: %v", err)
+	}
+}
+
 func RunEtcd() func() {
 	etcdCmd := exec.Command("etcd")
 
@@ -0,0 +1,65 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/imports"
+)
+
+// formatGenerated runs goimports on each generated go file under root, so the generated code passes
+// goimports checks whichever code generator wrote it
+func formatGenerated(root string) error {
+	files, err := generatedFiles(root)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if filepath.Ext(f) != ".go" {
+			continue
+		}
+		if err := formatFile(filepath.Join(root, f)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatFile rewrites the go file at path with goimports applied, leaving it untouched if it is already formatted
+func formatFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	formatted, err := imports.Process(path, src, nil)
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %v", path, err)
+	}
+	if bytes.Equal(src, formatted) {
+		return nil
+	}
+	return ioutil.WriteFile(path, formatted, info.Mode())
+}
@@ -38,26 +38,48 @@ var codegenerators []string
 var copyright string
 var generators = sets.String{}
 var vendorDir string
+var strictJSONTags bool
+var emitTests bool
+var emitTestClients bool
+var verifyGenerated bool
+var openAPIPerVersion bool
+var emitRegisterAll bool
+var noFormat bool
+var deepcopyVersions string
+var writeDocGo bool
 
 var generateCmd = &cobra.Command{
 	Use:   "generated",
 	Short: "Run code generators against repo.",
 	Long:  `Automatically run by most build commands.  Writes generated source code for a repo.`,
 	Example: `# Run code generators.
-apiserver-boot build generated`,
+apiserver-boot build generated
+
+# Fail if the generated code is out of date with the types, e.g. in CI.
+apiserver-boot build generated --verify`,
 	Run: RunGenerate,
 }
 
 var extraAPI = strings.Join([]string{
 	"k8s.io/apimachinery/pkg/apis/meta/v1",
 	"k8s.io/apimachinery/pkg/conversion",
-	"k8s.io/apimachinery/pkg/runtime"}, ",")
+	"k8s.io/apimachinery/pkg/runtime",
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"}, ",")
 
 func AddGenerate(cmd *cobra.Command) {
 	cmd.AddCommand(generateCmd)
 	generateCmd.Flags().StringVar(&copyright, "copyright", "boilerplate.go.txt", "Location of copyright boilerplate file.")
 	generateCmd.Flags().StringVar(&vendorDir, "vendor-dir", "", "Location of directory containing vendor files.")
 	generateCmd.Flags().StringArrayVar(&versionedAPIs, "api-versions", []string{}, "API version to generate code for.  Can be specified multiple times.  e.g. --api-versions foo/v1beta1 --api-versions bar/v1  defaults to all versions found under directories pkg/apis/<group>/<version>")
+	generateCmd.Flags().BoolVar(&strictJSONTags, "strict-json-tags", false, "fail if a json tag is not the lowerCamelCase of its field name and is not marked +json:allowDeviation")
+	generateCmd.Flags().BoolVar(&emitTests, "emit-tests", false, "generate test helpers, such as a LoadFixture function decoding the testdata fixtures of each api group")
+	generateCmd.Flags().BoolVar(&emitTestClients, "emit-test-clients", false, "generate a testclient package in each api version package, with clients of the resources using the storage of the apiserver")
+	generateCmd.Flags().BoolVar(&verifyGenerated, "verify", false, "regenerate the code and fail if it differs from the generated code in the repo, leaving the repo unchanged")
+	generateCmd.Flags().BoolVar(&openAPIPerVersion, "openapi-per-version", false, "also generate the OpenAPI definitions of each api version in its package, and a GetAllOpenAPIDefinitions function of the apis package merging them")
+	generateCmd.Flags().BoolVar(&emitRegisterAll, "emit-register-all", false, "also generate a zz_generated.register.go file in the apis package importing the install package of every group, with an InstallAll function")
+	generateCmd.Flags().BoolVar(&noFormat, "no-format", false, "skip running goimports on the generated go files")
+	generateCmd.Flags().StringVar(&deepcopyVersions, "deepcopy-versions", "all", "api versions to generate the deepcopy functions of, all or internal.  internal only generates them for the unversioned api packages")
+	generateCmd.Flags().BoolVar(&writeDocGo, "write-doc-go", false, "add the +k8s:openapi-gen=true and +groupName comments to the doc.go of the api version packages lacking them.  With --verify, fail listing the missing comments instead")
 	generateCmd.Flags().StringArrayVar(&codegenerators, "generator", []string{}, "list of generators to run.  e.g. --generator apiregister --generator conversion Valid values: [apiregister,conversion,client,deepcopy,defaulter,openapi,protobuf]")
 	generateCmd.AddCommand(generateCleanCmd)
 
@@ -97,7 +119,23 @@ func doGen(g string) bool {
 }
 
 func RunGenerate(cmd *cobra.Command, args []string) {
-	initApis()
+	if verifyGenerated {
+		runVerifyGenerate()
+		return
+	}
+	if err := runGenerate(); err != nil {
+		klog.Fatalf("%v", err)
+	}
+}
+
+// runGenerate runs the code generators, returning an error if one fails
+func runGenerate() error {
+	if deepcopyVersions != "all" && deepcopyVersions != "internal" {
+		return fmt.Errorf("--deepcopy-versions must be all or internal, got %q", deepcopyVersions)
+	}
+	if err := initApis(); err != nil {
+		return err
+	}
 
 	for _, g := range codegenerators {
 		generators.Insert(strings.Replace(g, "-gen", "", -1))
@@ -107,7 +145,7 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 
 	root, err := os.Executable()
 	if err != nil {
-		klog.Fatalf("error: %v", err)
+		return err
 	}
 	root = filepath.Dir(root)
 
@@ -135,13 +173,35 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 			klog.Warningf("ignoring controller package code-generation due to %v", err)
 		}
 		inputDirsArgs = append(inputDirsArgs, "--go-header-file", copyright)
+		if strictJSONTags {
+			inputDirsArgs = append(inputDirsArgs, "--strict-json-tags")
+		}
+		if emitTests {
+			inputDirsArgs = append(inputDirsArgs, "--emit-tests")
+		}
+		if emitTestClients {
+			inputDirsArgs = append(inputDirsArgs, "--emit-test-clients")
+		}
+		if openAPIPerVersion {
+			inputDirsArgs = append(inputDirsArgs, "--openapi-per-version")
+		}
+		if emitRegisterAll {
+			inputDirsArgs = append(inputDirsArgs, "--emit-register-all")
+		}
+		if writeDocGo && verifyGenerated {
+			inputDirsArgs = append(inputDirsArgs, "--verify-doc-go")
+		} else if writeDocGo {
+			inputDirsArgs = append(inputDirsArgs, "--write-doc-go")
+		}
 
 		c := exec.Command(filepath.Join(root, "apiregister-gen"), inputDirsArgs...)
 		klog.Infof("%s", strings.Join(c.Args, " "))
 		out, err := c.CombinedOutput()
 		if err != nil {
-			klog.Fatalf("failed to run apiregister-gen %s %v", out, err)
+			return fmt.Errorf("failed to run apiregister-gen %s %v", out, err)
 		}
+		// Surface warnings, such as json tag deviations
+		os.Stderr.Write(out)
 	}
 
 	if doGen("conversion-gen") {
@@ -155,13 +215,17 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 		klog.Infof("%s", strings.Join(c.Args, " "))
 		out, err := c.CombinedOutput()
 		if err != nil {
-			klog.Fatalf("failed to run conversion-gen %s %v", out, err)
+			return fmt.Errorf("failed to run conversion-gen %s %v", out, err)
 		}
 	}
 
 	if doGen("deepcopy-gen") {
+		inputDirs := append(all, unversioned...)
+		if deepcopyVersions == "internal" {
+			inputDirs = unversioned
+		}
 		c := exec.Command(filepath.Join(root, "deepcopy-gen"),
-			append(append(all, unversioned...),
+			append(inputDirs,
 				"-o", util.GoSrc,
 				"--go-header-file", copyright,
 				"-O", "zz_generated.deepcopy")...,
@@ -169,7 +233,7 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 		klog.Infof("%s", strings.Join(c.Args, " "))
 		out, err := c.CombinedOutput()
 		if err != nil {
-			klog.Fatalf("failed to run deepcopy-gen %s %v", out, err)
+			return fmt.Errorf("failed to run deepcopy-gen %s %v", out, err)
 		}
 	}
 
@@ -221,7 +285,29 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 		klog.Infof("%s", strings.Join(c.Args, " "))
 		out, err := c.CombinedOutput()
 		if err != nil {
-			klog.Fatalf("failed to run openapi-gen %s %v", out, err)
+			return fmt.Errorf("failed to run openapi-gen %s %v", out, err)
+		}
+
+		// Generate the definitions of each api version in its package for GetAllOpenAPIDefinitions to merge
+		if openAPIPerVersion {
+			env := c.Env
+			for _, v := range versionedAPIs {
+				pkg := filepath.Join(util.Repo, "pkg", "apis", v)
+				c := exec.Command(filepath.Join(root, "openapi-gen"),
+					"--input-dirs", pkg,
+					"-o", util.GoSrc,
+					"--go-header-file", copyright,
+					"-i", strings.Join(apis, ","),
+					"-O", "zz_generated.openapi",
+					"--report-filename", "violations.report",
+					"--output-package", pkg)
+				c.Env = env
+				klog.Infof("%s", strings.Join(c.Args, " "))
+				out, err := c.CombinedOutput()
+				if err != nil {
+					return fmt.Errorf("failed to run openapi-gen for %s %s %v", v, out, err)
+				}
+			}
 		}
 	}
 
@@ -236,7 +322,7 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 		klog.Infof("%s", strings.Join(c.Args, " "))
 		out, err := c.CombinedOutput()
 		if err != nil {
-			klog.Fatalf("failed to run defaulter-gen %s %v", out, err)
+			return fmt.Errorf("failed to run defaulter-gen %s %v", out, err)
 		}
 	}
 
@@ -255,7 +341,7 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 		klog.Infof("%s", strings.Join(c.Args, " "))
 		out, err := c.CombinedOutput()
 		if err != nil {
-			klog.Fatalf("failed to run client-gen %s %v", out, err)
+			return fmt.Errorf("failed to run client-gen %s %v", out, err)
 		}
 
 		toGen := versioned
@@ -271,7 +357,7 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 			klog.Infof("%s", strings.Join(c.Args, " "))
 			out, err = c.CombinedOutput()
 			if err != nil {
-				klog.Fatalf("failed to run client-gen for unversioned APIs %s %v", out, err)
+				return fmt.Errorf("failed to run client-gen for unversioned APIs %s %v", out, err)
 			}
 		}
 
@@ -285,7 +371,7 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 		klog.Infof("%s", strings.Join(c.Args, " "))
 		out, err = c.CombinedOutput()
 		if err != nil {
-			klog.Fatalf("failed to run lister-gen %s %v", out, err)
+			return fmt.Errorf("failed to run lister-gen %s %v", out, err)
 		}
 
 		informerPkg := filepath.Join(clientPkg, "informers_generated")
@@ -300,7 +386,7 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 		klog.Infof("%s", strings.Join(c.Args, " "))
 		out, err = c.CombinedOutput()
 		if err != nil {
-			klog.Fatalf("failed to run informer-gen %s %v", out, err)
+			return fmt.Errorf("failed to run informer-gen %s %v", out, err)
 		}
 	}
 
@@ -329,9 +415,16 @@ func RunGenerate(cmd *cobra.Command, args []string) {
 		klog.Infof("%s", strings.Join(c.Args, " "))
 		out, err := c.CombinedOutput()
 		if err != nil {
-			klog.Fatalf("failed to run go-to-protobuf %s %v", out, err)
+			return fmt.Errorf("failed to run go-to-protobuf %s %v", out, err)
+		}
+	}
+
+	if !noFormat {
+		if err := formatGenerated("."); err != nil {
+			return fmt.Errorf("failed to format generated code: %v", err)
 		}
 	}
+	return nil
 }
 
 func getVendorApis(pkg string) []string {
@@ -358,17 +451,17 @@ func getVendorApis(pkg string) []string {
 	return apis
 }
 
-func initApis() {
+func initApis() error {
 	if len(versionedAPIs) == 0 {
 		groups, err := ioutil.ReadDir(filepath.Join("pkg", "apis"))
 		if err != nil {
-			klog.Fatalf("could not read pkg/apis directory to find api Versions")
+			return fmt.Errorf("could not read pkg/apis directory to find api Versions: %v", err)
 		}
 		for _, g := range groups {
 			if g.IsDir() {
 				versionFiles, err := ioutil.ReadDir(filepath.Join("pkg", "apis", g.Name()))
 				if err != nil {
-					klog.Fatalf("could not read pkg/apis/%s directory to find api Versions", g.Name())
+					return fmt.Errorf("could not read pkg/apis/%s directory to find api Versions: %v", g.Name(), err)
 				}
 				versionMatch := regexp.MustCompile("^v\\d+(alpha\\d+|beta\\d+)*$")
 				for _, v := range versionFiles {
@@ -386,4 +479,5 @@ func initApis() {
 	for a, _ := range u {
 		unversionedAPIs = append(unversionedAPIs, a)
 	}
+	return nil
 }
@@ -0,0 +1,189 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+)
+
+// runVerifyGenerate regenerates the code and exits non-zero if it differs from the generated code in the
+// repo, or if a code generator fails
+func runVerifyGenerate() {
+	if err := verifyGenerate(); err != nil {
+		klog.Fatalf("%v", err)
+	}
+}
+
+// verifyGenerate regenerates the code and returns an error if it differs from the generated code in the repo.
+// The generated code of the repo is copied to a temp directory beforehand and restored afterwards, even if a
+// code generator fails.  The temp directory is kept if the generated code can not be restored from it.
+func verifyGenerate() (err error) {
+	committed, err := ioutil.TempDir("", "apiserver-boot-verify")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %v", err)
+	}
+	files, err := generatedFiles(".")
+	if err != nil {
+		os.RemoveAll(committed)
+		return fmt.Errorf("failed to find generated code: %v", err)
+	}
+	if err := copyFiles(".", committed, files); err != nil {
+		os.RemoveAll(committed)
+		return fmt.Errorf("failed to copy generated code to %s: %v", committed, err)
+	}
+	defer func() {
+		if restoreErr := restoreGenerated(committed, files); restoreErr != nil {
+			err = utilerrors.NewAggregate([]error{err,
+				fmt.Errorf("failed to restore generated code from %s: %v", committed, restoreErr)})
+			return
+		}
+		os.RemoveAll(committed)
+	}()
+
+	if err := runGenerate(); err != nil {
+		return err
+	}
+
+	drift, err := diffGenerated(committed, ".")
+	if err != nil {
+		return fmt.Errorf("failed to compare generated code with %s: %v", committed, err)
+	}
+	if len(drift) > 0 {
+		for _, d := range drift {
+			fmt.Fprintln(os.Stderr, d)
+		}
+		return fmt.Errorf("generated code is out of date, run `apiserver-boot build generated`")
+	}
+	return nil
+}
+
+// isGenerated returns true for the path of a file written by the code generators, relative to the repo root
+func isGenerated(path string) bool {
+	if strings.HasPrefix(filepath.Base(path), "zz_generated.") ||
+		path == filepath.Join("pkg", "openapi", "openapi_generated.go") {
+		return true
+	}
+	for _, dir := range []string{"clientset_generated", "informers_generated", "listers_generated"} {
+		if strings.HasPrefix(path, filepath.Join("pkg", "client", dir)+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// generatedFiles returns the paths of the generated files under the pkg directory of root, relative to root
+func generatedFiles(root string) ([]string, error) {
+	files := []string{}
+	err := filepath.Walk(filepath.Join(root, "pkg"), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && isGenerated(rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return files, nil
+	}
+	return files, err
+}
+
+// copyFiles copies the files from the from directory to the to directory
+func copyFiles(from, to string, files []string) error {
+	for _, f := range files {
+		info, err := os.Stat(filepath.Join(from, f))
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(filepath.Join(from, f))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(to, f)), 0700); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(to, f), data, info.Mode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreGenerated replaces the generated files of the repo with the files copied to the committed directory
+func restoreGenerated(committed string, files []string) error {
+	generated, err := generatedFiles(".")
+	if err != nil {
+		return err
+	}
+	for _, f := range generated {
+		if err := os.Remove(f); err != nil {
+			return err
+		}
+	}
+	return copyFiles(committed, ".", files)
+}
+
+// diffGenerated returns a description of each generated file that differs between the before and after directories
+func diffGenerated(before, after string) ([]string, error) {
+	beforeFiles, err := generatedFiles(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFiles, err := generatedFiles(after)
+	if err != nil {
+		return nil, err
+	}
+	inBefore := sets.NewString(beforeFiles...)
+	inAfter := sets.NewString(afterFiles...)
+
+	drift := []string{}
+	for _, f := range inBefore.Union(inAfter).List() {
+		switch {
+		case !inAfter.Has(f):
+			drift = append(drift, fmt.Sprintf("%s is no longer generated", f))
+		case !inBefore.Has(f):
+			drift = append(drift, fmt.Sprintf("%s is missing", f))
+		default:
+			b, err := ioutil.ReadFile(filepath.Join(before, f))
+			if err != nil {
+				return nil, err
+			}
+			a, err := ioutil.ReadFile(filepath.Join(after, f))
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(b, a) {
+				drift = append(drift, fmt.Sprintf("%s is out of date", f))
+			}
+		}
+	}
+	return drift, nil
+}
@@ -0,0 +1,507 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/apiserver-builder-alpha/cmd/apiserver-boot/boot/util"
+)
+
+var labelPolicy []string
+var annotationPolicy []string
+var parentGroup string
+var parentVersion string
+var parentKind string
+var parentName string
+var parentNamespaced bool
+
+var createAdmissionCmd = &cobra.Command{
+	Use:   "admission",
+	Short: "Creates an admission controller enforcing label and annotation conventions or setting owner references",
+	Long: `Creates an admission controller enforcing label and annotation conventions.  Creates file plugin/admission/<kind>/admission.go ` +
+		`with a validating plugin rejecting objects that are missing any of the required label or annotation keys.  ` +
+		`With --parent-kind, the file instead contains a mutating plugin setting an owner reference to a parent object on ` +
+		`every created object.  The plugin is registered by the generated plugin/admission/install package.`,
+	Example: `# Require every "Bee" to carry the "example.com/team" label
+apiserver-boot create admission --group insect --version v1beta1 --kind Bee --label-policy example.com/team
+
+# Require both a label and an annotation
+apiserver-boot create admission --group insect --version v1beta1 --kind Bee --label-policy example.com/team --annotation-policy example.com/owner
+
+# Make every "Bee" owned by the "Hive" named "default" in its namespace
+apiserver-boot create admission --group insect --version v1beta1 --kind Bee --parent-group insect --parent-version v1beta1 --parent-kind Hive --parent-name default --parent-namespaced`,
+	Run: RunCreateAdmission,
+}
+
+func AddCreateAdmission(cmd *cobra.Command) {
+	RegisterResourceFlags(createAdmissionCmd)
+
+	createAdmissionCmd.Flags().StringArrayVar(&labelPolicy, "label-policy", []string{}, "label key that must be present on every object of the kind.  Can be specified multiple times.")
+	createAdmissionCmd.Flags().StringArrayVar(&annotationPolicy, "annotation-policy", []string{}, "annotation key that must be present on every object of the kind.  Can be specified multiple times.")
+	createAdmissionCmd.Flags().StringVar(&parentGroup, "parent-group", "", "API group, excluding its domain name, of the parent referenced as the owner of every created object of the kind.")
+	createAdmissionCmd.Flags().StringVar(&parentVersion, "parent-version", "", "API version of the parent referenced as the owner of every created object of the kind.")
+	createAdmissionCmd.Flags().StringVar(&parentKind, "parent-kind", "", "kind of the parent referenced as the owner of every created object of the kind.")
+	createAdmissionCmd.Flags().StringVar(&parentName, "parent-name", "", "name of the parent referenced as the owner of every created object of the kind.")
+	createAdmissionCmd.Flags().BoolVar(&parentNamespaced, "parent-namespaced", false, "if true, the parent is looked up in the namespace of the created object.")
+
+	cmd.AddCommand(createAdmissionCmd)
+}
+
+func RunCreateAdmission(cmd *cobra.Command, args []string) {
+	if _, err := os.Stat("pkg"); err != nil {
+		klog.Fatalf("could not find 'pkg' directory.  must run apiserver-boot init before creating resources")
+	}
+
+	util.GetDomain()
+	ValidateResourceFlags()
+
+	if len(parentKind) > 0 {
+		if len(labelPolicy) > 0 || len(annotationPolicy) > 0 {
+			klog.Fatalf("Must not specify --label-policy or --annotation-policy with --parent-kind")
+		}
+		if len(parentGroup) == 0 || len(parentVersion) == 0 || len(parentName) == 0 {
+			klog.Fatalf("Must specify --parent-group, --parent-version and --parent-name with --parent-kind")
+		}
+		if errs := utilvalidation.IsDNS1123Subdomain(parentName); len(errs) > 0 {
+			klog.Fatalf("--parent-name %q has bad format: %s", parentName, strings.Join(errs, ","))
+		}
+		createOwnerReferenceAdmission(util.GetCopyright(copyright))
+		return
+	}
+
+	if len(labelPolicy) == 0 && len(annotationPolicy) == 0 {
+		klog.Fatalf("Must specify at least one --label-policy, --annotation-policy or --parent-kind")
+	}
+	for _, key := range append(append([]string{}, labelPolicy...), annotationPolicy...) {
+		if errs := utilvalidation.IsQualifiedName(key); len(errs) > 0 {
+			klog.Fatalf("policy key %q has bad format: %s", key, strings.Join(errs, ","))
+		}
+	}
+
+	createAdmission(util.GetCopyright(copyright))
+}
+
+func createAdmission(boilerplate string) {
+	dir, err := os.Getwd()
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	a := admissionPolicyTemplateArgs{
+		BoilerPlate:         boilerplate,
+		Repo:                util.Repo,
+		Group:               groupName,
+		Version:             versionName,
+		Kind:                kindName,
+		Resource:            resourceName,
+		RequiredLabels:      labelPolicy,
+		RequiredAnnotations: annotationPolicy,
+	}
+
+	path := filepath.Join(dir, "plugin", "admission", "initializer.go")
+	util.WriteIfNotFound(path, "admission-initializer-template", admissionControllerInitializerTemplate, a)
+
+	pluginDir := filepath.Join(dir, "plugin", "admission", strings.ToLower(kindName))
+	path = filepath.Join(pluginDir, "admission.go")
+	if !util.WriteIfNotFound(path, "admission-policy-template", admissionPolicyTemplate, a) {
+		klog.Fatalf("admission controller for kind %s already exists.", kindName)
+	}
+
+	path = filepath.Join(pluginDir, "admission_test.go")
+	util.WriteIfNotFound(path, "admission-policy-test-template", admissionPolicyTestTemplate, a)
+}
+
+func createOwnerReferenceAdmission(boilerplate string) {
+	dir, err := os.Getwd()
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	a := admissionOwnerReferenceTemplateArgs{
+		BoilerPlate:      boilerplate,
+		Repo:             util.Repo,
+		Group:            groupName,
+		Version:          versionName,
+		Kind:             kindName,
+		Resource:         resourceName,
+		ParentGroup:      parentGroup,
+		ParentVersion:    parentVersion,
+		ParentKind:       parentKind,
+		ParentName:       parentName,
+		ParentNamespaced: parentNamespaced,
+	}
+
+	path := filepath.Join(dir, "plugin", "admission", "initializer.go")
+	util.WriteIfNotFound(path, "admission-initializer-template", admissionControllerInitializerTemplate, a)
+
+	pluginDir := filepath.Join(dir, "plugin", "admission", strings.ToLower(kindName))
+	path = filepath.Join(pluginDir, "admission.go")
+	if !util.WriteIfNotFound(path, "admission-owner-reference-template", admissionOwnerReferenceTemplate, a) {
+		klog.Fatalf("admission controller for kind %s already exists.", kindName)
+	}
+
+	path = filepath.Join(pluginDir, "admission_test.go")
+	util.WriteIfNotFound(path, "admission-owner-reference-test-template", admissionOwnerReferenceTestTemplate, a)
+}
+
+type admissionPolicyTemplateArgs struct {
+	BoilerPlate         string
+	Repo                string
+	Group               string
+	Version             string
+	Kind                string
+	Resource            string
+	RequiredLabels      []string
+	RequiredAnnotations []string
+}
+
+var admissionPolicyTemplate = `
+{{.BoilerPlate}}
+
+package {{ lower .Kind }}admission
+
+import (
+	"context"
+
+	aggregatedadmission "{{.Repo}}/plugin/admission"
+	aggregatedinformerfactory "{{.Repo}}/pkg/client/informers_generated/externalversions"
+	aggregatedclientset "{{.Repo}}/pkg/client/clientset_generated/clientset"
+	"{{.Repo}}/pkg/apis/{{.Group}}"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+var _ admission.Interface 											= &{{ lower .Kind }}Plugin{}
+var _ admission.ValidationInterface 								= &{{ lower .Kind }}Plugin{}
+var _ aggregatedadmission.WantsAggregatedResourceInformerFactory 	= &{{ lower .Kind }}Plugin{}
+var _ aggregatedadmission.WantsAggregatedResourceClientSet 			= &{{ lower .Kind }}Plugin{}
+
+// {{ .Kind }}RequiredLabels are the label keys every {{ .Kind }} must carry.  The variables are prefixed with the
+// kind as the plugin packages are dot imported together by the plugin/admission/install package.
+var {{ .Kind }}RequiredLabels = []string{
+{{- range .RequiredLabels }}
+	"{{ . }}",
+{{- end }}
+}
+
+// {{ .Kind }}RequiredAnnotations are the annotation keys every {{ .Kind }} must carry.
+var {{ .Kind }}RequiredAnnotations = []string{
+{{- range .RequiredAnnotations }}
+	"{{ . }}",
+{{- end }}
+}
+
+func New{{ .Kind }}Plugin() *{{ lower .Kind }}Plugin {
+	return &{{ lower .Kind }}Plugin{
+		Handler:             admission.NewHandler(admission.Create, admission.Update),
+		RequiredLabels:      {{ .Kind }}RequiredLabels,
+		RequiredAnnotations: {{ .Kind }}RequiredAnnotations,
+	}
+}
+
+type {{ lower .Kind }}Plugin struct {
+	*admission.Handler
+
+	RequiredLabels      []string
+	RequiredAnnotations []string
+}
+
+func (p *{{ lower .Kind }}Plugin) ValidateInitialization() error {
+	return nil
+}
+
+// Validate rejects {{ .Kind }} objects missing any of the required label or annotation keys
+func (p *{{ lower .Kind }}Plugin) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetKind().GroupKind() != {{ .Group }}.Kind("{{ .Kind }}") || len(a.GetSubresource()) > 0 {
+		return nil
+	}
+	accessor, err := meta.Accessor(a.GetObject())
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	errs := field.ErrorList{}
+	labels := accessor.GetLabels()
+	for _, key := range p.RequiredLabels {
+		if _, found := labels[key]; !found {
+			errs = append(errs, field.Required(field.NewPath("metadata", "labels").Key(key), "label is required"))
+		}
+	}
+	annotations := accessor.GetAnnotations()
+	for _, key := range p.RequiredAnnotations {
+		if _, found := annotations[key]; !found {
+			errs = append(errs, field.Required(field.NewPath("metadata", "annotations").Key(key), "annotation is required"))
+		}
+	}
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(a.GetKind().GroupKind(), a.GetName(), errs)
+	}
+	return nil
+}
+
+func (p *{{ lower .Kind }}Plugin) SetAggregatedResourceInformerFactory(aggregatedinformerfactory.SharedInformerFactory) {}
+
+func (p *{{ lower .Kind }}Plugin) SetAggregatedResourceClientSet(aggregatedclientset.Interface) {}
+`
+
+var admissionPolicyTestTemplate = `
+{{.BoilerPlate}}
+
+package {{ lower .Kind }}admission
+
+import (
+	"context"
+	"testing"
+
+	"{{.Repo}}/pkg/apis/{{.Group}}"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+func newCreateAttributes(obj *{{ .Group }}.{{ .Kind }}) admission.Attributes {
+	return admission.NewAttributesRecord(
+		obj, nil,
+		{{ .Group }}.SchemeGroupVersion.WithKind("{{ .Kind }}"),
+		obj.Namespace, obj.Name,
+		{{ .Group }}.SchemeGroupVersion.WithResource("{{ .Resource }}"),
+		"", admission.Create, nil, false, nil)
+}
+
+func TestRejectsMissingPolicyKeys(t *testing.T) {
+	obj := &{{ .Group }}.{{ .Kind }}{}
+	obj.Name = "{{ lower .Kind }}-missing"
+
+	err := New{{ .Kind }}Plugin().Validate(context.TODO(), newCreateAttributes(obj), nil)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected an invalid error for an object without the required keys, got %v", err)
+	}
+}
+
+func TestAdmitsPolicyKeys(t *testing.T) {
+	obj := &{{ .Group }}.{{ .Kind }}{}
+	obj.Name = "{{ lower .Kind }}-valid"
+	obj.Labels = map[string]string{}
+	obj.Annotations = map[string]string{}
+	for _, key := range {{ .Kind }}RequiredLabels {
+		obj.Labels[key] = "value"
+	}
+	for _, key := range {{ .Kind }}RequiredAnnotations {
+		obj.Annotations[key] = "value"
+	}
+
+	if err := New{{ .Kind }}Plugin().Validate(context.TODO(), newCreateAttributes(obj), nil); err != nil {
+		t.Fatalf("expected object with the required keys to be admitted, got %v", err)
+	}
+}
+`
+
+type admissionOwnerReferenceTemplateArgs struct {
+	BoilerPlate      string
+	Repo             string
+	Group            string
+	Version          string
+	Kind             string
+	Resource         string
+	ParentGroup      string
+	ParentVersion    string
+	ParentKind       string
+	ParentName       string
+	ParentNamespaced bool
+}
+
+var admissionOwnerReferenceTemplate = `
+{{.BoilerPlate}}
+
+package {{ lower .Kind }}admission
+
+import (
+	"context"
+	"fmt"
+
+	aggregatedadmission "{{.Repo}}/plugin/admission"
+	aggregatedinformerfactory "{{.Repo}}/pkg/client/informers_generated/externalversions"
+	aggregatedclientset "{{.Repo}}/pkg/client/clientset_generated/clientset"
+	"{{.Repo}}/pkg/apis/{{.Group}}"
+	{{ .ParentGroup }}{{ .ParentVersion }} "{{.Repo}}/pkg/apis/{{.ParentGroup}}/{{.ParentVersion}}"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+var _ admission.Interface 											= &{{ lower .Kind }}Plugin{}
+var _ admission.MutationInterface 									= &{{ lower .Kind }}Plugin{}
+var _ aggregatedadmission.WantsAggregatedResourceInformerFactory 	= &{{ lower .Kind }}Plugin{}
+var _ aggregatedadmission.WantsAggregatedResourceClientSet 			= &{{ lower .Kind }}Plugin{}
+
+// {{ .Kind }}ParentGroupVersionKind is the kind of the parent every created {{ .Kind }} references as its owner.
+// The variables are prefixed with the kind as the plugin packages are dot imported together by the
+// plugin/admission/install package.
+var {{ .Kind }}ParentGroupVersionKind = {{ .ParentGroup }}{{ .ParentVersion }}.SchemeGroupVersion.WithKind("{{ .ParentKind }}")
+
+// {{ .Kind }}ParentName is the name of the parent every created {{ .Kind }} references as its owner.
+var {{ .Kind }}ParentName = "{{ .ParentName }}"
+
+func New{{ .Kind }}Plugin() *{{ lower .Kind }}Plugin {
+	return &{{ lower .Kind }}Plugin{
+		Handler:                admission.NewHandler(admission.Create),
+		ParentGroupVersionKind: {{ .Kind }}ParentGroupVersionKind,
+		ParentName:             {{ .Kind }}ParentName,
+	}
+}
+
+type {{ lower .Kind }}Plugin struct {
+	*admission.Handler
+
+	ParentGroupVersionKind schema.GroupVersionKind
+	ParentName             string
+
+	// ParentUID returns the uid of the parent{{ if .ParentNamespaced }} in the namespace{{ end }}.  It reads
+	// the parent with the aggregated resource clientset.
+	ParentUID func(ctx context.Context, namespace string) (types.UID, error)
+}
+
+func (p *{{ lower .Kind }}Plugin) ValidateInitialization() error {
+	if p.ParentUID == nil {
+		return fmt.Errorf("missing the aggregated resource clientset reading the parent %s", p.ParentGroupVersionKind.Kind)
+	}
+	return nil
+}
+
+// Admit sets an owner reference to the parent on created {{ .Kind }} objects
+func (p *{{ lower .Kind }}Plugin) Admit(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetKind().GroupKind() != {{ .Group }}.Kind("{{ .Kind }}") || len(a.GetSubresource()) > 0 {
+		return nil
+	}
+	accessor, err := meta.Accessor(a.GetObject())
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	uid, err := p.ParentUID(ctx, a.GetNamespace())
+	if apierrors.IsNotFound(err) {
+		return admission.NewForbidden(a, fmt.Errorf("parent %s %s not found", p.ParentGroupVersionKind.Kind, p.ParentName))
+	}
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	for _, ref := range accessor.GetOwnerReferences() {
+		if ref.UID == uid {
+			return nil
+		}
+	}
+	apiVersion, kind := p.ParentGroupVersionKind.ToAPIVersionAndKind()
+	accessor.SetOwnerReferences(append(accessor.GetOwnerReferences(), metav1.OwnerReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       p.ParentName,
+		UID:        uid,
+	}))
+	return nil
+}
+
+func (p *{{ lower .Kind }}Plugin) SetAggregatedResourceInformerFactory(aggregatedinformerfactory.SharedInformerFactory) {}
+
+func (p *{{ lower .Kind }}Plugin) SetAggregatedResourceClientSet(client aggregatedclientset.Interface) {
+	p.ParentUID = func(ctx context.Context, namespace string) (types.UID, error) {
+		parent, err := client.{{ title .ParentGroup }}{{ title .ParentVersion }}().{{ plural .ParentKind }}({{ if .ParentNamespaced }}namespace{{ end }}).Get(ctx, p.ParentName, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		return parent.UID, nil
+	}
+}
+`
+
+var admissionOwnerReferenceTestTemplate = `
+{{.BoilerPlate}}
+
+package {{ lower .Kind }}admission
+
+import (
+	"context"
+	"testing"
+
+	"{{.Repo}}/pkg/apis/{{.Group}}"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+func newCreateAttributes(obj *{{ .Group }}.{{ .Kind }}) admission.Attributes {
+	return admission.NewAttributesRecord(
+		obj, nil,
+		{{ .Group }}.SchemeGroupVersion.WithKind("{{ .Kind }}"),
+		obj.Namespace, obj.Name,
+		{{ .Group }}.SchemeGroupVersion.WithResource("{{ .Resource }}"),
+		"", admission.Create, nil, false, nil)
+}
+
+func TestSetsOwnerReference(t *testing.T) {
+	obj := &{{ .Group }}.{{ .Kind }}{}
+	obj.Name = "{{ lower .Kind }}-owned"
+
+	p := New{{ .Kind }}Plugin()
+	p.ParentUID = func(ctx context.Context, namespace string) (types.UID, error) {
+		return "parent-uid", nil
+	}
+	if err := p.Admit(context.TODO(), newCreateAttributes(obj), nil); err != nil {
+		t.Fatalf("expected the object to be admitted, got %v", err)
+	}
+	if len(obj.OwnerReferences) != 1 {
+		t.Fatalf("expected a single owner reference, got %v", obj.OwnerReferences)
+	}
+	ref := obj.OwnerReferences[0]
+	apiVersion, kind := {{ .Kind }}ParentGroupVersionKind.ToAPIVersionAndKind()
+	if ref.APIVersion != apiVersion || ref.Kind != kind || ref.Name != {{ .Kind }}ParentName || ref.UID != "parent-uid" {
+		t.Errorf("expected an owner reference to the %s %s, got %v", kind, {{ .Kind }}ParentName, ref)
+	}
+
+	// Admitting the object again leaves the owner reference in place
+	if err := p.Admit(context.TODO(), newCreateAttributes(obj), nil); err != nil {
+		t.Fatalf("expected the object to be admitted, got %v", err)
+	}
+	if len(obj.OwnerReferences) != 1 {
+		t.Errorf("expected a single owner reference, got %v", obj.OwnerReferences)
+	}
+}
+
+func TestRejectsMissingParent(t *testing.T) {
+	obj := &{{ .Group }}.{{ .Kind }}{}
+	obj.Name = "{{ lower .Kind }}-orphan"
+
+	p := New{{ .Kind }}Plugin()
+	p.ParentUID = func(ctx context.Context, namespace string) (types.UID, error) {
+		return "", apierrors.NewNotFound(schema.GroupResource{Resource: "{{ lower (plural .ParentKind) }}"}, {{ .Kind }}ParentName)
+	}
+	if err := p.Admit(context.TODO(), newCreateAttributes(obj), nil); !apierrors.IsForbidden(err) {
+		t.Fatalf("expected a forbidden error for an object without its parent, got %v", err)
+	}
+}
+`
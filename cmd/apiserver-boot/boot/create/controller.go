@@ -106,6 +106,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/controller/result"
 	{{ .Resource.Group}}{{ .Resource.Version }} "{{ .ResourcePackage }}/{{ .Resource.Group}}/{{ .Resource.Version }}"
 )
 
@@ -121,6 +122,7 @@ var log = logf.Log.WithName("{{ lower .Resource.Kind }}-controller")
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/controller/result"
 	{{ .Resource.Group}}{{ .Resource.Version }} "{{ .ResourcePackage }}/{{ .Resource.Group}}/{{ .Resource.Version }}"
 )
 {{ end -}}
@@ -195,10 +197,10 @@ func (r *Reconcile{{ .Resource.Kind }}) Reconcile(request reconcile.Request) (re
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
-			return reconcile.Result{}, nil
+			return result.Done()
 		}
 		// Error reading the object - requeue the request.
-		return reconcile.Result{}, err
+		return result.Error(err)
 	}
 
 	{{ if .Resource.CreateExampleReconcileBody -}}
@@ -227,7 +229,7 @@ func (r *Reconcile{{ .Resource.Kind }}) Reconcile(request reconcile.Request) (re
 		},
 	}
 	if err := controllerutil.SetControllerReference(instance, deploy, r.scheme); err != nil {
-		return reconcile.Result{}, err
+		return result.Error(err)
 	}
 
 	// TODO(user): Change this for the object type created by your controller
@@ -236,10 +238,12 @@ func (r *Reconcile{{ .Resource.Kind }}) Reconcile(request reconcile.Request) (re
 	err = r.Get(context.TODO(), types.NamespacedName{Name: deploy.Name, Namespace: deploy.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
 		log.Info("Creating Deployment", "namespace", deploy.Namespace, "name", deploy.Name)
-		err = r.Create(context.TODO(), deploy)
-		return reconcile.Result{}, err
+		if err := r.Create(context.TODO(), deploy); err != nil {
+			return result.Error(err)
+		}
+		return result.Done()
 	} else if err != nil {
-		return reconcile.Result{}, err
+		return result.Error(err)
 	}
 
 	// TODO(user): Change this for the object type created by your controller
@@ -249,11 +253,11 @@ func (r *Reconcile{{ .Resource.Kind }}) Reconcile(request reconcile.Request) (re
 		log.Info("Updating Deployment", "namespace", deploy.Namespace, "name", deploy.Name)
 		err = r.Update(context.TODO(), found)
 		if err != nil {
-			return reconcile.Result{}, err
+			return result.Error(err)
 		}
 	}
 	{{ end -}}
 
-	return reconcile.Result{}, nil
+	return result.Done()
 }
 `
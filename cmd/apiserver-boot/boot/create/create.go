@@ -42,6 +42,7 @@ var copyright string
 func AddCreate(cmd *cobra.Command) {
 	cmd.AddCommand(createCmd)
 	cmd.Flags().StringVar(&copyright, "copyright", "boilerplate.go.txt", "Location of copyright boilerplate file.")
+	AddCreateAdmission(createCmd)
 	AddCreateGroup(createCmd)
 	AddCreateResource(createCmd)
 	AddCreateSubresource(createCmd)
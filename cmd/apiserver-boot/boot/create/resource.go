@@ -41,6 +41,7 @@ var nonNamespacedKind bool
 var skipGenerateAdmissionController bool
 var skipGenerateResource bool
 var skipGenerateController bool
+var behaviorTests bool
 
 var createResourceCmd = &cobra.Command{
 	Use:   "resource",
@@ -61,6 +62,7 @@ func AddCreateResource(cmd *cobra.Command) {
 	createResourceCmd.Flags().BoolVar(&skipGenerateResource, "skip-resource", false, "if set, the resources will not be generated")
 	createResourceCmd.Flags().BoolVar(&skipGenerateController, "skip-controller", false, "if set, the controller will not be generated")
 	createResourceCmd.Flags().BoolVar(&skipGenerateAdmissionController, "skip-admission-controller", false, "if set, the admission controller will not be generated")
+	createResourceCmd.Flags().BoolVar(&behaviorTests, "behavior-tests", false, "if set, a table-driven test of the defaulting and validation of the resource will be generated")
 
 	cmd.AddCommand(createResourceCmd)
 }
@@ -180,6 +182,19 @@ func createResource(boilerplate string) {
 				found = true
 			}
 		}
+
+		if behaviorTests {
+			typesFileName = fmt.Sprintf("%s_behavior_test.go", strings.ToLower(kindName))
+			path = filepath.Join(dir, "pkg", "apis", groupName, versionName, typesFileName)
+			created = util.WriteIfNotFound(path, "resource-behavior-test-template", resourceBehaviorTestTemplate, a)
+			if !created {
+				if !found {
+					klog.Infof("API group version kind %s/%s/%s behavior test already exists.",
+						groupName, versionName, kindName)
+					found = true
+				}
+			}
+		}
 	}
 
 	if !skipGenerateAdmissionController {
@@ -451,6 +466,68 @@ var _ = Describe("{{.Kind}}", func() {
 })
 `
 
+var resourceBehaviorTestTemplate = `
+{{.BoilerPlate}}
+
+package {{.Version}}_test
+
+import (
+	"context"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	_ "{{.Repo}}/pkg/apis"
+	"{{.Repo}}/pkg/apis/{{.Group}}"
+	. "{{.Repo}}/pkg/apis/{{.Group}}/{{.Version}}"
+)
+
+// Test{{.Kind}}Behavior defaults each case with the defaulting functions registered for {{.Kind}}
+// and validates the result with {{.Kind}}Strategy
+func Test{{.Kind}}Behavior(t *testing.T) {
+	tests := []struct {
+		name string
+		// in is the {{.Kind}} before defaulting
+		in *{{.Kind}}
+		// defaulted is the {{.Kind}} expected after defaulting
+		defaulted *{{.Kind}}
+		// valid is true if the defaulted {{.Kind}} passes validation
+		valid bool
+	}{
+		{
+			name:      "empty",
+			in:        &{{.Kind}}{},
+			defaulted: &{{.Kind}}{},
+			valid:     true,
+		},
+		// TODO: add cases for the defaulting and validation of {{.Kind}}
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := tt.in.DeepCopy()
+			builders.Scheme.Default(obj)
+			if !apiequality.Semantic.DeepEqual(tt.defaulted, obj) {
+				t.Errorf("unexpected defaults: %s", diff.ObjectReflectDiff(tt.defaulted, obj))
+			}
+
+			internal := &{{.Group}}.{{.Kind}}{}
+			if err := builders.Scheme.Convert(obj, internal, nil); err != nil {
+				t.Fatalf("failed to convert %T to %T: %v", obj, internal, err)
+			}
+			errs := {{.Group}}.{{.Kind}}Strategy{}.Validate(context.TODO(), internal)
+			if tt.valid && len(errs) > 0 {
+				t.Errorf("unexpected validation errors: %v", errs.ToAggregate())
+			}
+			if !tt.valid && len(errs) == 0 {
+				t.Errorf("expected validation errors")
+			}
+		})
+	}
+}
+`
+
 var exampleTemplate = `note: {{ .Kind }} Example
 sample: |
   apiVersion: {{ .Group }}.{{ .Domain }}/{{ .Version }}
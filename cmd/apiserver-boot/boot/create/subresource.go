@@ -214,6 +214,7 @@ import (
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// +k8s:openapi-gen=true
 // +subresource-request
 type {{title .SubresourceKind}} struct {
 	metav1.TypeMeta   ` + "`" + `json:",inline"` + "`" + `
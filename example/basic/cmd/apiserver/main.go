@@ -17,6 +17,11 @@ limitations under the License.
 package main
 
 import (
+	"net/http"
+
+	"k8s.io/apiserver/pkg/server/healthz"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
 	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
 	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/openapi"
 	"sigs.k8s.io/apiserver-builder-alpha/pkg/cmd/server"
@@ -26,6 +31,11 @@ import (
 )
 
 func main() {
+	// Custom checks are served under /healthz, which fails while any of them fails
+	builders.AddHealthzCheck(healthz.NamedCheck("example", func(_ *http.Request) error {
+		return nil
+	}))
+
 	err := server.StartApiServerWithOptions(&server.StartOptions{
 		EtcdPath:    "/registry/sample.kubernetes.io",
 		Apis:        apis.GetAllApiBuilders(),
@@ -3,6 +3,7 @@ module sigs.k8s.io/apiserver-builder-alpha/example/basic
 go 1.13
 
 require (
+	github.com/go-openapi/spec v0.19.3
 	github.com/markbates/inflect v0.0.0-00010101000000-000000000000
 	github.com/onsi/ginkgo v1.11.0
 	github.com/onsi/gomega v1.8.1
@@ -14,6 +15,7 @@ require (
 	k8s.io/apiserver v0.18.4
 	k8s.io/client-go v0.18.4
 	k8s.io/code-generator v0.18.4
+	k8s.io/component-base v0.18.4
 	k8s.io/gengo v0.0.0-20200114144118-36b2048a9120
 	k8s.io/klog v1.0.0
 	k8s.io/kube-aggregator v0.18.4
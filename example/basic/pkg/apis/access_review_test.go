@@ -0,0 +1,82 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	innsmouthv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1"
+	kingsportv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1"
+)
+
+// TestAccessReview checks the SelfSubjectAccessReviews of the namespaced DeepOne and of the cluster scoped Festival
+// review the access to their resource, and the allowed status of the created review is returned
+func TestAccessReview(t *testing.T) {
+	review := innsmouthv1.NewDeepOneAccessReview("update", "innsmouth", "obed")
+	expected := &authorizationv1.ResourceAttributes{
+		Namespace: "innsmouth",
+		Verb:      "update",
+		Group:     "innsmouth.k8s.io",
+		Version:   "v1",
+		Resource:  "deepones",
+		Name:      "obed",
+	}
+	if !reflect.DeepEqual(review.Spec.ResourceAttributes, expected) {
+		t.Errorf("expected the resource attributes %+v, got %+v", expected, review.Spec.ResourceAttributes)
+	}
+
+	review = kingsportv1.NewFestivalAccessReview("delete", "harvest")
+	expected = &authorizationv1.ResourceAttributes{
+		Verb:     "delete",
+		Group:    "kingsport.k8s.io",
+		Version:  "v1",
+		Resource: "festivals",
+		Name:     "harvest",
+	}
+	if !reflect.DeepEqual(review.Spec.ResourceAttributes, expected) {
+		t.Errorf("expected the resource attributes %+v, got %+v", expected, review.Spec.ResourceAttributes)
+	}
+
+	// Only the deepones of innsmouth may be listed
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			attributes := review.Spec.ResourceAttributes
+			review.Status.Allowed = attributes.Resource == "deepones" && attributes.Namespace == "innsmouth" &&
+				attributes.Verb == "list"
+			return true, review, nil
+		})
+	ctx := context.Background()
+	allowed, err := innsmouthv1.CanDeepOne(ctx, client.AuthorizationV1(), "list", "innsmouth", "")
+	if err != nil || !allowed {
+		t.Errorf("expected listing the deepones of innsmouth to be allowed, got %v, %v", allowed, err)
+	}
+	if allowed, err := innsmouthv1.CanDeepOne(ctx, client.AuthorizationV1(), "list", "arkham", ""); err != nil || allowed {
+		t.Errorf("expected listing the deepones of arkham not to be allowed, got %v, %v", allowed, err)
+	}
+	if allowed, err := kingsportv1.CanFestival(ctx, client.AuthorizationV1(), "list", ""); err != nil || allowed {
+		t.Errorf("expected listing the festivals not to be allowed, got %v, %v", allowed, err)
+	}
+}
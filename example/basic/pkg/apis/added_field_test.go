@@ -0,0 +1,70 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+	innsmouthv1alpha1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1alpha1"
+	innsmouthv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1beta1"
+)
+
+// TestAddedField checks the tentacles of shoggoths, added in v1beta1, are dropped converting to v1alpha1 and
+// zeroed converting from v1alpha1 by generated conversions rather than left to be written by hand
+func TestAddedField(t *testing.T) {
+	shoggoth := &innsmouthv1beta1.Shoggoth{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "innsmouth", Name: "tekeli-li"},
+		Spec:       innsmouthv1beta1.ShoggothSpec{Eyes: 7, Tentacles: 12},
+	}
+	internal := &innsmouth.Shoggoth{}
+	if err := builders.Scheme.Convert(shoggoth, internal, nil); err != nil {
+		t.Fatal(err)
+	}
+	if internal.Spec.Tentacles != 12 {
+		t.Errorf("expected the unversioned shoggoth to keep the tentacles, got %d", internal.Spec.Tentacles)
+	}
+
+	old := &innsmouthv1alpha1.Shoggoth{}
+	if err := builders.Scheme.Convert(internal, old, nil); err != nil {
+		t.Fatal(err)
+	}
+	if old.Spec.Eyes != 7 {
+		t.Errorf("expected the v1alpha1 shoggoth to keep the eyes, got %d", old.Spec.Eyes)
+	}
+
+	// The shoggoth converted from v1alpha1 has no tentacles, even converted into an object which had
+	if err := builders.Scheme.Convert(old, internal, nil); err != nil {
+		t.Fatal(err)
+	}
+	if internal.Spec.Eyes != 7 || internal.Spec.Tentacles != 0 {
+		t.Errorf("expected the shoggoth converted from v1alpha1 to have 7 eyes and no tentacles, got %v", internal.Spec)
+	}
+
+	generated, err := ioutil.ReadFile("innsmouth/v1alpha1/zz_generated.api.register.conversion.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(generated), "TODO") {
+		t.Errorf("expected the conversions of the added field to be generated, got a TODO:\n%s", generated)
+	}
+}
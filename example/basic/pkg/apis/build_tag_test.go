@@ -0,0 +1,60 @@
+//go:build !experimental
+// +build !experimental
+
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport"
+	kingsportv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1"
+)
+
+// TestBuildTagExcludesResource checks the code generated for Ritual, which has a +buildTag=experimental
+// comment, is left out of a build without the experimental tag: rituals are neither registered with the
+// unversioned nor the versioned apis of the kingsport group, nor constructed by GVKToType
+func TestBuildTagExcludesResource(t *testing.T) {
+	for _, group := range apis.GetAllApiBuilders() {
+		for _, kind := range group.UnVersioned.Kinds {
+			if kind.GetName() == "rituals" {
+				t.Errorf("expected rituals not to be registered with the unversioned %s api", group.Name)
+			}
+		}
+		for _, version := range group.Versions {
+			for _, kind := range version.Kinds {
+				if kind.Unversioned.GetName() == "rituals" {
+					t.Errorf("expected rituals not to be served by %v", version.GroupVersion)
+				}
+			}
+		}
+	}
+	for _, gvk := range []schema.GroupVersionKind{
+		kingsportv1.SchemeGroupVersion.WithKind("Ritual"),
+		kingsportv1.SchemeGroupVersion.WithKind("RitualList"),
+		kingsport.SchemeGroupVersion.WithKind("Ritual"),
+		kingsport.SchemeGroupVersion.WithKind("RitualList"),
+	} {
+		if _, found := apis.GVKToType[gvk]; found {
+			t.Errorf("expected no constructor of %v", gvk)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// TestBuilder checks the generated builder of Universities sets the metadata and the top-level spec fields of
+// the built object, and builds independent copies
+func TestBuilder(t *testing.T) {
+	maxStudents := 150
+	builder := miskatonicv1beta1.NewUniversityBuilder().
+		WithName("miskatonic").
+		WithNamespace("arkham").
+		WithFacultySize(15).
+		WithMaxStudents(&maxStudents).
+		WithTier("ivy")
+	university := builder.Build()
+
+	if gvk := university.GroupVersionKind(); gvk != miskatonicv1beta1.SchemeGroupVersion.WithKind("University") {
+		t.Errorf("expected the built university to be a miskatonic.k8s.io/v1beta1 University, got %v", gvk)
+	}
+	if university.Name != "miskatonic" || university.Namespace != "arkham" {
+		t.Errorf("expected the university arkham/miskatonic, got %s/%s", university.Namespace, university.Name)
+	}
+	if university.Spec.FacultySize != 15 || university.Spec.Tier != "ivy" {
+		t.Errorf("expected the faculty size 15 and the tier ivy, got %d and %q", university.Spec.FacultySize, university.Spec.Tier)
+	}
+	if university.Spec.MaxStudents == nil || *university.Spec.MaxStudents != 150 {
+		t.Errorf("expected the max students to be 150, got %v", university.Spec.MaxStudents)
+	}
+
+	other := builder.WithName("brown").WithFacultySize(20).Build()
+	if university.Name != "miskatonic" || university.Spec.FacultySize != 15 {
+		t.Errorf("expected building another university to not change the built one, got %s with the faculty size %d",
+			university.Name, university.Spec.FacultySize)
+	}
+	if other.Name != "brown" || other.Spec.Tier != "ivy" {
+		t.Errorf("expected the other university to be brown and keep the tier ivy, got %s with the tier %q",
+			other.Name, other.Spec.Tier)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+)
+
+// TestCELValidation checks Shoggoths are validated against the "+kubebuilder:validation:XValidation" rules of
+// their v1beta1 types
+func TestCELValidation(t *testing.T) {
+	strategy := innsmouth.InnsmouthShoggothStorage.StorageBuilder
+	for _, test := range []struct {
+		name     string
+		shoggoth *innsmouth.Shoggoth
+		expected field.ErrorList
+		// rendered is the rendered error, checking its field path
+		rendered string
+	}{
+		{
+			name:     "no spec or status",
+			shoggoth: &innsmouth.Shoggoth{ObjectMeta: metav1.ObjectMeta{Name: "shoggoth", Namespace: "innsmouth"}},
+		},
+		{
+			name: "valid",
+			shoggoth: &innsmouth.Shoggoth{
+				ObjectMeta: metav1.ObjectMeta{Name: "shoggoth", Namespace: "innsmouth"},
+				Spec:       innsmouth.ShoggothSpec{Eyes: 1000},
+				Status:     innsmouth.ShoggothStatus{Master: "deepone"},
+			},
+		},
+		{
+			name: "too many eyes",
+			shoggoth: &innsmouth.Shoggoth{
+				ObjectMeta: metav1.ObjectMeta{Name: "shoggoth", Namespace: "innsmouth"},
+				Spec:       innsmouth.ShoggothSpec{Eyes: 1001},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "eyes"), int64(1001), "a shoggoth forms at most 1000 eyes"),
+			},
+			rendered: "spec.eyes: Invalid value: 1001: a shoggoth forms at most 1000 eyes",
+		},
+		{
+			name: "serving itself",
+			shoggoth: &innsmouth.Shoggoth{
+				ObjectMeta: metav1.ObjectMeta{Name: "shoggoth", Namespace: "innsmouth"},
+				Status:     innsmouth.ShoggothStatus{Master: "shoggoth"},
+			},
+			expected: field.ErrorList{
+				field.Invalid(nil, "object", "a shoggoth cannot serve itself"),
+			},
+			rendered: `: Invalid value: "object": a shoggoth cannot serve itself`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			errs := strategy.Validate(context.Background(), test.shoggoth)
+			if fmt.Sprint(errs.ToAggregate()) != fmt.Sprint(test.expected.ToAggregate()) {
+				t.Errorf("expected %v, got %v", test.expected, errs)
+			}
+			if len(test.rendered) > 0 && (len(errs) != 1 || errs[0].Error() != test.rendered) {
+				t.Errorf("expected the error %q, got %v", test.rendered, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/runtime/serializer/versioning"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+)
+
+// The compatibility tests are a smoke test of the generated code against the libraries the
+// project is built with.  Run them after upgrading k8s.io/apimachinery or k8s.io/apiserver.
+
+// TestSchemeRegistration checks every resource is registered with both its versioned and internal type
+func TestSchemeRegistration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	apis.Install(scheme)
+	for _, group := range apis.GetAllApiBuilders() {
+		for _, version := range group.Versions {
+			for _, kind := range version.Kinds {
+				for _, obj := range []runtime.Object{kind.New(), kind.Unversioned.New()} {
+					if obj == nil {
+						continue
+					}
+					if _, _, err := scheme.ObjectKinds(obj); err != nil {
+						t.Errorf("%T is not registered: %v", obj, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestRoundTrip encodes every versioned type, decodes it to the internal type and converts it back
+func TestRoundTrip(t *testing.T) {
+	scheme := runtime.NewScheme()
+	apis.Install(scheme)
+	info, _ := runtime.SerializerInfoForMediaType(serializer.NewCodecFactory(scheme).SupportedMediaTypes(), runtime.ContentTypeJSON)
+	for _, group := range apis.GetAllApiBuilders() {
+		for _, version := range group.Versions {
+			// Skip defaulting so the objects are unchanged by the round trip
+			codec := versioning.NewCodec(info.Serializer, info.Serializer, runtime.UnsafeObjectConvertor(scheme),
+				scheme, scheme, nil, version.GroupVersion, runtime.InternalGroupVersioner, scheme.Name())
+			for _, kind := range version.Kinds {
+				obj := kind.New()
+				if obj == nil {
+					continue
+				}
+				data, err := runtime.Encode(codec, obj)
+				if err != nil {
+					t.Errorf("failed to encode %T: %v", obj, err)
+					continue
+				}
+				internal, err := runtime.Decode(codec, data)
+				if err != nil {
+					t.Errorf("failed to decode %T: %v", obj, err)
+					continue
+				}
+				out := kind.New()
+				if err := scheme.Convert(internal, out, nil); err != nil {
+					t.Errorf("failed to convert %T to %T: %v", internal, out, err)
+					continue
+				}
+				if !apiequality.Semantic.DeepEqual(obj, out) {
+					t.Errorf("%T changed by the round trip: %s", obj, diff.ObjectReflectDiff(obj, out))
+				}
+			}
+		}
+	}
+}
+
+// TestStorage constructs the storage of every resource without connecting to etcd
+func TestStorage(t *testing.T) {
+	for _, group := range apis.GetAllApiBuilders() {
+		info := group.Build(noopRESTOptionsGetter{})
+		for version, storages := range info.VersionedResourcesStorageMap {
+			if len(storages) == 0 {
+				t.Errorf("no storage constructed for %s/%s", group.Name, version)
+			}
+		}
+	}
+}
+
+type noopRESTOptionsGetter struct{}
+
+func (noopRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	return generic.RESTOptions{
+		StorageConfig:  &storagebackend.Config{Codec: builders.Codecs.LegacyCodec()},
+		Decorator:      noopStorage,
+		ResourcePrefix: resource.Group + "/" + resource.Resource,
+	}, nil
+}
+
+func noopStorage(*storagebackend.Config, string, func(runtime.Object) (string, error), func() runtime.Object,
+	func() runtime.Object, storage.AttrFunc, storage.IndexerFuncs, *cache.Indexers) (storage.Interface, factory.DestroyFunc, error) {
+	return nil, func() {}, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+	_ "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/install"
+	innsmouthv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1beta1"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+)
+
+// review posts a ConversionReview of the objects to the ConversionReviewHandler of innsmouth and returns its response
+func review(t *testing.T, desiredAPIVersion string, objects ...string) *builders.ConversionResponse {
+	request := &builders.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request:  &builders.ConversionRequest{UID: "7b0e8b83", DesiredAPIVersion: desiredAPIVersion},
+	}
+	for _, obj := range objects {
+		request.Request.Objects = append(request.Request.Objects, runtime.RawExtension{Raw: []byte(obj)})
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	innsmouth.ConversionReviewHandler.ServeHTTP(recorder, httptest.NewRequest("POST", "/convert", bytes.NewReader(body)))
+	response := &builders.ConversionReview{}
+	if err := json.NewDecoder(recorder.Body).Decode(response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Response == nil || response.Response.UID != request.Request.UID {
+		t.Fatalf("expected the response of %s, got %+v", request.Request.UID, response.Response)
+	}
+	return response.Response
+}
+
+// TestConversionReviewHandler checks the objects converted by the handler are defaulted for the desired version
+func TestConversionReviewHandler(t *testing.T) {
+	response := review(t, "innsmouth.k8s.io/v1beta1",
+		`{"apiVersion":"innsmouth.k8s.io/v1alpha1","kind":"Shoggoth","metadata":{"name":"tekeli-li"},"status":{"master":"obed"}}`)
+	if response.Result.Status != metav1.StatusSuccess || len(response.ConvertedObjects) != 1 {
+		t.Fatalf("expected a converted object, got %+v", response)
+	}
+	shoggoth := &innsmouthv1beta1.Shoggoth{}
+	if err := json.Unmarshal(response.ConvertedObjects[0].Raw, shoggoth); err != nil {
+		t.Fatal(err)
+	}
+	if shoggoth.APIVersion != "innsmouth.k8s.io/v1beta1" || shoggoth.Kind != "Shoggoth" {
+		t.Errorf("expected a v1beta1 Shoggoth, got %v", shoggoth.TypeMeta)
+	}
+	if shoggoth.Spec.Eyes != 3 {
+		t.Errorf("expected the eyes to be defaulted to 3, got %d", shoggoth.Spec.Eyes)
+	}
+	if shoggoth.Name != "tekeli-li" || shoggoth.Status.Master != "obed" {
+		t.Errorf("expected the name and status of the object to be converted, got %+v", shoggoth)
+	}
+
+	response = review(t, "miskatonic.k8s.io/v1beta1",
+		`{"apiVersion":"innsmouth.k8s.io/v1alpha1","kind":"Shoggoth","metadata":{"name":"tekeli-li"}}`)
+	if response.Result.Status != metav1.StatusFailure || len(response.ConvertedObjects) != 0 {
+		t.Errorf("expected the conversion to another group to fail, got %+v", response)
+	}
+}
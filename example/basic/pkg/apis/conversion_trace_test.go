@@ -0,0 +1,69 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/klog"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+)
+
+// TestConversionTrace checks the trace of a lossy conversion logs the field lost by the conversion
+func TestConversionTrace(t *testing.T) {
+	flags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(flags)
+	flags.Set("logtostderr", "false")
+	defer flags.Set("logtostderr", "true")
+	var log bytes.Buffer
+	klog.SetOutput(&log)
+	defer klog.SetOutput(os.Stderr)
+
+	// Manual.C has no peer in the unversioned University, so it is dropped without a conversion webhook
+	university := &miskatonicv1beta1.University{}
+	university.Name = "miskatonic"
+	university.Spec.FacultySize = 7
+	university.Spec.Manual.C = "necronomicon"
+	convert := func(trace bool) string {
+		builders.ConversionTrace = trace
+		defer func() { builders.ConversionTrace = false }()
+		log.Reset()
+		if err := builders.Scheme.Convert(university, &miskatonic.University{}, nil); err != nil {
+			t.Fatal(err)
+		}
+		klog.Flush()
+		return log.String()
+	}
+
+	if out := convert(false); strings.Contains(out, "loses the field") {
+		t.Errorf("expected no trace while the trace is disabled, got %s", out)
+	}
+	out := convert(true)
+	if !strings.Contains(out, `University "miskatonic" loses the field spec.Manual.C: "necronomicon" before, "" after`) {
+		t.Errorf("expected the trace to log the lost spec.Manual.C field, got %s", out)
+	}
+	if strings.Contains(out, "faculty_size") {
+		t.Errorf("expected the converted faculty_size to not be traced, got %s", out)
+	}
+}
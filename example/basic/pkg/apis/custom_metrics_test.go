@@ -0,0 +1,89 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+	innsmouthv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1"
+	innsmouthtestclient "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1/testclient"
+)
+
+// TestCustomMetrics checks the custom metrics API serves the fish metric of the "+metric" comment of DeepOnes
+func TestCustomMetrics(t *testing.T) {
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	apis.GetInnsmouthAPIBuilder().Build(getter)
+
+	deepOnes := innsmouthtestclient.DeepOnes("innsmouth")
+	deepOne := &innsmouthv1.DeepOne{}
+	deepOne.Name = "obed"
+	deepOne.Labels = map[string]string{"reef": "devil"}
+	deepOne.Spec.FishRequired = 3
+	created, err := deepOnes.Create(deepOne)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created.Status.ActualFish = 42
+	if _, err := deepOnes.UpdateStatus(created); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := builders.NewCustomMetricsHandler(builders.CustomMetrics...)
+	for _, path := range []string{
+		"/apis/custom.metrics.k8s.io/v1beta1/namespaces/innsmouth/deepones.innsmouth.k8s.io/obed/fish",
+		"/apis/custom.metrics.k8s.io/v1beta1/namespaces/innsmouth/deepones.innsmouth.k8s.io/*/fish?labelSelector=reef%3Ddevil",
+	} {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+		if resp.Code != http.StatusOK {
+			t.Fatalf("expected %s to be served, got %d: %s", path, resp.Code, resp.Body)
+		}
+		list := &builders.MetricValueList{}
+		if err := json.Unmarshal(resp.Body.Bytes(), list); err != nil {
+			t.Fatal(err)
+		}
+		if len(list.Items) != 1 {
+			t.Fatalf("expected the metric of obed from %s, got %+v", path, list.Items)
+		}
+		item := list.Items[0]
+		if item.MetricName != "fish" || item.Value.Value() != 42 || item.DescribedObject.Kind != "DeepOne" ||
+			item.DescribedObject.APIVersion != "innsmouth.k8s.io/v1" || item.DescribedObject.Name != "obed" ||
+			item.DescribedObject.Namespace != "innsmouth" {
+			t.Errorf("expected 42 fish caught by the DeepOne obed from %s, got %+v", path, item)
+		}
+	}
+
+	for path, code := range map[string]int{
+		"/apis/custom.metrics.k8s.io/v1beta1/namespaces/innsmouth/deepones.innsmouth.k8s.io/zadok/fish":               http.StatusNotFound,
+		"/apis/custom.metrics.k8s.io/v1beta1/namespaces/innsmouth/deepones.innsmouth.k8s.io/obed/eyes":                http.StatusNotFound,
+		"/apis/custom.metrics.k8s.io/v1beta1/deepones.innsmouth.k8s.io/obed/fish":                                     http.StatusNotFound,
+		"/apis/custom.metrics.k8s.io/v1beta1/namespaces/innsmouth/deepones.innsmouth.k8s.io/*/fish?labelSelector=%3D": http.StatusBadRequest,
+	} {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+		if resp.Code != code {
+			t.Errorf("expected %s to fail with %d, got %d: %s", path, code, resp.Code, resp.Body)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"testing"
+
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+)
+
+// TestDefaultOnRead checks Universities stored before the introduction of the max_students field are read
+// with its default, the Universities having the "+resource:defaultOnRead" comment
+func TestDefaultOnRead(t *testing.T) {
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	universities := miskatonic.MiskatonicUniversityStorage.Build("miskatonic.k8s.io", getter)
+	// stored without max_students, bypassing the defaulting of the decoding of the created objects
+	if err := getter.storage.store("/miskatonic.k8s.io/universities/arkham/miskatonic", &miskatonic.University{
+		ObjectMeta: metav1.ObjectMeta{Name: "miskatonic", Namespace: "arkham"},
+		Spec:       miskatonic.UniversitySpec{FacultySize: 15},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := request.WithNamespace(context.Background(), "arkham")
+	obj, err := universities.Get(ctx, "miskatonic", &metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxStudents := obj.(*miskatonic.University).Spec.MaxStudents; maxStudents == nil || *maxStudents != 15 {
+		t.Errorf("expected the read University to have the default max_students 15, got %v", maxStudents)
+	}
+
+	list, err := universities.List(ctx, &metainternalversion.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := list.(*miskatonic.UniversityList).Items
+	if len(items) != 1 {
+		t.Fatalf("expected 1 listed University, got %d", len(items))
+	}
+	if maxStudents := items[0].Spec.MaxStudents; maxStudents == nil || *maxStudents != 15 {
+		t.Errorf("expected the listed University to have the default max_students 15, got %v", maxStudents)
+	}
+	if stored := getter.storage.objects["/miskatonic.k8s.io/universities/arkham/miskatonic"]; stored.(*miskatonic.University).Spec.MaxStudents != nil {
+		t.Errorf("expected the stored University to be left without max_students")
+	}
+}
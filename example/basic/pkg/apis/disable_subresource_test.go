@@ -0,0 +1,79 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/apiserver"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/cmd/server"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+)
+
+// TestDisableSubresource checks the apiserver does not serve the subresources of --disable-subresource while
+// still serving their resource
+func TestDisableSubresource(t *testing.T) {
+	builders.APIGroupBuilders = apis.GetAllApiBuilders()
+	if err := server.DisableSubresources(builders.APIGroupBuilders, []string{"poseidons/frobs"}); err == nil {
+		t.Errorf("expected an error disabling a subresource Poseidons do not have")
+	}
+	if err := server.DisableSubresources(builders.APIGroupBuilders, []string{"poseidons/status"}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		for _, g := range builders.APIGroupBuilders {
+			g.DisabledSubresources = nil
+		}
+	}()
+
+	config := (&apiserver.Config{RecommendedConfig: genericapiserver.NewRecommendedConfig(builders.Codecs)}).Init()
+	config.RecommendedConfig.LoopbackClientConfig = &rest.Config{}
+	config.RecommendedConfig.ExternalAddress = "localhost:443"
+	config.RecommendedConfig.RESTOptionsGetter = memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	s, err := config.Complete().New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, "/apis/olympus.k8s.io/v1beta1/namespaces/default/"+path,
+			bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		s.GenericAPIServer.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	poseidon := []byte(`{"apiVersion":"olympus.k8s.io/v1beta1","kind":"Poseidon","metadata":{"name":"poseidon"}}`)
+	if w := serve(http.MethodPost, "poseidons", poseidon); w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201 creating a Poseidon, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodGet, "poseidons/poseidon", nil); w.Code != http.StatusOK {
+		t.Errorf("expected status 200 getting the Poseidon, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodPut, "poseidons/poseidon/status", poseidon); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 updating the disabled status, got %d: %s", w.Code, w.Body.String())
+	}
+}
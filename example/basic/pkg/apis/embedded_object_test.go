@@ -0,0 +1,76 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+	innsmouthv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1"
+)
+
+// TestEmbeddedObjectConversion checks the Cultist embedded in the +embeddedObject Relic field of v1 DeepOnes
+// is decoded converting to the internal version and encoded back converting to v1
+func TestEmbeddedObjectConversion(t *testing.T) {
+	deepOne := &innsmouthv1.DeepOne{
+		ObjectMeta: metav1.ObjectMeta{Name: "deepone"},
+		Spec: innsmouthv1.DeepOneSpec{
+			Relic: runtime.RawExtension{
+				Raw: []byte(`{"apiVersion":"innsmouth.k8s.io/v1","kind":"Cultist","metadata":{"name":"zadok"},"spec":{"rank":"elder"}}`),
+			},
+		},
+	}
+	internal := &innsmouth.DeepOne{}
+	if err := builders.Scheme.Convert(deepOne, internal, nil); err != nil {
+		t.Fatal(err)
+	}
+	cultist, ok := internal.Spec.Relic.Object.(*innsmouthv1.Cultist)
+	if !ok {
+		t.Fatalf("expected the internal relic to hold a v1 Cultist, got %T", internal.Spec.Relic.Object)
+	}
+	if cultist.Name != "zadok" || cultist.Spec.Rank != "elder" {
+		t.Errorf("expected the elder Cultist zadok, got %+v", cultist)
+	}
+
+	cultist.Spec.Rank = "priest"
+	converted := &innsmouthv1.DeepOne{}
+	if err := builders.Scheme.Convert(internal, converted, nil); err != nil {
+		t.Fatal(err)
+	}
+	if converted.Spec.Relic.Object != nil {
+		t.Errorf("expected the v1 relic to only hold its encoded object, got %T", converted.Spec.Relic.Object)
+	}
+	roundTripped := &innsmouth.DeepOne{}
+	if err := builders.Scheme.Convert(converted, roundTripped, nil); err != nil {
+		t.Fatal(err)
+	}
+	cultist, ok = roundTripped.Spec.Relic.Object.(*innsmouthv1.Cultist)
+	if !ok || cultist.Name != "zadok" || cultist.Spec.Rank != "priest" {
+		t.Errorf("expected the relic to survive the round trip as the priest Cultist zadok, got %+v", roundTripped.Spec.Relic.Object)
+	}
+
+	deepOne.Spec.Relic.Raw = []byte(`{"apiVersion":"innsmouth.k8s.io/v1","kind":"Elder"}`)
+	err := builders.Scheme.Convert(deepOne, &innsmouth.DeepOne{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "spec.relic: decoding the embedded object") {
+		t.Errorf("expected a relic of an unknown kind to not convert, got %v", err)
+	}
+}
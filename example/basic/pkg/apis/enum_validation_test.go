@@ -0,0 +1,92 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/olympus"
+	olympusv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/olympus/v1beta1"
+)
+
+// TestEnumValidation checks the +enum PoseidonSea field of v1beta1 Poseidons converts to and from the string
+// of the internal version, and that the created and updated Poseidons must hold one of the constants of the
+// type while the objects holding another value can still be converted when read
+func TestEnumValidation(t *testing.T) {
+	poseidon := &olympusv1beta1.Poseidon{
+		ObjectMeta: metav1.ObjectMeta{Name: "poseidon"},
+		Spec:       olympusv1beta1.PoseidonSpec{Sea: olympusv1beta1.PoseidonSeaStorm},
+	}
+	internal := &olympus.Poseidon{}
+	if err := builders.Scheme.Convert(poseidon, internal, nil); err != nil {
+		t.Fatal(err)
+	}
+	if internal.Spec.Sea != "Storm" {
+		t.Errorf("expected the internal sea to be Storm, got %q", internal.Spec.Sea)
+	}
+
+	internal.Spec.Sea = "Calm"
+	if err := builders.Scheme.Convert(internal, poseidon, nil); err != nil {
+		t.Fatal(err)
+	}
+	if poseidon.Spec.Sea != olympusv1beta1.PoseidonSeaCalm {
+		t.Errorf("expected the v1beta1 sea to be Calm, got %q", poseidon.Spec.Sea)
+	}
+
+	// The objects stored with a sea that is no longer a constant of PoseidonSea are still read
+	internal.Spec.Sea = "Maelstrom"
+	if err := builders.Scheme.Convert(internal, poseidon, nil); err != nil {
+		t.Errorf("expected the internal Maelstrom sea to convert to v1beta1, got %v", err)
+	}
+	if err := builders.Scheme.Convert(poseidon, internal, nil); err != nil {
+		t.Errorf("expected the v1beta1 Maelstrom sea to convert to the internal version, got %v", err)
+	}
+
+	poseidons := olympus.OlympusPoseidonStorage.Build("olympus.k8s.io",
+		memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}})
+	ctx := request.WithNamespace(context.Background(), "olympus")
+	_, err := poseidons.Create(ctx, &olympus.Poseidon{
+		ObjectMeta: metav1.ObjectMeta{Name: "poseidon", Namespace: "olympus"},
+		Spec:       olympus.PoseidonSpec{Sea: "Maelstrom"},
+	}, nil, &metav1.CreateOptions{})
+	if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), `spec.sea: Unsupported value: "Maelstrom"`) {
+		t.Errorf("expected the Poseidon with a Maelstrom sea to be invalid, got %v", err)
+	}
+	created, err := poseidons.Create(ctx, &olympus.Poseidon{
+		ObjectMeta: metav1.ObjectMeta{Name: "poseidon", Namespace: "olympus"},
+		Spec:       olympus.PoseidonSpec{Sea: "Storm"},
+	}, nil, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := created.(*olympus.Poseidon).DeepCopy()
+	update.Spec.Sea = "Maelstrom"
+	_, _, err = poseidons.Update(ctx, update.Name, rest.DefaultUpdatedObjectInfo(update),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), `spec.sea: Unsupported value: "Maelstrom"`) {
+		t.Errorf("expected the update of the sea to Maelstrom to be invalid, got %v", err)
+	}
+}
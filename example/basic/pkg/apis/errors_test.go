@@ -0,0 +1,66 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+)
+
+// TestErrors checks the generated errors of universities carry the group and resource of universities, as the
+// errors of their storage do
+func TestErrors(t *testing.T) {
+	notFound := miskatonic.NewUniversityNotFound("miskatonic")
+	if !apierrors.IsNotFound(notFound) {
+		t.Errorf("expected a NotFound error, got %v", notFound)
+	}
+	if details := notFound.Status().Details; details.Group != "miskatonic.k8s.io" || details.Kind != "universities" ||
+		details.Name != "miskatonic" {
+		t.Errorf("expected the details of the miskatonic university, got %+v", details)
+	}
+
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	universities := miskatonic.MiskatonicUniversityStorage.Build("miskatonic.k8s.io", getter).(rest.Getter)
+	_, err := universities.Get(request.WithNamespace(context.Background(), "arkham"), "miskatonic",
+		&metav1.GetOptions{})
+	if status, ok := err.(apierrors.APIStatus); !ok || !reflect.DeepEqual(status.Status(), notFound.Status()) {
+		t.Errorf("expected the storage to return\n%v\ngot\n%v", notFound, err)
+	}
+
+	if err := miskatonic.NewUniversityAlreadyExists("miskatonic"); !apierrors.IsAlreadyExists(err) ||
+		err.Status().Details.Group != "miskatonic.k8s.io" || err.Status().Details.Kind != "universities" {
+		t.Errorf("expected an AlreadyExists error of universities, got %+v", err.Status())
+	}
+	if err := miskatonic.NewUniversityConflict("miskatonic", errors.New("stale")); !apierrors.IsConflict(err) ||
+		err.Status().Details.Kind != "universities" {
+		t.Errorf("expected a Conflict error of universities, got %+v", err.Status())
+	}
+	if err := miskatonic.NewUniversityInvalid("miskatonic", nil); !apierrors.IsInvalid(err) ||
+		err.Status().Details.Group != "miskatonic.k8s.io" || err.Status().Details.Kind != "University" {
+		t.Errorf("expected an Invalid error of universities, got %+v", err.Status())
+	}
+}
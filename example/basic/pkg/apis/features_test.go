@@ -0,0 +1,74 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/apiserver"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+)
+
+// TestFeaturesHandler checks the apiserver lists the states of the feature gates of the miskatonic resources
+func TestFeaturesHandler(t *testing.T) {
+	builders.APIGroupBuilders = apis.GetAllApiBuilders()
+	config := (&apiserver.Config{RecommendedConfig: genericapiserver.NewRecommendedConfig(builders.Codecs)}).Init()
+	config.RecommendedConfig.LoopbackClientConfig = &rest.Config{}
+	config.RecommendedConfig.ExternalAddress = "localhost:443"
+	config.RecommendedConfig.RESTOptionsGetter = noopRESTOptionsGetter{}
+	server, err := config.Complete().New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	features := func() map[string]bool {
+		w := httptest.NewRecorder()
+		server.GenericAPIServer.Handler.GoRestfulContainer.ServeHTTP(w,
+			httptest.NewRequest(http.MethodGet, "/apis/miskatonic.k8s.io/v1beta1/features", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		states := map[string]bool{}
+		if err := json.Unmarshal(w.Body.Bytes(), &states); err != nil {
+			t.Fatal(err)
+		}
+		return states
+	}
+
+	expected := map[string]bool{"MiskatonicStudents": true, "MiskatonicUniversities": true}
+	if states := features(); !reflect.DeepEqual(states, expected) {
+		t.Errorf("expected the features %v, got %v", expected, states)
+	}
+
+	if err := utilfeature.DefaultMutableFeatureGate.Set("MiskatonicStudents=false"); err != nil {
+		t.Fatal(err)
+	}
+	defer utilfeature.DefaultMutableFeatureGate.Set("MiskatonicStudents=true")
+	expected["MiskatonicStudents"] = false
+	if states := features(); !reflect.DeepEqual(states, expected) {
+		t.Errorf("expected the features %v, got %v", expected, states)
+	}
+}
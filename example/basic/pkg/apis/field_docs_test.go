@@ -0,0 +1,50 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// TestFieldDocs checks UniversityFieldDocs documents the fields of the nested structs of University by their
+// dotted json path, the fields of the embedded CommonStatus and metav1.TypeMeta being fields of the
+// embedding structs
+func TestFieldDocs(t *testing.T) {
+	expected := map[string]string{
+		"spec.faculty_size":      "faculty_size defines the desired faculty size of the university.  Defaults to 15.",
+		"spec.departments.chair": "chair is the name of the professor heading the department",
+		"spec.annexes.manual.C": "C has no peer in the unversioned struct, so it is dropped by the conversion unless " +
+			"the conversion webhook moves it into a field that is converted",
+		"status.conditions.type": "type of the condition - e.g. Ready",
+		"spec.annexes.name":      "",
+	}
+	for path, description := range expected {
+		if doc, found := miskatonicv1beta1.UniversityFieldDocs[path]; !found || doc != description {
+			t.Errorf("expected %s to be documented as %q, got %q", path, description, doc)
+		}
+	}
+	if _, found := miskatonicv1beta1.UniversityFieldDocs["kind"]; !found {
+		t.Errorf("expected the kind of the embedded TypeMeta to be documented")
+	}
+	for _, path := range []string{"status.CommonStatus", "metadata.name", "spec.selector.matchLabels"} {
+		if _, found := miskatonicv1beta1.UniversityFieldDocs[path]; found {
+			t.Errorf("expected %s not to be documented", path)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// TestFieldProjection checks a GET of a university projected to its name and faculty size only returns these
+// fields along with its apiVersion and kind, and that the items of a list of universities are projected
+func TestFieldProjection(t *testing.T) {
+	maxStudents := 150
+	university := miskatonicv1beta1.University{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "miskatonic.k8s.io/v1beta1", Kind: "University"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "arkham", Name: "miskatonic", Labels: map[string]string{"city": "arkham"}},
+		Spec:       miskatonicv1beta1.UniversitySpec{FacultySize: 15, MaxStudents: &maxStudents, Tier: "ivy"},
+		Status:     miskatonicv1beta1.UniversityStatus{EnrolledStudents: []string{"randolph"}},
+	}
+	list := miskatonicv1beta1.UniversityList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "miskatonic.k8s.io/v1beta1", Kind: "UniversityList"},
+		ListMeta: metav1.ListMeta{ResourceVersion: "7"},
+		Items:    []miskatonicv1beta1.University{university},
+	}
+	handler := builders.WithFieldProjection(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, found := req.URL.Query()[builders.FieldProjectionParam]; found {
+			t.Errorf("expected the fields parameter not to be forwarded, got %s", req.URL)
+		}
+		var obj interface{} = university
+		if req.URL.Path == "/universities" {
+			obj = list
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(obj)
+	}))
+
+	expected := map[string]interface{}{
+		"apiVersion": "miskatonic.k8s.io/v1beta1",
+		"kind":       "University",
+		"metadata":   map[string]interface{}{"name": "miskatonic"},
+		"spec":       map[string]interface{}{"faculty_size": float64(15)},
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/universities/miskatonic?fields=metadata.name,spec.faculty_size", nil))
+	projected := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &projected); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(projected, expected) {
+		t.Errorf("expected the projected university %v, got %v", expected, projected)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/universities?fields=metadata.name,spec.faculty_size", nil))
+	projectedList := struct {
+		Kind     string                   `json:"kind"`
+		Metadata metav1.ListMeta          `json:"metadata"`
+		Items    []map[string]interface{} `json:"items"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &projectedList); err != nil {
+		t.Fatal(err)
+	}
+	if projectedList.Kind != "UniversityList" || projectedList.Metadata.ResourceVersion != "7" {
+		t.Errorf("expected the list to keep its kind and metadata, got %s", w.Body)
+	}
+	if len(projectedList.Items) != 1 || !reflect.DeepEqual(projectedList.Items[0], expected) {
+		t.Errorf("expected the items of the list to be projected to %v, got %v", expected, projectedList.Items)
+	}
+
+	// Requests without the parameter are served unchanged
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/universities/miskatonic", nil))
+	unchanged := miskatonicv1beta1.University{}
+	if err := json.Unmarshal(w.Body.Bytes(), &unchanged); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unchanged, university) {
+		t.Errorf("expected the university %v to be unchanged, got %v", university, unchanged)
+	}
+}
@@ -0,0 +1,106 @@
+//go:build !experimental
+// +build !experimental
+
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	flowcontrolv1alpha1 "k8s.io/api/flowcontrol/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+)
+
+// TestFlowSchemas checks the manifest written by apiregister-gen --flow-schema-manifest holds a FlowSchema of
+// each group whose resource rules match the resources the group serves, classifying the requests into a
+// PriorityLevelConfiguration of the manifest.  The resources with a build tag are not classified, the test is
+// left out of the builds serving them.
+func TestFlowSchemas(t *testing.T) {
+	file, err := os.Open(filepath.Join("..", "..", "testdata", "flowschemas.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	flowSchemas := map[string]*flowcontrolv1alpha1.FlowSchema{}
+	priorityLevels := map[string]*flowcontrolv1alpha1.PriorityLevelConfiguration{}
+	decoder := yaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		obj := map[string]interface{}{}
+		if err := decoder.Decode(&obj); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		switch obj["kind"] {
+		case "FlowSchema":
+			flowSchema := &flowcontrolv1alpha1.FlowSchema{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, flowSchema); err != nil {
+				t.Fatal(err)
+			}
+			flowSchemas[flowSchema.Name] = flowSchema
+		case "PriorityLevelConfiguration":
+			priorityLevel := &flowcontrolv1alpha1.PriorityLevelConfiguration{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, priorityLevel); err != nil {
+				t.Fatal(err)
+			}
+			priorityLevels[priorityLevel.Name] = priorityLevel
+		default:
+			t.Errorf("unexpected %v in the flow schema manifest", obj["kind"])
+		}
+	}
+
+	for _, group := range apis.GetAllApiBuilders() {
+		resources := sets.NewString()
+		for _, storage := range group.Build(noopRESTOptionsGetter{}).VersionedResourcesStorageMap {
+			resources.Insert(sets.StringKeySet(storage).List()...)
+		}
+
+		flowSchema, found := flowSchemas[group.Name]
+		if !found {
+			t.Errorf("expected a FlowSchema of %s", group.Name)
+			continue
+		}
+		priorityLevel, found := priorityLevels[flowSchema.Spec.PriorityLevelConfiguration.Name]
+		if !found {
+			t.Errorf("expected the PriorityLevelConfiguration %s of the FlowSchema of %s",
+				flowSchema.Spec.PriorityLevelConfiguration.Name, group.Name)
+		} else if shares := priorityLevel.Spec.Limited.AssuredConcurrencyShares; shares != 20 {
+			t.Errorf("expected the priority level of %s to be assured 20 concurrency shares, got %d", group.Name, shares)
+		}
+		if len(flowSchema.Spec.Rules) != 1 || len(flowSchema.Spec.Rules[0].ResourceRules) != 1 {
+			t.Errorf("expected the FlowSchema of %s to have a resource rule, got %v", group.Name, flowSchema.Spec.Rules)
+			continue
+		}
+		rule := flowSchema.Spec.Rules[0].ResourceRules[0]
+		if !reflect.DeepEqual(rule.APIGroups, []string{group.Name}) {
+			t.Errorf("expected the FlowSchema of %s to match the group, got %v", group.Name, rule.APIGroups)
+		}
+		if !reflect.DeepEqual(rule.Resources, resources.List()) {
+			t.Errorf("expected the FlowSchema of %s to match the resources %v, got %v", group.Name, resources.List(), rule.Resources)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+)
+
+// TestFuzzCorpus checks the seed corpus written by apiregister-gen --fuzz-corpus-dir holds a generated object of
+// each resource, and that the seeds decode without unknown fields into objects converting to valid internal
+// objects
+func TestFuzzCorpus(t *testing.T) {
+	for _, group := range apis.GetAllApiBuilders() {
+		for _, version := range group.Versions {
+			for _, kind := range version.Kinds {
+				if len(kind.Unversioned.GetPath()) > 0 {
+					continue
+				}
+				dir := filepath.Join("..", "..", "testdata", "fuzz", strings.Split(group.Name, ".")[0],
+					version.GroupVersion.Version, kind.Unversioned.GetName())
+				seeds, err := filepath.Glob(filepath.Join(dir, "*.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(seeds) == 0 {
+					t.Errorf("expected the seeds of %s in %s", kind.Unversioned.GetKind(), dir)
+				}
+				for _, seed := range seeds {
+					checkFuzzSeed(t, seed, version.GroupVersion.WithKind(kind.Unversioned.GetKind()), kind.StorageBuilder)
+				}
+			}
+		}
+	}
+}
+
+func checkFuzzSeed(t *testing.T, seed string, gvk schema.GroupVersionKind, strategy builders.StorageBuilder) {
+	data, err := ioutil.ReadFile(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(data, &typeMeta); err != nil {
+		t.Fatalf("failed to decode %s: %v", seed, err)
+	}
+	if typeMeta.GroupVersionKind() != gvk {
+		t.Errorf("expected %s to be a %v, got %v", seed, gvk, typeMeta.GroupVersionKind())
+		return
+	}
+
+	versioned := apis.GVKToType[gvk]()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(versioned); err != nil {
+		t.Errorf("failed to decode %s: %v", seed, err)
+		return
+	}
+	internal, err := builders.Scheme.ConvertToVersion(versioned, runtime.InternalGroupVersioner)
+	if err != nil {
+		t.Errorf("failed to convert %s: %v", seed, err)
+		return
+	}
+	if strategy == nil {
+		return
+	}
+	// The store sets the namespace of the request on the objects created in a namespace
+	if accessor, err := meta.Accessor(internal); err == nil && strategy.NamespaceScoped() && len(accessor.GetNamespace()) == 0 {
+		accessor.SetNamespace(metav1.NamespaceDefault)
+	}
+	if errs := strategy.Validate(context.TODO(), internal); len(errs) > 0 {
+		t.Errorf("expected %s to be valid: %v", seed, errs.ToAggregate())
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport"
+)
+
+// TestGenerateNameRequired checks the strategy of Pilgrims, which have a +resource:generateNameRequired comment,
+// generates the names of the pilgrims created with a generateName and rejects the others
+func TestGenerateNameRequired(t *testing.T) {
+	strategy := kingsport.KingsportPilgrimStorage.StorageBuilder
+	ctx := request.WithNamespace(context.TODO(), "kingsport")
+
+	for _, name := range []string{"", "randolph"} {
+		pilgrim := &kingsport.Pilgrim{ObjectMeta: metav1.ObjectMeta{
+			Namespace:    "kingsport",
+			Name:         name,
+			GenerateName: "pilgrim-",
+		}}
+		if err := rest.BeforeCreate(strategy, ctx, pilgrim); err != nil {
+			t.Errorf("expected the pilgrim with the generateName pilgrim- and name %q to be created, got %v", name, err)
+		}
+		if !strings.HasPrefix(pilgrim.Name, "pilgrim-") || pilgrim.Name == "pilgrim-" {
+			t.Errorf("expected the name of the pilgrim to be generated from pilgrim-, got %q", pilgrim.Name)
+		}
+	}
+
+	for _, name := range []string{"", "randolph"} {
+		pilgrim := &kingsport.Pilgrim{ObjectMeta: metav1.ObjectMeta{Namespace: "kingsport", Name: name}}
+		err := rest.BeforeCreate(strategy, ctx, pilgrim)
+		if !errors.IsInvalid(err) || !strings.Contains(err.Error(), "metadata.generateName: Required value") {
+			t.Errorf("expected the pilgrim with the name %q and no generateName to be rejected, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+)
+
+// TestGVKToType checks GVKToType constructs the versioned and internal objects of each resource and of its list,
+// of the types the scheme registers for their kinds
+func TestGVKToType(t *testing.T) {
+	checked := map[schema.GroupVersionKind]bool{}
+	check := func(gvk schema.GroupVersionKind) {
+		checked[gvk] = true
+		obj, err := builders.Scheme.New(gvk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		newFunc, found := apis.GVKToType[gvk]
+		if !found {
+			t.Errorf("expected a constructor of %v", gvk)
+			return
+		}
+		if constructed := reflect.TypeOf(newFunc()); constructed != reflect.TypeOf(obj) {
+			t.Errorf("expected the constructor of %v to return a %v, got a %v", gvk, reflect.TypeOf(obj), constructed)
+		}
+	}
+
+	for _, group := range apis.GetAllApiBuilders() {
+		for _, version := range group.Versions {
+			for _, kind := range version.Kinds {
+				// Subresources share the kind of their resource or have request kinds of their own
+				if len(kind.Unversioned.GetPath()) > 0 {
+					continue
+				}
+				// The kinds of the internal objects of the resource and of its list
+				for _, obj := range []runtime.Object{kind.NewFunc(), kind.NewListFunc()} {
+					gvks, _, err := builders.Scheme.ObjectKinds(obj)
+					if err != nil {
+						t.Fatal(err)
+					}
+					check(gvks[0])
+					check(version.GroupVersion.WithKind(gvks[0].Kind))
+				}
+			}
+		}
+	}
+	if len(apis.GVKToType) != len(checked) {
+		t.Errorf("expected constructors of the %d kinds of the resources and of their lists, got %d",
+			len(checked), len(apis.GVKToType))
+	}
+}
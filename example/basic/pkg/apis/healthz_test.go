@@ -0,0 +1,71 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/apiserver"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+)
+
+// TestHealthzCheck checks /healthz of the apiserver fails while a custom check added with
+// builders.AddHealthzCheck fails
+func TestHealthzCheck(t *testing.T) {
+	var healthy error
+	builders.AddHealthzCheck(healthz.NamedCheck("elder-sign", func(*http.Request) error {
+		return healthy
+	}))
+	defer func() {
+		builders.HealthzChecks = builders.HealthzChecks[:len(builders.HealthzChecks)-1]
+	}()
+
+	recommendedConfig := genericapiserver.NewRecommendedConfig(builders.Codecs)
+	recommendedConfig.ExternalAddress = "localhost:443"
+	recommendedConfig.LoopbackClientConfig = &rest.Config{}
+	recommendedConfig.RESTOptionsGetter = noopRESTOptionsGetter{}
+	config := &apiserver.Config{RecommendedConfig: recommendedConfig}
+	server, err := config.Complete().New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := server.GenericAPIServer.PrepareRun().Handler
+
+	for _, check := range []struct {
+		err    error
+		status int
+	}{
+		{nil, http.StatusOK},
+		{fmt.Errorf("the elder sign is broken"), http.StatusInternalServerError},
+	} {
+		healthy = check.err
+		for _, path := range []string{"/healthz", "/healthz/elder-sign"} {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+			if w.Code != check.status {
+				t.Errorf("expected %s to return %d while the check returns %v, got %d: %s",
+					path, check.status, check.err, w.Code, w.Body)
+			}
+		}
+	}
+}
@@ -20,5 +20,8 @@ limitations under the License.
 // +k8s:deepcopy-gen=package,register
 // +groupName=innsmouth.k8s.io
 
+// Discovery lists innsmouth before the groups of lower priorities, e.g. the CRD groups defaulting to 1000
+// +discovery:groupPriorityMinimum=1500
+
 // Package api is the internal version of the API.
 package innsmouth
@@ -0,0 +1,59 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+	innsmouthv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1"
+	innsmouthv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1beta1"
+)
+
+// TestConversionPairs checks the conversion matrix test generated with --emit-tests enumerates the six ordered
+// pairs of the three versions of Cultist, and the two of the versions of Shoggoth
+func TestConversionPairs(t *testing.T) {
+	expected := sets.NewString(
+		"Cultist/v1->v1beta1", "Cultist/v1->v1alpha1",
+		"Cultist/v1beta1->v1", "Cultist/v1beta1->v1alpha1",
+		"Cultist/v1alpha1->v1", "Cultist/v1alpha1->v1beta1",
+		"Shoggoth/v1beta1->v1alpha1", "Shoggoth/v1alpha1->v1beta1")
+	pairs := sets.NewString()
+	for _, pair := range conversionPairs {
+		pairs.Insert(pair.String())
+	}
+	if !pairs.Equal(expected) || len(conversionPairs) != expected.Len() {
+		t.Errorf("expected the conversion pairs %v, got %v", expected.List(), conversionPairs)
+	}
+}
+
+// TestConversionPathMissing checks a pair of versions is reported when the scheme knows the types of the versions
+// but not their conversions, e.g. when the conversions of a new version are forgotten
+func TestConversionPathMissing(t *testing.T) {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypes(innsmouthv1.SchemeGroupVersion, &innsmouthv1.Cultist{})
+	scheme.AddKnownTypes(innsmouthv1beta1.SchemeGroupVersion, &innsmouthv1beta1.Cultist{})
+	scheme.AddKnownTypes(innsmouth.SchemeGroupVersion, &innsmouth.Cultist{})
+	pair := builders.ConversionPair{Group: "innsmouth.k8s.io", Kind: "Cultist", From: "v1", To: "v1beta1"}
+	if err := builders.CheckConversionPath(scheme, pair); err == nil {
+		t.Errorf("expected no conversion path of %s without the conversions of the versions", pair)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package innsmouth
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+var _ rest.Getter = &DeepOneScaleREST{}
+var _ rest.Updater = &DeepOneScaleREST{}
+
+// +k8s:deepcopy-gen=false
+type DeepOneScaleREST struct {
+	Registry DeepOneRegistry
+}
+
+// Get returns the scale of the DeepOne, its replicas being the fish required by the DeepOne.
+func (r *DeepOneScaleREST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	rec, err := r.Registry.GetDeepOne(ctx, name, options)
+	if err != nil {
+		return nil, err
+	}
+	return scaleOf(rec), nil
+}
+
+// Update sets the fish required by the DeepOne to the replicas of the updated scale.
+func (r *DeepOneScaleREST) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	rec, err := r.Registry.GetDeepOne(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	obj, err := objInfo.UpdatedObject(ctx, scaleOf(rec))
+	if err != nil {
+		return nil, false, err
+	}
+	rec.Spec.FishRequired = obj.(*DeepOneScale).Replicas
+	if rec, err = r.Registry.UpdateDeepOne(ctx, rec); err != nil {
+		return nil, false, err
+	}
+	return scaleOf(rec), false, nil
+}
+
+func (r *DeepOneScaleREST) New() runtime.Object {
+	return &DeepOneScale{}
+}
+
+func scaleOf(rec *DeepOne) *DeepOneScale {
+	return &DeepOneScale{
+		ObjectMeta: *rec.ObjectMeta.DeepCopy(),
+		Replicas:   rec.Spec.FishRequired,
+	}
+}
@@ -19,6 +19,7 @@ package v1
 import (
 	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // +genclient
@@ -26,6 +27,11 @@ import (
 
 // +k8s:openapi-gen=true
 // +resource:path=deepones
+// +resource:enableGarbageCollection=false
+// +resource:stampUser
+// +resource:nameRegex=^[a-z]+(-[0-9]+)?$
+// +subresource:request=DeepOneScale,path=scale,kind=DeepOneScale
+// +metric=name=fish,path=status.actual_fish
 // DeepOne defines a resident of innsmouth
 type DeepOne struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -40,13 +46,19 @@ type SamplePrimitiveAlias int64
 // DeepOnesSpec defines the desired state of DeepOne
 type DeepOneSpec struct {
 	// fish_required defines the number of fish required by the DeepOne.
+	// +json:allowDeviation
 	FishRequired int `json:"fish_required,omitempty"`
 
 	Sample               SampleElem                       `json:"sample,omitempty"`
+	// +json:allowDeviation
 	SamplePointer        *SamplePointerElem               `json:"sample_pointer,omitempty"`
+	// +json:allowDeviation
 	SampleList           []SampleListElem                 `json:"sample_list,omitempty"`
+	// +json:allowDeviation
 	SamplePointerList    []*SampleListPointerElem         `json:"sample_pointer_list,omitempty"`
+	// +json:allowDeviation
 	SampleMap            map[string]SampleMapElem         `json:"sample_map,omitempty"`
+	// +json:allowDeviation
 	SamplePointerMap     map[string]*SampleMapPointerElem `json:"sample_pointer_map,omitempty"`
 	SamplePrimitiveAlias SamplePrimitiveAlias
 
@@ -56,6 +68,13 @@ type DeepOneSpec struct {
 	ConstSlice []common.CustomType          `json:"constSlice,omitempty"`
 	ConstMap   map[string]common.CustomType `json:"constMap,omitempty"`
 
+	// Offering is an arbitrary object offered by the DeepOne
+	Offering runtime.RawExtension `json:"offering,omitempty"`
+
+	// Relic is an object of a kind known to the scheme, e.g. a Cultist, held decoded by the internal version
+	// +embeddedObject
+	Relic runtime.RawExtension `json:"relic,omitempty"`
+
 	// TODO: Fix issues with deep copy to make these work
 	//ConstSlicePtr []*common.CustomType          `json:"constSlicePtr,omitempty"`
 	//ConstMapPtr map[string]*common.CustomType `json:"constMapPtr,omitempty"`
@@ -112,5 +131,6 @@ type SampleSubElem struct {
 // DeepOneStatus defines the observed state of DeepOne
 type DeepOneStatus struct {
 	// actual_fish defines the number of fish caught by the DeepOne.
+	// +json:allowDeviation
 	ActualFish int `json:"actual_fish,omitempty"`
 }
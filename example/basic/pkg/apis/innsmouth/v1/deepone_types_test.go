@@ -21,9 +21,13 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
 	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1"
 	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/client/clientset_generated/clientset/typed/innsmouth/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
 )
 
 var _ = Describe("Deepone", func() {
@@ -122,4 +126,31 @@ var _ = Describe("Deepone", func() {
 			})
 		})
 	})
+
+	Describe("when deleting", func() {
+		It("should orphan the dependents as set by the +resource:enableGarbageCollection=false comment", func() {
+			deepones := innsmouth.Resource("deepones")
+			Expect(innsmouth.DefaultGCPolicy).To(HaveKeyWithValue(deepones, metav1.DeletePropagationOrphan))
+
+			strategy := innsmouth.InnsmouthDeepOneStorage.StorageBuilder.(rest.GarbageCollectionDeleteStrategy)
+			Expect(strategy.DefaultGarbageCollectionPolicy(context.TODO())).To(Equal(rest.OrphanDependents))
+		})
+	})
+
+	Describe("when converting", func() {
+		It("should deep copy the offering RawExtension", func() {
+			offering := &DeepOne{ObjectMeta: metav1.ObjectMeta{Name: "dagon"}}
+			instance.Spec.Offering = runtime.RawExtension{Raw: []byte(`{"fish":1}`), Object: offering}
+
+			actual := &innsmouth.DeepOne{}
+			Expect(builders.Scheme.Convert(&instance, actual, nil)).To(Succeed())
+			Expect(actual.Spec.Offering.Raw).To(Equal([]byte(`{"fish":1}`)))
+			Expect(actual.Spec.Offering.Object).To(Equal(offering))
+
+			instance.Spec.Offering.Raw[9] = '2'
+			offering.Name = "hydra"
+			Expect(actual.Spec.Offering.Raw).To(Equal([]byte(`{"fish":1}`)))
+			Expect(actual.Spec.Offering.Object.(*DeepOne).Name).To(Equal("dagon"))
+		})
+	})
 })
@@ -26,6 +26,7 @@ limitations under the License.
 // +k8s:deepcopy-gen=package,register
 // +k8s:conversion-gen=sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth
 // +k8s:defaulter-gen=TypeMeta
+// +discovery:versionPriority=15
 
 // +groupName=innsmouth.k8s.io
 package v1 // import "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1"
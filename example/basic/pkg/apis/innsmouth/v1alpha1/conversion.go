@@ -0,0 +1,35 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/conversion"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+)
+
+// Convert_v1alpha1_Den_To_innsmouth_Lair converts the Dens of the lairs of Shoggoths, which are not sunken
+func Convert_v1alpha1_Den_To_innsmouth_Lair(in *Den, out *innsmouth.Lair, s conversion.Scope) error {
+	*out = innsmouth.Lair{Depth: in.Depth}
+	return nil
+}
+
+// Convert_innsmouth_Lair_To_v1alpha1_Den converts the lairs of Shoggoths to Dens, dropping whether they are sunken
+func Convert_innsmouth_Lair_To_v1alpha1_Den(in *innsmouth.Lair, out *Den, s conversion.Scope) error {
+	*out = Den{Depth: in.Depth}
+	return nil
+}
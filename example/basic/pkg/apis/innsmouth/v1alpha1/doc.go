@@ -0,0 +1,31 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Api versions allow the api contract for a resource to be changed while keeping
+// backward compatibility by support multiple concurrent versions
+// of the same resource
+
+//go:generate deepcopy-gen -O zz_generated.deepcopy -i . -h ../../../../boilerplate.go.txt
+//go:generate defaulter-gen -O zz_generated.defaults -i . -h ../../../../boilerplate.go.txt
+//go:generate conversion-gen -O zz_generated.conversion -i . -h ../../../../boilerplate.go.txt
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=package,register
+// +k8s:conversion-gen=sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth
+// +k8s:defaulter-gen=TypeMeta
+
+// +groupName=innsmouth.k8s.io
+package v1alpha1 // import "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1alpha1"
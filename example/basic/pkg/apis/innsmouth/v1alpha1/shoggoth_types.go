@@ -0,0 +1,57 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// +resource:path=shoggoths
+// +retainConversion
+// Shoggoth defines a servant of the deep ones.  v1alpha1 no longer serves shoggoths, the type is retained to
+// read and migrate the shoggoths stored in v1alpha1.
+type Shoggoth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ShoggothSpec   `json:"spec,omitempty"`
+	Status ShoggothStatus `json:"status,omitempty"`
+}
+
+// ShoggothSpec defines the desired state of Shoggoth
+type ShoggothSpec struct {
+	// Eyes is the number of eyes the Shoggoth forms
+	Eyes int32 `json:"eyes,omitempty"`
+}
+
+// ShoggothStatus defines the observed state of Shoggoth
+type ShoggothStatus struct {
+	// Master is the name of the DeepOne the Shoggoth serves
+	Master string `json:"master,omitempty"`
+	// Lairs are the dens of the Shoggoth keyed by name
+	Lairs map[string]Den `json:"lairs,omitempty"`
+}
+
+// Den is a lair of a Shoggoth
+type Den struct {
+	// Depth is the depth of the den in fathoms
+	Depth int32 `json:"depth,omitempty"`
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// +resource:path=cultists
+// Cultist is a worshipper of the deep ones.  It is served by the three versions of the innsmouth group, so the
+// conversion matrix test checks the six ordered pairs of its versions.
+type Cultist struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CultistSpec   `json:"spec,omitempty"`
+	Status CultistStatus `json:"status,omitempty"`
+}
+
+// CultistSpec defines the desired state of Cultist
+type CultistSpec struct {
+	// Rank is the rank of the cultist in the order
+	Rank string `json:"rank,omitempty"`
+}
+
+// CultistStatus defines the observed state of Cultist
+type CultistStatus struct {
+	// Initiated is whether the cultist was initiated
+	Initiated bool `json:"initiated,omitempty"`
+}
@@ -0,0 +1,24 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// SetDefaults_Shoggoth defaults the eyes of a Shoggoth to 3, the v1alpha1 Shoggoths have no default
+func SetDefaults_Shoggoth(obj *Shoggoth) {
+	if obj.Spec.Eyes == 0 {
+		obj.Spec.Eyes = 3
+	}
+}
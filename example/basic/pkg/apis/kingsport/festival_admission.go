@@ -0,0 +1,40 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kingsport
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apiserver/pkg/admission"
+)
+
+// MaxFestivalAttendees is the number of attendees the town hall of Kingsport holds
+const MaxFestivalAttendees = 1000
+
+// ValidateFestivalCreate rejects new festivals inviting more attendees than the town hall holds
+func ValidateFestivalCreate(ctx context.Context, obj *Festival, a admission.Attributes) error {
+	if a.GetOperation() != admission.Create {
+		return nil
+	}
+	attendees := int(obj.Spec.Invited) + len(obj.Spec.GuestList)
+	if attendees > MaxFestivalAttendees {
+		return fmt.Errorf("festival %s invites %d attendees, the town hall holds %d",
+			obj.Name, attendees, MaxFestivalAttendees)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright YEAR The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1"
+)
+
+var _ = Describe("Festival indexers", func() {
+	var informer cache.SharedIndexInformer
+	var stop chan struct{}
+
+	BeforeEach(func() {
+		festivals := &FestivalList{Items: []Festival{
+			{ObjectMeta: metav1.ObjectMeta{Name: "harvest"}, Spec: FestivalSpec{Year: 1925}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "midwinter"}, Spec: FestivalSpec{Year: 1925}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "yule"}, Spec: FestivalSpec{Year: 1926}},
+		}}
+		informer = cache.NewSharedIndexInformer(&cache.ListWatch{
+			ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+				return festivals, nil
+			},
+			WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		}, &Festival{}, 0, cache.Indexers{})
+		Expect(AddFestivalIndexers(informer)).ShouldNot(HaveOccurred())
+
+		stop = make(chan struct{})
+		go informer.Run(stop)
+		Expect(cache.WaitForCacheSync(stop, informer.HasSynced)).To(BeTrue())
+	})
+
+	AfterEach(func() {
+		close(stop)
+	})
+
+	It("should index Festivals by spec.year", func() {
+		keys, err := informer.GetIndexer().IndexKeys(FestivalSpecYearIndex, "1925")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(keys).To(ConsistOf("harvest", "midwinter"))
+	})
+
+	It("should list the Festivals with a spec.year", func() {
+		festivals, err := ListFestivalsBySpecYear(informer.GetIndexer(), "1926")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(festivals).To(HaveLen(1))
+		Expect(festivals[0].Name).To(Equal("yule"))
+
+		festivals, err = ListFestivalsBySpecYear(informer.GetIndexer(), "1927")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(festivals).To(BeEmpty())
+	})
+})
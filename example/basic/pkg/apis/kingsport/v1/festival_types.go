@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"encoding/json"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -27,6 +29,14 @@ import (
 // Festival
 // +k8s:openapi-gen=true
 // +resource:path=festivals,strategy=FestivalStrategy,shortname=fs
+// +index=spec.year
+// +resource:storageMediaType=application/json
+// +resource:customMarshal
+// +resource:oneOf=spec.invited,spec.guestList
+// +admission:validating=ValidateFestivalCreate
+// +removedField=spec.patron
+// +resource:utcTimes
+// +resource:rangeDefault=spec.capacity:10:5000
 type Festival struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -39,8 +49,66 @@ type Festival struct {
 type FestivalSpec struct {
 	// Year when the festival was held, may be negative (BC)
 	Year int `json:"year,omitempty"`
-	// Invited holds the number of invited attendees
+	// Invited holds the number of invited attendees, exclusive with guestList
 	Invited uint `json:"invited,omitempty"`
+	// GuestList holds the names of the invited attendees, exclusive with invited
+	GuestList []string `json:"guestList,omitempty"`
+	// Venue is where the festival is held
+	// +optional
+	Venue *FestivalVenue `json:"venue,omitempty"`
+	// Performers holds the acts of the festival, patches merge them by name
+	// +listType=map
+	// +listMapKey=name
+	// +patchStrategy=merge
+	// +patchMergeKey=name
+	// +optional
+	Performers []FestivalPerformer `json:"performers,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	// Capacity is the number of attendees the venue holds, clamped into 10 to 5000 on create and 10 if unset
+	// +optional
+	Capacity *int32 `json:"capacity,omitempty"`
+	// Patron sponsoring the festival, removed as festivals are no longer sponsored
+	// +optional
+	Patron string `json:"patron,omitempty"`
+}
+
+// FestivalPerformer is an act performing at a festival
+type FestivalPerformer struct {
+	// Name of the act, unique within the festival
+	Name string `json:"name"`
+	// Stage where the act performs
+	Stage string `json:"stage,omitempty"`
+	// Start is when the act starts performing, stored in UTC
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+}
+
+// FestivalVenue is a union of the hall or the open air location holding the festival.  A hall is
+// encoded as its name, e.g. "Town Hall", and an open air location as its coordinates.
+type FestivalVenue struct {
+	Hall    string
+	OpenAir *FestivalLocation
+}
+
+// FestivalLocation is the location of an open air festival
+type FestivalLocation struct {
+	Latitude  string `json:"latitude"`
+	Longitude string `json:"longitude"`
+}
+
+func (v FestivalVenue) MarshalJSON() ([]byte, error) {
+	if v.OpenAir != nil {
+		return json.Marshal(v.OpenAir)
+	}
+	return json.Marshal(v.Hall)
+}
+
+func (v *FestivalVenue) UnmarshalJSON(data []byte) error {
+	*v = FestivalVenue{}
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &v.Hall)
+	}
+	v.OpenAir = &FestivalLocation{}
+	return json.Unmarshal(data, v.OpenAir)
 }
 
 // FestivalStatus defines the observed state of Festival
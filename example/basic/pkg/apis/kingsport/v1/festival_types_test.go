@@ -18,13 +18,25 @@ package v1_test
 
 import (
 	"context"
+	"encoding/json"
+
+	"github.com/go-openapi/spec"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport"
 	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1"
 	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/client/clientset_generated/clientset/typed/kingsport/v1"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/openapi"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
 )
 
 var _ = Describe("Festival", func() {
@@ -36,6 +48,7 @@ var _ = Describe("Festival", func() {
 		instance = Festival{}
 		instance.Name = "instance-1"
 		instance.Spec.Year = 1
+		instance.Spec.Invited = 100
 		expected = instance
 	})
 
@@ -85,4 +98,125 @@ var _ = Describe("Festival", func() {
 			})
 		})
 	})
+
+	Describe("when storing in etcd", func() {
+		It("should use the media type of the +resource:storageMediaType comment", func() {
+			festivals := schema.GroupResource{Group: "kingsport.k8s.io", Resource: "festivals"}
+			Expect(kingsport.StorageMediaTypes).To(HaveKeyWithValue(festivals, "application/json"))
+			Expect(apis.GetKingsportAPIBuilder().StorageMediaTypes).To(HaveKeyWithValue(festivals, "application/json"))
+		})
+	})
+
+	Describe("when encoding a venue", func() {
+		It("should use the MarshalJSON and UnmarshalJSON methods of the +resource:customMarshal comment", func() {
+			info, _ := runtime.SerializerInfoForMediaType(builders.Codecs.SupportedMediaTypes(), runtime.ContentTypeJSON)
+			for venue, encoded := range map[FestivalVenue]string{
+				{Hall: "Town Hall"}: `"venue":"Town Hall"`,
+				{OpenAir: &FestivalLocation{Latitude: "42.6", Longitude: "-70.9"}}: `"venue":{"latitude":"42.6","longitude":"-70.9"}`,
+			} {
+				venue := venue
+				instance.Spec.Venue = &venue
+				data, err := runtime.Encode(info.Serializer, &instance)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(string(data)).To(ContainSubstring(encoded))
+
+				actual := &Festival{}
+				_, _, err = info.Serializer.Decode(data, nil, actual)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(actual.Spec.Venue).To(Equal(instance.Spec.Venue))
+			}
+		})
+	})
+
+	Describe("when validating", func() {
+		It("should require exactly one of the fields of the +resource:oneOf comment", func() {
+			strategy := kingsport.KingsportFestivalStorage.StorageBuilder
+			festival := &kingsport.Festival{}
+			festival.Name = "harvest"
+			festival.Spec.Year = 1925
+
+			By("accepting only spec.invited")
+			festival.Spec.Invited = 100
+			Expect(strategy.Validate(context.TODO(), festival)).To(BeEmpty())
+
+			By("accepting only spec.guestList")
+			festival.Spec.Invited = 0
+			festival.Spec.GuestList = []string{"Zadok Allen"}
+			Expect(strategy.Validate(context.TODO(), festival)).To(BeEmpty())
+
+			By("rejecting both")
+			festival.Spec.Invited = 100
+			errs := strategy.Validate(context.TODO(), festival)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+			Expect(errs[0].Field).To(Equal("spec.invited"))
+			Expect(errs[0].Detail).To(Equal("exactly one of spec.invited, spec.guestList must be set"))
+
+			By("rejecting neither")
+			festival.Spec.Invited = 0
+			festival.Spec.GuestList = nil
+			Expect(strategy.Validate(context.TODO(), festival)).To(HaveLen(1))
+			Expect(strategy.ValidateUpdate(context.TODO(), festival, festival)).To(HaveLen(1))
+		})
+	})
+
+	Describe("when admitting", func() {
+		It("should call the function of the +admission:validating comment on creates of festivals", func() {
+			plugin := apis.GetKingsportAPIBuilder().AdmissionPlugins["KingsportValidateFestivalCreate"]
+			Expect(plugin).ToNot(BeNil())
+			Expect(plugin.Handles(admission.Create)).To(BeTrue())
+			validator := plugin.(admission.ValidationInterface)
+
+			festivals := kingsport.Resource("festivals")
+			attributes := func(resource schema.GroupResource, obj runtime.Object) admission.Attributes {
+				return admission.NewAttributesRecord(obj, nil, kingsport.Kind("Festival").WithVersion(""), "",
+					"harvest", resource.WithVersion(""), "", admission.Create, &metav1.CreateOptions{}, false, nil)
+			}
+			festival := &kingsport.Festival{}
+			festival.Name = "harvest"
+			festival.Spec.Invited = kingsport.MaxFestivalAttendees
+
+			By("admitting festivals the town hall holds")
+			Expect(validator.Validate(context.TODO(), attributes(festivals, festival), nil)).To(Succeed())
+
+			By("rejecting festivals the town hall does not hold")
+			festival.Spec.Invited++
+			err := validator.Validate(context.TODO(), attributes(festivals, festival), nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invites 1001 attendees, the town hall holds 1000"))
+
+			By("ignoring other resources")
+			Expect(validator.Validate(context.TODO(), attributes(kingsport.Resource("parades"), festival), nil)).To(Succeed())
+		})
+	})
+
+	Describe("when patching performers", func() {
+		It("should merge the list of the +listType=map comment by its +listMapKey", func() {
+			By("merging strategic merge patches by name")
+			instance.Spec.Performers = []FestivalPerformer{
+				{Name: "Brown Jenkin", Stage: "north"},
+				{Name: "Keziah Mason", Stage: "south"},
+			}
+			original, err := json.Marshal(&instance)
+			Expect(err).ShouldNot(HaveOccurred())
+			patch := []byte(`{"spec":{"performers":[{"name":"Keziah Mason","stage":"east"}]}}`)
+			patched, err := strategicpatch.StrategicMergePatch(original, patch, &Festival{})
+			Expect(err).ShouldNot(HaveOccurred())
+			actual := &Festival{}
+			Expect(json.Unmarshal(patched, actual)).To(Succeed())
+			Expect(actual.Spec.Performers).To(Equal([]FestivalPerformer{
+				{Name: "Brown Jenkin", Stage: "north"},
+				{Name: "Keziah Mason", Stage: "east"},
+			}))
+
+			By("emitting the list extensions used by server-side apply")
+			ref := func(path string) spec.Ref { return spec.MustCreateRef("#/definitions/" + path) }
+			definition := openapi.GetOpenAPIDefinitions(ref)["sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1.FestivalSpec"]
+			extensions := definition.Schema.Properties["performers"].Extensions
+			Expect(extensions).To(HaveKeyWithValue("x-kubernetes-list-type", "map"))
+			Expect(extensions).To(HaveKeyWithValue("x-kubernetes-list-map-keys", []interface{}{"name"}))
+			Expect(extensions).To(HaveKeyWithValue("x-kubernetes-patch-strategy", "merge"))
+			Expect(extensions).To(HaveKeyWithValue("x-kubernetes-patch-merge-key", "name"))
+		})
+	})
 })
@@ -0,0 +1,48 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Harbor is the harbor of Kingsport.  A namespace has a single harbor, named kingsport.
+// +k8s:openapi-gen=true
+// +resource:path=harbors
+// +singleton=kingsport
+type Harbor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HarborSpec   `json:"spec,omitempty"`
+	Status HarborStatus `json:"status,omitempty"`
+}
+
+// HarborSpec defines the desired state of Harbor
+type HarborSpec struct {
+	// Berths is the number of ships the harbor holds at once
+	Berths int `json:"berths,omitempty"`
+}
+
+// HarborStatus defines the observed state of Harbor
+type HarborStatus struct {
+	// Moored is the number of ships moored in the harbor
+	Moored int `json:"moored,omitempty"`
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Pilgrim is a visitor of the festivals of Kingsport.  The names of pilgrims are generated by the apiserver from
+// their generateName.
+// +k8s:openapi-gen=true
+// +resource:path=pilgrims
+// +resource:generateNameRequired
+type Pilgrim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PilgrimSpec   `json:"spec,omitempty"`
+	Status PilgrimStatus `json:"status,omitempty"`
+}
+
+// PilgrimSpec defines the desired state of Pilgrim
+type PilgrimSpec struct {
+	// Festival is the name of the festival the pilgrim visits
+	Festival string `json:"festival,omitempty"`
+}
+
+// PilgrimStatus defines the observed state of Pilgrim
+type PilgrimStatus struct {
+	// Arrived is whether the pilgrim arrived at the festival
+	// +readOnly
+	Arrived bool `json:"arrived,omitempty"`
+}
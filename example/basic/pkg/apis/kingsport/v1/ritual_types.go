@@ -0,0 +1,48 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Ritual is experimental, it is only served by an apiserver built with the experimental build tag
+// +k8s:openapi-gen=true
+// +resource:path=rituals
+// +buildTag=experimental
+type Ritual struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RitualSpec   `json:"spec,omitempty"`
+	Status RitualStatus `json:"status,omitempty"`
+}
+
+// RitualSpec defines the desired state of Ritual
+type RitualSpec struct {
+	// Chant recited during the ritual
+	Chant string `json:"chant,omitempty"`
+}
+
+// RitualStatus defines the observed state of Ritual
+type RitualStatus struct {
+	// Performed is whether the ritual was performed
+	Performed bool `json:"performed,omitempty"`
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+)
+
+// TestListConversion checks converting a v1beta1 UniversityList converts each of its items with the
+// conversion of University, which migrates the dean annotation of its +annotationConversion comment
+func TestListConversion(t *testing.T) {
+	university := func(name, dean string) miskatonicv1beta1.University {
+		return miskatonicv1beta1.University{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{miskatonicv1beta1.DeanAnnotation: dean},
+		}}
+	}
+	list := &miskatonicv1beta1.UniversityList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "42"},
+		Items:    []miskatonicv1beta1.University{university("miskatonic", "armitage"), university("brown", "halsey")},
+	}
+
+	internal := &miskatonic.UniversityList{}
+	if err := builders.Scheme.Convert(list, internal, nil); err != nil {
+		t.Fatal(err)
+	}
+	if internal.ResourceVersion != "42" {
+		t.Errorf("expected the resource version of the list to be converted, got %q", internal.ResourceVersion)
+	}
+	if len(internal.Items) != len(list.Items) {
+		t.Fatalf("expected %d converted universities, got %d", len(list.Items), len(internal.Items))
+	}
+	for i, item := range internal.Items {
+		dean := list.Items[i].Annotations[miskatonicv1beta1.DeanAnnotation]
+		if item.Name != list.Items[i].Name || item.Annotations[miskatonicv1beta1.InternalDeanAnnotation] != dean {
+			t.Errorf("expected the university %s to be converted with the dean %s, got %v",
+				list.Items[i].Name, dean, item.ObjectMeta)
+		}
+	}
+
+	converted := &miskatonicv1beta1.UniversityList{}
+	if err := builders.Scheme.Convert(internal, converted, nil); err != nil {
+		t.Fatal(err)
+	}
+	for i, item := range converted.Items {
+		dean := list.Items[i].Annotations[miskatonicv1beta1.DeanAnnotation]
+		if item.Annotations[miskatonicv1beta1.DeanAnnotation] != dean {
+			t.Errorf("expected the university %s to be converted back with the dean %s, got %v",
+				item.Name, dean, item.ObjectMeta)
+		}
+	}
+}
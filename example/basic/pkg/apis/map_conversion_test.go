@@ -0,0 +1,79 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+	innsmouthv1alpha1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1alpha1"
+)
+
+// TestMapConversion checks the Dens of the lairs of v1alpha1 shoggoths, declared as Lairs by v1beta1, are
+// converted entry by entry through the generated conversions of the maps
+func TestMapConversion(t *testing.T) {
+	shoggoth := &innsmouthv1alpha1.Shoggoth{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "innsmouth", Name: "tekeli-li"},
+		Status: innsmouthv1alpha1.ShoggothStatus{Lairs: map[string]innsmouthv1alpha1.Den{
+			"reef":   {Depth: 300},
+			"tunnel": {Depth: 12},
+		}},
+	}
+	internal := &innsmouth.Shoggoth{}
+	if err := builders.Scheme.Convert(shoggoth, internal, nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]innsmouth.Lair{"reef": {Depth: 300}, "tunnel": {Depth: 12}}
+	if !apiequality.Semantic.DeepEqual(internal.Status.Lairs, expected) {
+		t.Errorf("unexpected internal lairs: %s", diff.ObjectReflectDiff(expected, internal.Status.Lairs))
+	}
+
+	internal.Status.Lairs["reef"] = innsmouth.Lair{Depth: 300, Sunken: true}
+	old := &innsmouthv1alpha1.Shoggoth{}
+	if err := builders.Scheme.Convert(internal, old, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !apiequality.Semantic.DeepEqual(old.Status.Lairs, shoggoth.Status.Lairs) {
+		t.Errorf("unexpected v1alpha1 dens: %s", diff.ObjectReflectDiff(shoggoth.Status.Lairs, old.Status.Lairs))
+	}
+
+	internal.Status.Lairs = nil
+	if err := builders.Scheme.Convert(internal, old, nil); err != nil {
+		t.Fatal(err)
+	}
+	if old.Status.Lairs != nil {
+		t.Errorf("expected the nil lairs to be converted to nil, got %v", old.Status.Lairs)
+	}
+
+	generated, err := ioutil.ReadFile("innsmouth/v1alpha1/zz_generated.api.register.conversion.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(generated), "func Convert_Map_string_To_v1alpha1_Den_To_Map_string_To_innsmouth_Lair(") {
+		t.Errorf("expected the conversion of the lairs to be generated, got:\n%s", generated)
+	}
+	if strings.Contains(string(generated), "TODO") {
+		t.Errorf("expected the conversions of the lairs to be generated, got a TODO:\n%s", generated)
+	}
+}
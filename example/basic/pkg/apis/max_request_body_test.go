@@ -0,0 +1,67 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/apiserver"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/cmd/server"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+)
+
+// TestMaxRequestBodyBytes checks the apiserver rejects the request bodies larger than --max-request-body-bytes
+func TestMaxRequestBodyBytes(t *testing.T) {
+	builders.APIGroupBuilders = apis.GetAllApiBuilders()
+	config := (&apiserver.Config{RecommendedConfig: genericapiserver.NewRecommendedConfig(builders.Codecs)}).Init()
+	config.RecommendedConfig.LoopbackClientConfig = &rest.Config{}
+	config.RecommendedConfig.ExternalAddress = "localhost:443"
+	config.RecommendedConfig.RESTOptionsGetter = noopRESTOptionsGetter{}
+	if err := server.ApplyMaxRequestBodyBytes(&config.RecommendedConfig.Config, 1024); err != nil {
+		t.Fatal(err)
+	}
+	s, err := config.Complete().New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(body []byte) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/apis/miskatonic.k8s.io/v1beta1/namespaces/default/universities",
+			bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		s.GenericAPIServer.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// Without a name the universities are rejected before reaching the storage
+	university := `{"apiVersion":"miskatonic.k8s.io/v1beta1","kind":"University","metadata":{"annotations":{"motto":"%s"}}}`
+	if w := create([]byte(strings.Replace(university, "%s", strings.Repeat("x", 2048), 1))); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413 for a body over the limit, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := create([]byte(strings.Replace(university, "%s", "lux", 1))); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422 for a body under the limit, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+	miskatonictestclient "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1/testclient"
+)
+
+// TestMetricsLabel checks the generated collector counts the universities by namespace and by their
+// +metrics:label=spec.tier
+func TestMetricsLabel(t *testing.T) {
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	miskatonic.MiskatonicUniversityStorage.Build("miskatonic.k8s.io", getter)
+
+	obj, err := miskatonic.LoadFixture("v1beta1", "University")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []struct{ namespace, name, tier string }{
+		{"arkham", "miskatonic", "ivy"},
+		{"arkham", "brown", "ivy"},
+		{"arkham", "yale", ""},
+		{"kingsport", "miskatonic", "ivy"},
+	} {
+		university := obj.(*miskatonicv1beta1.University).DeepCopy()
+		university.Name = u.name
+		university.Spec.Tier = u.tier
+		if _, err := miskatonictestclient.Universities(u.namespace).Create(university); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := `
+# HELP miskatonic_universities_objects [ALPHA] Number of universities by namespace and spec.tier
+# TYPE miskatonic_universities_objects gauge
+miskatonic_universities_objects{namespace="arkham",tier=""} 1
+miskatonic_universities_objects{namespace="arkham",tier="ivy"} 2
+miskatonic_universities_objects{namespace="kingsport",tier="ivy"} 1
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected),
+		"miskatonic_universities_objects"); err != nil {
+		t.Error(err)
+	}
+}
@@ -19,6 +19,7 @@ limitations under the License.
 
 // +k8s:deepcopy-gen=package,register
 // +groupName=miskatonic.k8s.io
+// +previousGroupName=miskatonic.arkham.io
 
 // Package api is the internal version of the API.
 package miskatonic
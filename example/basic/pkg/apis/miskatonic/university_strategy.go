@@ -19,6 +19,7 @@ package miskatonic
 import (
 	"context"
 
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog"
@@ -37,3 +38,9 @@ func (UniversityStrategy) Validate(ctx context.Context, obj runtime.Object) fiel
 	}
 	return errors
 }
+
+// ValidateUniversityName requires the names of the universities to be DNS labels, as they name the hosts of
+// their campuses
+func ValidateUniversityName(name string, prefix bool) []string {
+	return apivalidation.NameIsDNSLabel(name, prefix)
+}
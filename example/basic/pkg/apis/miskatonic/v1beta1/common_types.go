@@ -0,0 +1,42 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// CommonStatus is embedded in the status of resources reporting conditions.  Print columns may
+// refer to its fields as if they were fields of the embedding status - e.g. .status.conditions[0].type
+type CommonStatus struct {
+	// conditions are the latest observations of the resource's state
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// Condition is an observation of the state of a resource
+type Condition struct {
+	// type of the condition - e.g. Ready
+	Type string `json:"type"`
+
+	// status of the condition, one of True, False or Unknown
+	Status string `json:"status"`
+
+	// reason is a brief CamelCase explanation of the status
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// message is a human readable description of the status
+	// +optional
+	Message string `json:"message,omitempty"`
+}
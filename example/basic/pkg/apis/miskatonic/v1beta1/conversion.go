@@ -0,0 +1,49 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/conversion"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+)
+
+// Convert_v1beta1_ManualCreateUnversionedType_To_miskatonic_ManualCreateUnversionedType drops C, which
+// is left to the conversion webhook of University
+func Convert_v1beta1_ManualCreateUnversionedType_To_miskatonic_ManualCreateUnversionedType(
+	in *ManualCreateUnversionedType, out *miskatonic.ManualCreateUnversionedType, s conversion.Scope) error {
+	return autoConvert_v1beta1_ManualCreateUnversionedType_To_miskatonic_ManualCreateUnversionedType(in, out, s)
+}
+
+const (
+	// DeanAnnotation names the dean of a University in v1beta1
+	DeanAnnotation = "miskatonic.k8s.io/dean-name"
+	// InternalDeanAnnotation names the dean of a University in the internal version
+	InternalDeanAnnotation = "miskatonic.k8s.io/dean"
+)
+
+// MigrateUniversityAnnotations renames the dean annotation between v1beta1 and the internal version
+func MigrateUniversityAnnotations(annotations map[string]string, toInternal bool) {
+	from, to := InternalDeanAnnotation, DeanAnnotation
+	if toInternal {
+		from, to = DeanAnnotation, InternalDeanAnnotation
+	}
+	if dean, found := annotations[from]; found {
+		annotations[to] = dean
+		delete(annotations, from)
+	}
+}
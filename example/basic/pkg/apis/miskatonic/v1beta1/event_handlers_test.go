@@ -0,0 +1,93 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1_test
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeInformer records the event handlers registered with it, so events can be delivered without a watch
+type fakeInformer struct {
+	cache.SharedInformer
+	handlers []cache.ResourceEventHandler
+}
+
+func (f *fakeInformer) AddEventHandler(handler cache.ResourceEventHandler) {
+	f.handlers = append(f.handlers, handler)
+}
+
+var _ = Describe("University event handlers", func() {
+	var informer *fakeInformer
+	var university *University
+
+	BeforeEach(func() {
+		informer = &fakeInformer{}
+		university = &University{ObjectMeta: metav1.ObjectMeta{Name: "miskatonic"}}
+	})
+
+	It("should call the add handler with the University", func() {
+		var added *University
+		OnUniversityAdd(informer, func(obj *University) { added = obj })
+		Expect(informer.handlers).To(HaveLen(1))
+
+		informer.handlers[0].OnAdd(university)
+		Expect(added).To(BeIdenticalTo(university))
+	})
+
+	It("should call the update handler with the old and new University", func() {
+		var oldUniversity, newUniversity *University
+		OnUniversityUpdate(informer, func(oldObj, newObj *University) { oldUniversity, newUniversity = oldObj, newObj })
+		updated := university.DeepCopy()
+		updated.Spec.FacultySize = 10
+
+		informer.handlers[0].OnUpdate(university, updated)
+		Expect(oldUniversity).To(BeIdenticalTo(university))
+		Expect(newUniversity).To(BeIdenticalTo(updated))
+	})
+
+	It("should call the delete handler with the University", func() {
+		var deleted *University
+		OnUniversityDelete(informer, func(obj *University) { deleted = obj })
+
+		informer.handlers[0].OnDelete(university)
+		Expect(deleted).To(BeIdenticalTo(university))
+	})
+
+	It("should unwrap the University from a tombstone", func() {
+		var deleted *University
+		OnUniversityDelete(informer, func(obj *University) { deleted = obj })
+
+		informer.handlers[0].OnDelete(cache.DeletedFinalStateUnknown{Key: "miskatonic", Obj: university})
+		Expect(deleted).To(BeIdenticalTo(university))
+	})
+
+	It("should not call the handlers for other types", func() {
+		called := false
+		OnUniversityAdd(informer, func(*University) { called = true })
+		OnUniversityDelete(informer, func(*University) { called = true })
+
+		informer.handlers[0].OnAdd(&Student{})
+		informer.handlers[1].OnDelete(cache.DeletedFinalStateUnknown{Key: "miskatonic", Obj: &Student{}})
+		Expect(called).To(BeFalse())
+	})
+})
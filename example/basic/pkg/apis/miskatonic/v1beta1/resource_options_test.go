@@ -0,0 +1,52 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1_test
+
+import (
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResourceOptions", func() {
+	var options *miskatonic.ResourceOptions
+	var fs *pflag.FlagSet
+
+	BeforeEach(func() {
+		options = miskatonic.NewResourceOptions()
+		fs = pflag.NewFlagSet("miskatonic", pflag.ContinueOnError)
+		options.AddFlags(fs)
+	})
+
+	It("should add an enable flag for each resource", func() {
+		Expect(fs.Lookup("enable-universities")).ShouldNot(BeNil())
+		Expect(fs.Lookup("enable-students")).ShouldNot(BeNil())
+	})
+
+	It("should serve every resource by default", func() {
+		Expect(fs.Parse([]string{})).ShouldNot(HaveOccurred())
+		Expect(options.DisabledResources()).To(BeEmpty())
+	})
+
+	It("should not serve disabled resources", func() {
+		Expect(fs.Parse([]string{"--enable-students=false"})).ShouldNot(HaveOccurred())
+		Expect(options.DisabledResources()).To(ConsistOf("students"))
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1_test
+
+import (
+	"context"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	_ "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// TestUniversityBehavior defaults each case with the defaulting functions registered for University
+// and validates the result with UniversityStrategy
+func TestUniversityBehavior(t *testing.T) {
+	maxStudents := func(n int) *int { return &n }
+	tests := []struct {
+		name string
+		// in is the University before defaulting
+		in *University
+		// defaulted is the University expected after defaulting
+		defaulted *University
+		// valid is true if the defaulted University passes validation
+		valid bool
+	}{
+		{
+			name:      "empty",
+			in:        &University{},
+			defaulted: &University{Spec: UniversitySpec{MaxStudents: maxStudents(15)}},
+			valid:     true,
+		},
+		{
+			name:      "max students set",
+			in:        &University{Spec: UniversitySpec{MaxStudents: maxStudents(150)}},
+			defaulted: &University{Spec: UniversitySpec{MaxStudents: maxStudents(150)}},
+			valid:     true,
+		},
+		{
+			name:      "too many max students",
+			in:        &University{Spec: UniversitySpec{MaxStudents: maxStudents(151)}},
+			defaulted: &University{Spec: UniversitySpec{MaxStudents: maxStudents(151)}},
+			valid:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := tt.in.DeepCopy()
+			builders.Scheme.Default(obj)
+			if !apiequality.Semantic.DeepEqual(tt.defaulted, obj) {
+				t.Errorf("unexpected defaults: %s", diff.ObjectReflectDiff(tt.defaulted, obj))
+			}
+
+			internal := &miskatonic.University{}
+			if err := builders.Scheme.Convert(obj, internal, nil); err != nil {
+				t.Fatalf("failed to convert %T to %T: %v", obj, internal, err)
+			}
+			errs := miskatonic.UniversityStrategy{}.Validate(context.TODO(), internal)
+			if tt.valid && len(errs) > 0 {
+				t.Errorf("unexpected validation errors: %v", errs.ToAggregate())
+			}
+			if !tt.valid && len(errs) == 0 {
+				t.Errorf("expected validation errors")
+			}
+		})
+	}
+}
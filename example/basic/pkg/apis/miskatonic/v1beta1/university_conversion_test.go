@@ -0,0 +1,129 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("University", func() {
+	var server *httptest.Server
+	var requests []builders.ConversionWebhookRequest
+
+	BeforeEach(func() {
+		requests = nil
+		// The webhook moves the unversioned C field into A
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request := builders.ConversionWebhookRequest{}
+			Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+			requests = append(requests, request)
+
+			c, _, _ := unstructured.NestedString(request.Object.Object, "spec", "Manual", "C")
+			unstructured.SetNestedField(request.Object.Object, c, "spec", "Manual", "A")
+			unstructured.RemoveNestedField(request.Object.Object, "spec", "Manual", "C")
+			Expect(json.NewEncoder(w).Encode(&builders.ConversionWebhookResponse{Object: request.Object})).To(Succeed())
+		}))
+		builders.ConversionWebhook = builders.NewConversionWebhookClient(server.URL, server.Client())
+	})
+
+	AfterEach(func() {
+		builders.ConversionWebhook = nil
+		server.Close()
+	})
+
+	Describe("when converting to the internal type", func() {
+		It("should pass the unmapped fields to the conversion webhook", func() {
+			instance := &University{}
+			instance.Name = "miskatonic-university"
+			instance.Spec.FacultySize = 7
+			instance.Spec.Manual.C = "arkham"
+
+			actual := &miskatonic.University{}
+			Expect(builders.Scheme.Convert(instance, actual, nil)).To(Succeed())
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].UnmappedFields).To(Equal([]string{"spec.Manual.C"}))
+			Expect(requests[0].Object.GetKind()).To(Equal("University"))
+			Expect(actual.Name).To(Equal("miskatonic-university"))
+			Expect(actual.Spec.FacultySize).To(Equal(7))
+			Expect(actual.Spec.Manual.A).To(Equal("arkham"))
+		})
+
+		It("should not call the conversion webhook when every field is mapped", func() {
+			instance := &University{}
+			instance.Name = "miskatonic-university"
+			instance.Spec.Manual.A = "arkham"
+
+			actual := &miskatonic.University{}
+			Expect(builders.Scheme.Convert(instance, actual, nil)).To(Succeed())
+
+			Expect(requests).To(BeEmpty())
+			Expect(actual.Spec.Manual.A).To(Equal("arkham"))
+		})
+
+		It("should fail the conversion when the conversion webhook does not answer within the timeout", func() {
+			hung := make(chan struct{})
+			hungServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-hung
+			}))
+			defer hungServer.Close()
+			defer close(hung)
+			builders.ConversionWebhook = builders.NewConversionWebhookClient(
+				hungServer.URL, &http.Client{Timeout: 100 * time.Millisecond})
+
+			instance := &University{}
+			instance.Name = "miskatonic-university"
+			instance.Spec.Manual.C = "arkham"
+
+			actual := &miskatonic.University{}
+			err := builders.Scheme.Convert(instance, actual, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("conversion webhook failed for fields [spec.Manual.C]"))
+		})
+	})
+	Describe("when converting the annotations", func() {
+		It("should migrate them with the function of the +annotationConversion comment", func() {
+			instance := &University{}
+			instance.Name = "miskatonic-university"
+			instance.Annotations = map[string]string{DeanAnnotation: "Halsey", "founded": "1765"}
+
+			By("renaming the dean annotation of the internal type")
+			internal := &miskatonic.University{}
+			Expect(builders.Scheme.Convert(instance, internal, nil)).To(Succeed())
+			Expect(internal.Annotations).To(Equal(map[string]string{InternalDeanAnnotation: "Halsey", "founded": "1765"}))
+			Expect(instance.Annotations).To(HaveKeyWithValue(DeanAnnotation, "Halsey"))
+
+			By("renaming it back for the versioned type")
+			actual := &University{}
+			Expect(builders.Scheme.Convert(internal, actual, nil)).To(Succeed())
+			Expect(actual.Annotations).To(Equal(instance.Annotations))
+			Expect(actual.Name).To(Equal("miskatonic-university"))
+		})
+	})
+})
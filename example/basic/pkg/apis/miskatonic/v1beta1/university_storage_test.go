@@ -0,0 +1,165 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1_test
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// universityStorage stores created Universities in memory
+type universityStorage struct {
+	storage.Interface
+	created map[string]*miskatonic.University
+}
+
+func (s *universityStorage) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	university := obj.(*miskatonic.University).DeepCopy()
+	s.created[key] = university
+	university.DeepCopyInto(out.(*miskatonic.University))
+	return nil
+}
+
+// universityStorageGetter decorates the store with a universityStorage
+type universityStorageGetter struct {
+	storage *universityStorage
+}
+
+func (g universityStorageGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	return generic.RESTOptions{
+		StorageConfig: &storagebackend.Config{},
+		Decorator: func(*storagebackend.Config, string, func(runtime.Object) (string, error), func() runtime.Object,
+			func() runtime.Object, storage.AttrFunc, storage.IndexerFuncs, *cache.Indexers) (storage.Interface, factory.DestroyFunc, error) {
+			return g.storage, func() {}, nil
+		},
+		ResourcePrefix: resource.Group + "/" + resource.Resource,
+	}, nil
+}
+
+var _ = Describe("University storage", func() {
+	var getter universityStorageGetter
+
+	BeforeEach(func() {
+		getter = universityStorageGetter{&universityStorage{created: map[string]*miskatonic.University{}}}
+	})
+
+	It("should create Universities with a custom NewFunc", func() {
+		called := 0
+		store := builders.NewApiResource(
+			miskatonic.InternalUniversity,
+			func() runtime.Object { return &University{} },
+			func() runtime.Object { return &UniversityList{} },
+			&miskatonic.UniversityStrategy{DefaultStorageStrategy: builders.StorageStrategySingleton},
+			builders.WithNewFunc(func() runtime.Object {
+				called++
+				return &miskatonic.University{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"custom": "true"}}}
+			}),
+		).Build("miskatonic.k8s.io", getter)
+		called = 0
+
+		maxStudents := 10
+		university := &miskatonic.University{
+			ObjectMeta: metav1.ObjectMeta{Name: "miskatonic", Namespace: "default"},
+			Spec:       miskatonic.UniversitySpec{MaxStudents: &maxStudents},
+		}
+		ctx := request.WithNamespace(context.Background(), "default")
+		obj, err := store.Create(ctx, university, nil, &metav1.CreateOptions{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(called).To(Equal(1))
+		Expect(obj.(*miskatonic.University).Name).To(Equal("miskatonic"))
+		Expect(getter.storage.created).To(HaveLen(1))
+	})
+
+	It("should keep the default NewFunc when not overridden", func() {
+		store := builders.NewApiResource(
+			miskatonic.InternalUniversity,
+			func() runtime.Object { return &University{} },
+			func() runtime.Object { return &UniversityList{} },
+			&miskatonic.UniversityStrategy{DefaultStorageStrategy: builders.StorageStrategySingleton},
+		).Build("miskatonic.k8s.io", getter)
+
+		Expect(store.New()).To(Equal(&miskatonic.University{}))
+		Expect(store.NewList()).To(Equal(&miskatonic.UniversityList{}))
+	})
+
+	Describe("when printing a table", func() {
+		var convertor rest.TableConvertor
+
+		BeforeEach(func() {
+			convertor = builders.NewApiResource(
+				miskatonic.InternalUniversity,
+				func() runtime.Object { return &University{} },
+				func() runtime.Object { return &UniversityList{} },
+				builders.NewPrintColumnStorageStrategy(
+					&miskatonic.UniversityStrategy{DefaultStorageStrategy: builders.StorageStrategySingleton},
+					miskatonic.UniversityPrintColumns...),
+			).Build("miskatonic.k8s.io", getter).(rest.TableConvertor)
+		})
+
+		It("should print the columns of Universities", func() {
+			university := &miskatonic.University{
+				ObjectMeta: metav1.ObjectMeta{Name: "miskatonic"},
+				Spec:       miskatonic.UniversitySpec{FacultySize: 7},
+			}
+			table, err := convertor.ConvertToTable(context.Background(), university, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			var names []string
+			for _, column := range table.ColumnDefinitions {
+				names = append(names, column.Name)
+			}
+			Expect(names).To(Equal([]string{"Name", "Faculty", "Condition", "Age"}))
+			Expect(table.Rows).To(HaveLen(1))
+			Expect(table.Rows[0].Cells[1]).To(Equal(7))
+		})
+
+		It("should fall back to the metadata columns for other objects", func() {
+			created := metav1.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+			student := &miskatonic.Student{
+				ObjectMeta: metav1.ObjectMeta{Name: "wilbur", CreationTimestamp: created},
+			}
+			table, err := convertor.ConvertToTable(context.Background(), student, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			var names []string
+			for _, column := range table.ColumnDefinitions {
+				names = append(names, column.Name)
+			}
+			Expect(names).To(Equal([]string{"Name", "Age"}))
+			Expect(table.Rows).To(HaveLen(1))
+			Expect(table.Rows[0].Cells).To(Equal([]interface{}{"wilbur", "2020-01-02T03:04:05Z"}))
+		})
+	})
+})
@@ -30,7 +30,20 @@ import (
 
 // +k8s:openapi-gen=true
 // +resource:path=universities,strategy=UniversityStrategy
+// +resource:printColumn=name=Faculty,type=integer,JSONPath=.spec.faculty_size
+// +resource:printColumn=name=Condition,type=string,JSONPath=.status.conditions[0].type
 // +subresource:request=UniversityCampus,path=campus,kind=UniversityCampus
+// +conversion:webhookFallback
+// +annotationConversion=MigrateUniversityAnnotations
+// +resource:featureGate=MiskatonicUniversities,default=true
+// +resource:defaultOnRead
+// +resource:nameValidation=ValidateUniversityName
+// +webhook:runtime
+// +webhook:matchCondition=exclude-system-namespaces:!request.namespace.startsWith("kube-")
+// +metrics:label=spec.tier
+// +condition=Ready
+// +condition=Available
+// +example={"apiVersion":"miskatonic.k8s.io/v1beta1","kind":"University","metadata":{"name":"miskatonic"},"spec":{"faculty_size":15,"max_students":150}}
 type University struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -42,12 +55,20 @@ type University struct {
 // UniversitySpec defines the desired state of University
 type UniversitySpec struct {
 	// faculty_size defines the desired faculty size of the university.  Defaults to 15.
+	// +json:allowDeviation
 	FacultySize int `json:"faculty_size,omitempty"`
 
 	// max_students defines the maximum number of enrolled students.  Defaults to 300.
 	// +optional
+	// +json:allowDeviation
+	// +uiDescriptor=urn:alm:descriptor:com.tectonic.ui:podCount
+	// +uiDescriptor=urn:alm:descriptor:com.tectonic.ui:number
 	MaxStudents *int `json:"max_students,omitempty"`
 
+	// tier is the tier of the university, counted by the miskatonic_universities_objects gauge
+	// +optional
+	Tier string `json:"tier,omitempty"`
+
 	// The unversioned struct definition for this field must be manually defined in the group package
 	Manual ManualCreateUnversionedType
 
@@ -56,9 +77,44 @@ type UniversitySpec struct {
 
 	Template *corev1.PodSpec `json:"template,omitempty"`
 
+	// +json:allowDeviation
 	ServiceSpec corev1.ServiceSpec `json:"service_spec,omitempty"`
 
 	Rollout []appsv1.Deployment `json:"rollout,omitempty"`
+
+	// The unversioned map is generated with unversioned keys and values, so each entry is
+	// converted individually
+	Departments map[DepartmentName]Department `json:"departments,omitempty"`
+
+	// Annexes are the buildings of the university outside of its main campus.  Their elements are
+	// converted individually, nil elements are kept nil.
+	// +optional
+	Annexes []*Annex `json:"annexes,omitempty"`
+
+	// selector matches the students enrolled at the university.  A Selector method returning the
+	// compiled labels.Selector is generated for this field.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// DepartmentName is the name of a department within the university
+type DepartmentName string
+
+// Department is automatically copied into the unversioned package because it is the
+// value type of a map field
+type Department struct {
+	// chair is the name of the professor heading the department
+	Chair string `json:"chair,omitempty"`
+	// faculty is the number of professors of the department
+	Faculty int `json:"faculty,omitempty"`
+}
+
+// Annex is a building of a university.  Its unversioned copy differs from it through Manual, so the
+// elements of a []*Annex cannot be shared with the unversioned slice.
+type Annex struct {
+	Name string `json:"name,omitempty"`
+
+	Manual ManualCreateUnversionedType `json:"manual,omitempty"`
 }
 
 // Require that the unversioned struct is manually created.  This is *NOT* the default behavior for
@@ -68,6 +124,10 @@ type UniversitySpec struct {
 type ManualCreateUnversionedType struct {
 	A string
 	B bool
+
+	// C has no peer in the unversioned struct, so it is dropped by the conversion unless the
+	// conversion webhook moves it into a field that is converted
+	C string
 }
 
 // Automatically create an unversioned copy of this struct by copying its definition
@@ -81,9 +141,14 @@ type AutomaticCreateUnversionedType struct {
 // UniversityStatus defines the observed state of University
 type UniversityStatus struct {
 	// enrolled_students is the number of currently enrolled students
+	// +json:allowDeviation
 	EnrolledStudents []string `json:"enrolled_students,omitempty"`
 
 	// statusfield provides status information about University
+	// +json:allowDeviation
 	FacultyEmployed []string `json:"faculty_employed,omitempty"`
+
+	// The conditions of the embedded CommonStatus are serialized as status.conditions
+	CommonStatus `json:",inline"`
 }
 
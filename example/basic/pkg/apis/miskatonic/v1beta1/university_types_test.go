@@ -18,11 +18,14 @@ package v1beta1_test
 
 import (
 	"context"
+	"encoding/json"
 
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
 	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
 	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/client/clientset_generated/clientset/typed/miskatonic/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -101,6 +104,53 @@ var _ = Describe("University", func() {
 		})
 	})
 
+	Describe("when compiling the selector", func() {
+		It("should match the selected labels", func() {
+			instance.Spec.Selector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"college": "medicine"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "year", Operator: metav1.LabelSelectorOpIn, Values: []string{"1", "2"}},
+				},
+			}
+			selector, err := instance.Selector()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(selector.Matches(labels.Set{"college": "medicine", "year": "1"})).To(BeTrue())
+			Expect(selector.Matches(labels.Set{"college": "medicine", "year": "3"})).To(BeFalse())
+			Expect(selector.Matches(labels.Set{"college": "law", "year": "1"})).To(BeFalse())
+		})
+
+		It("should match nothing without a selector", func() {
+			selector, err := instance.Selector()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(selector.Matches(labels.Set{"college": "medicine"})).To(BeFalse())
+		})
+	})
+
+	Describe("when printing as a table", func() {
+		It("should resolve columns through the embedded status", func() {
+			client = cs.MiskatonicV1beta1().Universities("university-test-table")
+			actual, err := client.Create(context.TODO(), &instance, metav1.CreateOptions{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			actual.Status.Conditions = []Condition{{Type: "Ready", Status: "True"}}
+			_, err = client.UpdateStatus(context.TODO(), actual, metav1.UpdateOptions{})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			body, err := cs.MiskatonicV1beta1().RESTClient().Get().Namespace("university-test-table").
+				Resource("universities").
+				Name(instance.Name).
+				SetHeader("Accept", "application/json;as=Table;v=v1;g=meta.k8s.io").
+				DoRaw(context.TODO())
+			Expect(err).ShouldNot(HaveOccurred())
+			table := &metav1.Table{}
+			Expect(json.Unmarshal(body, table)).ShouldNot(HaveOccurred())
+
+			Expect(table.ColumnDefinitions[2].Name).To(Equal("Condition"))
+			Expect(table.Rows).To(HaveLen(1))
+			Expect(table.Rows[0].Cells[2]).To(Equal("Ready"))
+		})
+	})
+
 	Describe("when sending a campus request", func() {
 		It("should set the faculty count", func() {
 			client = cs.MiskatonicV1beta1().Universities("university-test-campus")
@@ -125,4 +175,24 @@ var _ = Describe("University", func() {
 			Expect(actual.Spec).Should(Equal(expected.Spec))
 		})
 	})
+
+	Describe("when loading a fixture", func() {
+		It("should decode the versioned University", func() {
+			obj, err := miskatonic.LoadFixture("v1beta1", "University")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(obj).To(BeAssignableToTypeOf(&University{}))
+
+			university := obj.(*University)
+			Expect(university.Name).To(Equal("miskatonic-university"))
+			Expect(university.Spec.FacultySize).To(Equal(7))
+			Expect(university.Spec.MaxStudents).ShouldNot(BeNil())
+			Expect(*university.Spec.MaxStudents).To(Equal(150))
+			Expect(university.Status.EnrolledStudents).To(ConsistOf("herbert-west"))
+		})
+
+		It("should fail for a missing fixture", func() {
+			_, err := miskatonic.LoadFixture("v1beta1", "Student")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,58 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+)
+
+// TestNameRegex checks the strategy of DeepOnes, which have a +resource:nameRegex=^[a-z]+(-[0-9]+)?$ comment,
+// accepts the names matching the regex and rejects the others on create, and rejects renames on update
+func TestNameRegex(t *testing.T) {
+	strategy := innsmouth.InnsmouthDeepOneStorage.StorageBuilder
+	ctx := request.WithNamespace(context.TODO(), "innsmouth")
+
+	for name, valid := range map[string]bool{
+		"obed":       true,
+		"deepone-1":  true,
+		"obed-marsh": false,
+		"1-deepone":  false,
+	} {
+		deepOne := &innsmouth.DeepOne{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "innsmouth"}}
+		err := rest.BeforeCreate(strategy, ctx, deepOne)
+		if valid && err != nil {
+			t.Errorf("expected the deepone %s to be created, got %v", name, err)
+		}
+		if !valid && err == nil {
+			t.Errorf("expected the deepone %s not to be created", name)
+		}
+	}
+
+	old := &innsmouth.DeepOne{ObjectMeta: metav1.ObjectMeta{Name: "deepone-1", Namespace: "innsmouth"}}
+	renamed := old.DeepCopy()
+	renamed.Name = "deepone-2"
+	if err := rest.BeforeUpdate(strategy, ctx, renamed, old); err == nil {
+		t.Errorf("expected the deepone %s not to be renamed to %s", old.Name, renamed.Name)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	innsmouthv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1"
+	kingsportv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1"
+)
+
+// TestObjectKey checks the keys of the namespaced DeepOne and of the cluster scoped Festival are the keys of
+// their informer stores, and split back into the namespace and name of the objects
+func TestObjectKey(t *testing.T) {
+	deepOne := &innsmouthv1.DeepOne{ObjectMeta: metav1.ObjectMeta{Namespace: "innsmouth", Name: "obed"}}
+	key := innsmouthv1.DeepOneKey(deepOne)
+	if storeKey, _ := cache.MetaNamespaceKeyFunc(deepOne); key != storeKey {
+		t.Errorf("expected the key of the deepone to be %q, got %q", storeKey, key)
+	}
+	if namespace, name, err := innsmouthv1.SplitDeepOneKey(key); err != nil ||
+		namespace != deepOne.Namespace || name != deepOne.Name {
+		t.Errorf("expected the key %q to split into innsmouth and obed, got %q, %q, %v", key, namespace, name, err)
+	}
+
+	festival := &kingsportv1.Festival{ObjectMeta: metav1.ObjectMeta{Name: "harvest"}}
+	key = kingsportv1.FestivalKey(festival)
+	if storeKey, _ := cache.MetaNamespaceKeyFunc(festival); key != storeKey {
+		t.Errorf("expected the key of the festival to be %q, got %q", storeKey, key)
+	}
+	if namespace, name, err := kingsportv1.SplitFestivalKey(key); err != nil ||
+		namespace != "" || name != festival.Name {
+		t.Errorf("expected the key %q to split into harvest, got %q, %q, %v", key, namespace, name, err)
+	}
+
+	for _, key := range []string{"", "obed", "innsmouth/", "/obed", "innsmouth/obed/marsh"} {
+		if _, _, err := innsmouthv1.SplitDeepOneKey(key); err == nil {
+			t.Errorf("expected the deepone key %q not to split", key)
+		}
+	}
+	for _, key := range []string{"", "kingsport/harvest"} {
+		if _, _, err := kingsportv1.SplitFestivalKey(key); err == nil {
+			t.Errorf("expected the festival key %q not to split", key)
+		}
+	}
+}
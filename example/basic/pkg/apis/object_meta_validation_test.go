@@ -0,0 +1,75 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport"
+	kingsportv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1"
+	kingsporttestclient "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1/testclient"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+	miskatonictestclient "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1/testclient"
+)
+
+// TestObjectMetaValidation checks the names and labels of the created objects are validated, with the name
+// validation of the +resource:nameValidation comment of University
+func TestObjectMetaValidation(t *testing.T) {
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	miskatonic.MiskatonicUniversityStorage.Build("miskatonic.k8s.io", getter)
+	kingsport.KingsportFestivalStorage.Build("kingsport.k8s.io", getter)
+
+	obj, err := kingsport.LoadFixture("v1", "Festival")
+	if err != nil {
+		t.Fatal(err)
+	}
+	festival := obj.(*kingsportv1.Festival)
+	for _, invalid := range []metav1.ObjectMeta{
+		{Name: "Yule_1922"},
+		{Name: "yule-1923", Labels: map[string]string{"kingsport.k8s.io/": "yule"}},
+	} {
+		festival.ObjectMeta = invalid
+		if _, err := kingsporttestclient.Festivals().Create(festival); !apierrors.IsInvalid(err) {
+			t.Errorf("expected the Festival %+v to be invalid, got %v", invalid, err)
+		}
+	}
+	// The names of festivals are DNS subdomains
+	festival.ObjectMeta = metav1.ObjectMeta{Name: "yule.1922"}
+	if _, err := kingsporttestclient.Festivals().Create(festival); err != nil {
+		t.Errorf("expected the Festival %s to be created, got %v", festival.Name, err)
+	}
+
+	obj, err = miskatonic.LoadFixture("v1beta1", "University")
+	if err != nil {
+		t.Fatal(err)
+	}
+	university := obj.(*miskatonicv1beta1.University)
+	// The names of universities are DNS labels
+	university.Name = "miskatonic.arkham"
+	_, err = miskatonictestclient.Universities("arkham").Create(university)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected the University %s to be invalid, got %v", university.Name, err)
+	}
+	if causes := err.(apierrors.APIStatus).Status().Details.Causes; len(causes) != 1 || causes[0].Field != "metadata.name" {
+		t.Errorf("expected the name of the University to be invalid, got %v", causes)
+	}
+}
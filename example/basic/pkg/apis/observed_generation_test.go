@@ -0,0 +1,87 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/olympus"
+	olympusv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/olympus/v1beta1"
+)
+
+// TestObservedGeneration checks the status updates of Poseidons, which have a +status:observedGeneration
+// comment, set their observed generation
+func TestObservedGeneration(t *testing.T) {
+	var group *builders.APIGroupBuilder
+	for _, b := range apis.GetAllApiBuilders() {
+		if b.Name == "olympus.k8s.io" {
+			group = b
+		}
+	}
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	storage := group.Build(getter).VersionedResourcesStorageMap["v1beta1"]
+	poseidons := storage["poseidons"].(rest.StandardStorage)
+	status := storage["poseidons/status"].(rest.Updater)
+
+	ctx := request.WithNamespace(context.Background(), "default")
+	// update updates poseidon, keeping it up to date with the stored object, and returns its v1beta1 version
+	update := func(updater rest.Updater, poseidon *olympus.Poseidon) *olympusv1beta1.Poseidon {
+		obj, _, err := updater.Update(ctx, poseidon.Name, rest.DefaultUpdatedObjectInfo(poseidon.DeepCopy()),
+			nil, nil, false, &metav1.UpdateOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj.(*olympus.Poseidon).DeepCopyInto(poseidon)
+		versioned := &olympusv1beta1.Poseidon{}
+		if err := builders.Scheme.Convert(obj, versioned, nil); err != nil {
+			t.Fatal(err)
+		}
+		return versioned
+	}
+
+	obj, err := poseidons.Create(ctx, &olympus.Poseidon{ObjectMeta: metav1.ObjectMeta{Name: "poseidon"}},
+		nil, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	poseidon := obj.(*olympus.Poseidon)
+
+	updated := update(status, poseidon)
+	if updated.Status.ObservedGeneration != 1 || !olympusv1beta1.IsPoseidonUpToDate(updated) {
+		t.Errorf("expected the status update to observe generation 1, got %+v", updated.ObjectMeta)
+	}
+
+	poseidon.Spec.Deployment.Name = "trident"
+	updated = update(poseidons, poseidon)
+	if updated.Generation != 2 || olympusv1beta1.IsPoseidonUpToDate(updated) {
+		t.Errorf("expected the spec update to not be observed, got generation %d, observed %d",
+			updated.Generation, updated.Status.ObservedGeneration)
+	}
+
+	updated = update(status, poseidon)
+	if updated.Status.ObservedGeneration != 2 || !olympusv1beta1.IsPoseidonUpToDate(updated) {
+		t.Errorf("expected the status update to observe generation 2, got %d", updated.Status.ObservedGeneration)
+	}
+}
@@ -28,6 +28,9 @@ import (
 // Poseidon
 // +k8s:openapi-gen=true
 // +resource:path=poseidons,strategy=PoseidonStrategy
+// +storageMediaType=application/json
+// +status:observedGeneration
+// +subresource:status:storageMediaType=application/yaml
 type Poseidon struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -40,9 +43,24 @@ type Poseidon struct {
 type PoseidonSpec struct {
 	PodSpec    v1.PodTemplate
 	Deployment appsv1.Deployment
+	// Sea is the state of the sea the Poseidon keeps
+	// +optional
+	Sea PoseidonSea `json:"sea,omitempty"`
 }
 
+// PoseidonSea is the state of the sea kept by a Poseidon
+// +enum
+type PoseidonSea string
+
+const (
+	PoseidonSeaCalm  PoseidonSea = "Calm"
+	PoseidonSeaStorm PoseidonSea = "Storm"
+)
+
 // PoseidonStatus defines the observed state of Poseidon
 type PoseidonStatus struct {
+	// ObservedGeneration is the generation of the Poseidon the status was last updated for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
@@ -18,15 +18,28 @@ package v1beta1_test
 
 import (
 	"context"
+	"encoding/json"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
 
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/olympus"
 	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/olympus/v1beta1"
 	. "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/client/clientset_generated/clientset/typed/olympus/v1beta1"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
 )
 
+type storageConfigGetter struct{}
+
+func (storageConfigGetter) GetRESTOptions(schema.GroupResource) (generic.RESTOptions, error) {
+	return generic.RESTOptions{StorageConfig: &storagebackend.Config{}}, nil
+}
+
 var _ = Describe("Poseidon", func() {
 	var instance Poseidon
 	var expected Poseidon
@@ -76,6 +89,23 @@ var _ = Describe("Poseidon", func() {
 		})
 	})
 
+	Describe("when building the storage", func() {
+		It("should use the configured storage media type", func() {
+			strategy := olympus.OlympusPoseidonStorage.StorageBuilder
+			Expect(strategy).To(BeAssignableToTypeOf(&builders.StorageMediaTypeStorageStrategy{}))
+			Expect(strategy.(*builders.StorageMediaTypeStorageStrategy).MediaType).To(Equal("application/json"))
+
+			options := &generic.StoreOptions{RESTOptions: storageConfigGetter{}}
+			strategy.Build(strategy, &builders.StorageWrapper{}, options)
+			restOptions, err := options.RESTOptions.GetRESTOptions(schema.GroupResource{Group: "olympus.k8s.io", Resource: "poseidons"})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			data, err := runtime.Encode(restOptions.StorageConfig.Codec, &olympus.Poseidon{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(json.Valid(data)).To(BeTrue())
+		})
+	})
+
 	Describe("when listing a resource", func() {
 		Context("using labels", func() {
 			It("shouldn't find the matchings objects because the functions are overriden", func() {
@@ -0,0 +1,169 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"k8s.io/apiserver/pkg/endpoints/openapi"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/rest"
+	"k8s.io/kube-openapi/pkg/builder"
+	"k8s.io/kube-openapi/pkg/common"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/apiserver"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+)
+
+func ref(path string) spec.Ref {
+	return spec.MustCreateRef("#/definitions/" + common.EscapeJsonPointer(path))
+}
+
+// TestGetAllOpenAPIDefinitions checks the definitions of every group are merged along with the shared definitions
+func TestGetAllOpenAPIDefinitions(t *testing.T) {
+	definitions := apis.GetAllOpenAPIDefinitions(ref)
+	for _, name := range []string{
+		"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1.Festival",
+		"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1.University",
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta",
+	} {
+		if _, found := definitions[name]; !found {
+			t.Errorf("missing the OpenAPI definition of %s", name)
+		}
+	}
+}
+
+// TestMergeOpenAPIDefinitions checks a definition returned by more than one getter is taken from the first
+func TestMergeOpenAPIDefinitions(t *testing.T) {
+	getter := func(group string) common.GetOpenAPIDefinitions {
+		return func(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+			return map[string]common.OpenAPIDefinition{
+				group:    {Schema: spec.Schema{SchemaProps: spec.SchemaProps{Description: group}}},
+				"shared": {Schema: spec.Schema{SchemaProps: spec.SchemaProps{Description: group}}},
+			}
+		}
+	}
+	for i := 0; i < 10; i++ {
+		definitions := builders.MergeOpenAPIDefinitions(ref, getter("kingsport"), getter("miskatonic"))
+		if len(definitions) != 3 {
+			t.Fatalf("expected the kingsport, miskatonic and shared definitions, got %v", definitions)
+		}
+		if description := definitions["shared"].Schema.Description; description != "kingsport" {
+			t.Fatalf("expected the shared definition of the first getter, got the one of %s", description)
+		}
+	}
+}
+
+// TestSubresourceOpenAPIPaths checks the OpenAPI spec served by the apiserver has the paths of the subresources
+func TestSubresourceOpenAPIPaths(t *testing.T) {
+	builders.APIGroupBuilders = apis.GetAllApiBuilders()
+	config := (&apiserver.Config{RecommendedConfig: genericapiserver.NewRecommendedConfig(builders.Codecs)}).Init()
+	config.RecommendedConfig.LoopbackClientConfig = &rest.Config{}
+	config.RecommendedConfig.ExternalAddress = "localhost:443"
+	config.RecommendedConfig.RESTOptionsGetter = noopRESTOptionsGetter{}
+	server, err := config.Complete().New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namer := openapi.NewDefinitionNamer(builders.Scheme)
+	openAPIConfig := genericapiserver.DefaultOpenAPIConfig(apis.GetAllOpenAPIDefinitions, namer)
+	openAPISpec, err := builder.BuildOpenAPISpec(
+		server.GenericAPIServer.Handler.GoRestfulContainer.RegisteredWebServices(), openAPIConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{
+		"/apis/miskatonic.k8s.io/v1beta1/namespaces/{namespace}/universities/{name}/status",
+		"/apis/miskatonic.k8s.io/v1beta1/namespaces/{namespace}/universities/{name}/campus",
+		"/apis/miskatonic.k8s.io/v1beta1/namespaces/{namespace}/students/{name}/computer",
+	} {
+		if _, found := openAPISpec.Paths.Paths[path]; !found {
+			t.Errorf("missing the OpenAPI path %s", path)
+		}
+	}
+	for _, name := range []string{
+		"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1.UniversityCampus",
+		"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1.StudentComputer",
+	} {
+		if definition, _ := namer.GetDefinitionName(name); len(openAPISpec.Definitions[definition].Type) == 0 {
+			t.Errorf("missing the OpenAPI definition of subresource request %s", name)
+		}
+	}
+}
+
+// TestOpenAPIExamples checks the example of the +example comment of University is set in its OpenAPI definition
+func TestOpenAPIExamples(t *testing.T) {
+	getter := builders.AddOpenAPIExamples(apis.GetAllOpenAPIDefinitions, apis.GetAllApiBuilders())
+	definition := getter(ref)["sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1.University"]
+	b, err := json.Marshal(definition.Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := struct {
+		Example struct {
+			Kind string `json:"kind"`
+			Spec struct {
+				FacultySize int `json:"faculty_size"`
+			} `json:"spec"`
+		} `json:"example"`
+	}{}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Example.Kind != "University" || schema.Example.Spec.FacultySize != 15 {
+		t.Errorf("expected the example University with a faculty size of 15, got %s", b)
+	}
+
+	definition = getter(ref)["sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1.Festival"]
+	if definition.Schema.Example != nil {
+		t.Errorf("expected no example for Festival, got %v", definition.Schema.Example)
+	}
+}
+
+// TestOpenAPIUIDescriptors checks the descriptors of the +uiDescriptor comments of UniversitySpec.MaxStudents are
+// set as the x-descriptors of its property in the OpenAPI definition
+func TestOpenAPIUIDescriptors(t *testing.T) {
+	getter := builders.AddOpenAPIUIDescriptors(apis.GetAllOpenAPIDefinitions, apis.GetAllApiBuilders())
+	definition := getter(ref)["sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1.UniversitySpec"]
+	b, err := json.Marshal(definition.Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := struct {
+		Properties map[string]struct {
+			Descriptors []string `json:"x-descriptors"`
+		} `json:"properties"`
+	}{}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"urn:alm:descriptor:com.tectonic.ui:podCount",
+		"urn:alm:descriptor:com.tectonic.ui:number",
+	}
+	if descriptors := schema.Properties["max_students"].Descriptors; !reflect.DeepEqual(descriptors, expected) {
+		t.Errorf("expected the x-descriptors %v of max_students, got %s", expected, b)
+	}
+	if descriptors := schema.Properties["faculty_size"].Descriptors; descriptors != nil {
+		t.Errorf("expected no x-descriptors of faculty_size, got %v", descriptors)
+	}
+}
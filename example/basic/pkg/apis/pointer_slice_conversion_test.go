@@ -0,0 +1,64 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// TestPointerSliceConversion checks the elements of the []*Annex field of University are converted through the
+// generated element conversions, the nil elements being kept nil
+func TestPointerSliceConversion(t *testing.T) {
+	in := &v1beta1.University{}
+	in.Name = "miskatonic"
+	in.Spec.Annexes = []*v1beta1.Annex{
+		{Name: "library", Manual: v1beta1.ManualCreateUnversionedType{A: "necronomicon", B: true}},
+		nil,
+		{Name: "observatory"},
+	}
+
+	internal := &miskatonic.University{}
+	if err := builders.Scheme.Convert(in, internal, nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := []*miskatonic.Annex{
+		{Name: "library", Manual: miskatonic.ManualCreateUnversionedType{A: "necronomicon", B: true}},
+		nil,
+		{Name: "observatory"},
+	}
+	if !apiequality.Semantic.DeepEqual(internal.Spec.Annexes, expected) {
+		t.Errorf("unexpected internal annexes: %s", diff.ObjectReflectDiff(expected, internal.Spec.Annexes))
+	}
+
+	out := &v1beta1.University{}
+	if err := builders.Scheme.Convert(internal, out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !apiequality.Semantic.DeepEqual(in.Spec.Annexes, out.Spec.Annexes) {
+		t.Errorf("annexes changed by the round trip: %s", diff.ObjectReflectDiff(in.Spec.Annexes, out.Spec.Annexes))
+	}
+	if out.Spec.Annexes[0] == in.Spec.Annexes[0] {
+		t.Errorf("expected the converted elements to be allocated rather than shared")
+	}
+}
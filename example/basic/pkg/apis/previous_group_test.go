@@ -0,0 +1,72 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// TestPreviousGroupName checks a University stored with the apiVersion of the previous name of the miskatonic
+// group is read as a University of the miskatonic group, and kept under the etcd keys of the previous name
+func TestPreviousGroupName(t *testing.T) {
+	scheme := runtime.NewScheme()
+	apis.Install(scheme)
+	codecs := serializer.NewCodecFactory(scheme)
+	data := []byte(`{"apiVersion":"miskatonic.arkham.io/v1beta1","kind":"University",` +
+		`"metadata":{"name":"miskatonic"},"spec":{"faculty_size":7}}`)
+
+	obj, err := runtime.Decode(codecs.UniversalDecoder(miskatonicv1beta1.SchemeGroupVersion), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	university, ok := obj.(*miskatonicv1beta1.University)
+	if !ok {
+		t.Fatalf("expected a *v1beta1.University, got %T", obj)
+	}
+	if gvk := university.GroupVersionKind(); gvk != miskatonicv1beta1.SchemeGroupVersion.WithKind("University") {
+		t.Errorf("expected the kind of the miskatonic.k8s.io group, got %v", gvk)
+	}
+	if university.Spec.FacultySize != 7 {
+		t.Errorf("expected a faculty size of 7, got %d", university.Spec.FacultySize)
+	}
+
+	obj, err = runtime.Decode(codecs.UniversalDecoder(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := obj.(*miskatonic.University); !ok {
+		t.Fatalf("expected a *miskatonic.University, got %T", obj)
+	}
+
+	info := apis.GetMiskatonicAPIBuilder().Build(noopRESTOptionsGetter{})
+	store, ok := info.VersionedResourcesStorageMap["v1beta1"]["universities"].(*builders.StorageWrapper)
+	if !ok {
+		t.Fatalf("expected a *builders.StorageWrapper, got %T", info.VersionedResourcesStorageMap["v1beta1"]["universities"])
+	}
+	if root := store.KeyRootFunc(genericapirequest.NewContext()); root != "/miskatonic.arkham.io/universities" {
+		t.Errorf("expected the universities under the etcd keys of miskatonic.arkham.io, got %s", root)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport"
+)
+
+// TestRangeDefault checks the strategy of Festivals, which have a +resource:rangeDefault comment, clamps the
+// capacity of the created festivals into 10 to 5000 and defaults an unset capacity to 10
+func TestRangeDefault(t *testing.T) {
+	strategy := kingsport.KingsportFestivalStorage.StorageBuilder
+
+	capacity := func(n int32) *int32 { return &n }
+	for _, test := range []struct {
+		name     string
+		capacity *int32
+		expected int32
+	}{
+		{name: "unset", capacity: nil, expected: 10},
+		{name: "below min", capacity: capacity(3), expected: 10},
+		{name: "min", capacity: capacity(10), expected: 10},
+		{name: "in range", capacity: capacity(200), expected: 200},
+		{name: "max", capacity: capacity(5000), expected: 5000},
+		{name: "above max", capacity: capacity(9000), expected: 5000},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			festival := &kingsport.Festival{
+				ObjectMeta: metav1.ObjectMeta{Name: "yule-1922"},
+				Spec:       kingsport.FestivalSpec{Year: 1922, Capacity: test.capacity},
+			}
+			strategy.PrepareForCreate(context.TODO(), festival)
+			if festival.Spec.Capacity == nil || *festival.Spec.Capacity != test.expected {
+				t.Errorf("expected the capacity to be %d, got %v", test.expected, festival.Spec.Capacity)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport"
+)
+
+// TestReadOnlyField checks the strategy of Pilgrims ignores the +readOnly arrived status set by the clients,
+// clearing it from the created pilgrims and keeping it from the stored pilgrims on updates
+func TestReadOnlyField(t *testing.T) {
+	strategy := kingsport.KingsportPilgrimStorage.StorageBuilder
+	ctx := request.WithNamespace(context.TODO(), "kingsport")
+
+	created := &kingsport.Pilgrim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kingsport", Name: "randolph"},
+		Spec:       kingsport.PilgrimSpec{Festival: "yule-1922"},
+		Status:     kingsport.PilgrimStatus{Arrived: true},
+	}
+	strategy.PrepareForCreate(ctx, created)
+	if created.Status.Arrived {
+		t.Errorf("expected the arrived status set by the client to be cleared from the created pilgrim")
+	}
+
+	stored := created.DeepCopy()
+	stored.Status.Arrived = true
+	updated := stored.DeepCopy()
+	updated.Spec.Festival = "yule-1923"
+	updated.Status.Arrived = false
+	strategy.PrepareForUpdate(ctx, updated, stored)
+	if !updated.Status.Arrived {
+		t.Errorf("expected the updated pilgrim to keep the arrived status of the stored pilgrim")
+	}
+	if updated.Spec.Festival != "yule-1923" {
+		t.Errorf("expected the update of the festival to be kept, got %q", updated.Spec.Festival)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/controller/result"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// TestReconcileResult checks the results returned by the scaffolded controllers requeue their requests
+// after the expected durations
+func TestReconcileResult(t *testing.T) {
+	failed := fmt.Errorf("failed")
+	for _, tc := range []struct {
+		name         string
+		result       func() (reconcile.Result, error)
+		requeueAfter time.Duration
+		err          error
+	}{
+		{name: "Done", result: result.Done},
+		{
+			name:         "RequeueAfter",
+			result:       func() (reconcile.Result, error) { return result.RequeueAfter(30 * time.Second) },
+			requeueAfter: 30 * time.Second,
+		},
+		{
+			name:   "Error",
+			result: func() (reconcile.Result, error) { return result.Error(failed) },
+			err:    failed,
+		},
+	} {
+		r, err := tc.result()
+		if r.Requeue {
+			t.Errorf("%s: expected the request not to be requeued immediately", tc.name)
+		}
+		if r.RequeueAfter != tc.requeueAfter {
+			t.Errorf("%s: expected the request to be requeued after %v, got %v", tc.name, tc.requeueAfter,
+				r.RequeueAfter)
+		}
+		if err != tc.err {
+			t.Errorf("%s: expected the error %v, got %v", tc.name, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+	innsmouthv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// TestInstallAll checks the register file generated by --emit-register-all installs the innsmouth and the
+// miskatonic groups with a scheme, and with the builders.Scheme through the imports of their install packages
+func TestInstallAll(t *testing.T) {
+	kinds := []schema.GroupVersionKind{
+		innsmouth.SchemeGroupVersion.WithKind("DeepOne"),
+		innsmouthv1.SchemeGroupVersion.WithKind("DeepOne"),
+		miskatonic.SchemeGroupVersion.WithKind("University"),
+		miskatonicv1beta1.SchemeGroupVersion.WithKind("University"),
+	}
+	for _, kind := range kinds {
+		if !builders.Scheme.Recognizes(kind) {
+			t.Errorf("expected the import of the install packages to install %v with the builders.Scheme", kind)
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	apis.InstallAll(scheme)
+	for _, kind := range kinds {
+		if !scheme.Recognizes(kind) {
+			t.Errorf("expected InstallAll to install %v", kind)
+		}
+	}
+	for _, group := range []string{innsmouth.SchemeGroupVersion.Group, miskatonic.SchemeGroupVersion.Group} {
+		if !scheme.IsGroupRegistered(group) {
+			t.Errorf("expected InstallAll to register the %s group", group)
+		}
+	}
+}
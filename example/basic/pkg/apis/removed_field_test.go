@@ -0,0 +1,87 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport"
+)
+
+// TestRemovedField checks the patron of festivals, which has a +removedField comment, is cleared from the
+// created festivals and from the festivals stored before its removal
+func TestRemovedField(t *testing.T) {
+	memory := &memoryStorage{objects: map[string]runtime.Object{}}
+	festivals := kingsport.KingsportFestivalStorage.Build("kingsport.k8s.io", memoryStorageGetter{memory}).(rest.StandardStorage)
+	ctx := context.Background()
+
+	obj, err := kingsport.LoadFixture("v1", "Festival")
+	if err != nil {
+		t.Fatal(err)
+	}
+	festival := &kingsport.Festival{}
+	if err := builders.Scheme.Convert(obj, festival, nil); err != nil {
+		t.Fatal(err)
+	}
+	festival.Spec.Patron = "joseph-curwen"
+	created, err := festivals.Create(ctx, festival, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patron := created.(*kingsport.Festival).Spec.Patron; patron != "" {
+		t.Errorf("expected the patron to be cleared from the created festival, got %q", patron)
+	}
+
+	// Festivals stored before the removal of the field still set it
+	for _, stored := range memory.objects {
+		stored.(*kingsport.Festival).Spec.Patron = "joseph-curwen"
+	}
+	read, err := festivals.Get(ctx, festival.Name, &metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patron := read.(*kingsport.Festival).Spec.Patron; patron != "" {
+		t.Errorf("expected the patron to be cleared from the read festival, got %q", patron)
+	}
+	for _, stored := range memory.objects {
+		if patron := stored.(*kingsport.Festival).Spec.Patron; patron != "joseph-curwen" {
+			t.Errorf("expected reads to not change the stored festival, got the patron %q", patron)
+		}
+	}
+
+	update := read.(*kingsport.Festival).DeepCopy()
+	update.Spec.Year = 1923
+	updated, _, err := festivals.Update(ctx, festival.Name, rest.DefaultUpdatedObjectInfo(update),
+		rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patron := updated.(*kingsport.Festival).Spec.Patron; patron != "" {
+		t.Errorf("expected the patron to be cleared from the updated festival, got %q", patron)
+	}
+	for _, stored := range memory.objects {
+		if patron := stored.(*kingsport.Festival).Spec.Patron; patron != "" {
+			t.Errorf("expected the update to clear the patron of the stored festival, got %q", patron)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+)
+
+// exactMemoryStorage reads the limited lists at exactly their resource version like etcd, leaving out the objects
+// stored after it
+type exactMemoryStorage struct {
+	*memoryStorage
+}
+
+func (s exactMemoryStorage) List(ctx context.Context, key string, resourceVersion string, p storage.SelectionPredicate,
+	listObj runtime.Object) error {
+	if err := s.memoryStorage.List(ctx, key, resourceVersion, p, listObj); err != nil {
+		return err
+	}
+	if len(resourceVersion) == 0 || resourceVersion == "0" || p.Limit == 0 {
+		return nil
+	}
+	version, err := strconv.ParseUint(resourceVersion, 10, 64)
+	if err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(listObj)
+	if err != nil {
+		return err
+	}
+	read := []runtime.Object{}
+	for _, item := range items {
+		if itemVersion, err := s.Versioner().ObjectResourceVersion(item); err == nil && itemVersion <= version {
+			read = append(read, item)
+		}
+	}
+	return meta.SetList(listObj, read)
+}
+
+// exactMemoryStorageGetter decorates the stores with an exactMemoryStorage
+type exactMemoryStorageGetter struct {
+	storage exactMemoryStorage
+}
+
+func (g exactMemoryStorageGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	options, err := memoryStorageGetter{g.storage.memoryStorage}.GetRESTOptions(resource)
+	options.Decorator = func(*storagebackend.Config, string, func(runtime.Object) (string, error), func() runtime.Object,
+		func() runtime.Object, storage.AttrFunc, storage.IndexerFuncs, *cache.Indexers) (storage.Interface, factory.DestroyFunc, error) {
+		return g.storage, func() {}, nil
+	}
+	return options, err
+}
+
+// TestResourceVersionMatch checks the Universities listed with resourceVersionMatch=NotOlderThan are never older
+// than the resourceVersion of the request, while the limited lists without it, or with Exact, are read at the
+// resourceVersion
+func TestResourceVersionMatch(t *testing.T) {
+	getter := exactMemoryStorageGetter{exactMemoryStorage{&memoryStorage{objects: map[string]runtime.Object{}}}}
+	universities := miskatonic.MiskatonicUniversityStorage.Build("miskatonic.k8s.io", getter)
+
+	ctx := request.WithNamespace(context.Background(), "arkham")
+	maxStudents := 150
+	created, err := universities.Create(ctx, &miskatonic.University{
+		ObjectMeta: metav1.ObjectMeta{Name: "miskatonic", Namespace: "arkham"},
+		Spec:       miskatonic.UniversitySpec{FacultySize: 15, MaxStudents: &maxStudents},
+	}, nil, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resourceVersion := created.(*miskatonic.University).ResourceVersion
+	if _, err := universities.Create(ctx, &miskatonic.University{
+		ObjectMeta: metav1.ObjectMeta{Name: "brown", Namespace: "arkham"},
+		Spec:       miskatonic.UniversitySpec{FacultySize: 15, MaxStudents: &maxStudents},
+	}, nil, &metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		match    string
+		limit    int64
+		expected int
+	}{
+		{match: "", limit: 10, expected: 1},
+		{match: "NotOlderThan", limit: 10, expected: 2},
+		{match: "Exact", expected: 1},
+	}
+	for _, test := range tests {
+		// The lists are served through the filter of the apiserver passing the query parameter to the storage
+		var list runtime.Object
+		handler := builders.WithResourceVersionMatch(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			list, err = universities.List(request.WithNamespace(req.Context(), "arkham"),
+				&metainternalversion.ListOptions{ResourceVersion: resourceVersion, Limit: test.limit})
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet,
+			"/apis/miskatonic.k8s.io/v1beta1/namespaces/arkham/universities?resourceVersionMatch="+test.match, nil))
+		if err != nil {
+			t.Errorf("failed to list with resourceVersionMatch %q: %v", test.match, err)
+			continue
+		}
+		if items := list.(*miskatonic.UniversityList).Items; len(items) != test.expected {
+			t.Errorf("expected %d Universities listed at resourceVersion %s with resourceVersionMatch %q, got %d",
+				test.expected, resourceVersion, test.match, len(items))
+		}
+	}
+
+	match := builders.ContextWithResourceVersionMatch(ctx, builders.ResourceVersionMatchNotOlderThan)
+	if _, err := universities.List(match, &metainternalversion.ListOptions{}); !apierrors.IsBadRequest(err) {
+		t.Errorf("expected a NotOlderThan list without resourceVersion to be a bad request, got %v", err)
+	}
+	if _, err := universities.Watch(match, &metainternalversion.ListOptions{ResourceVersion: resourceVersion}); !apierrors.IsBadRequest(err) {
+		t.Errorf("expected a watch with resourceVersionMatch to be a bad request, got %v", err)
+	}
+}
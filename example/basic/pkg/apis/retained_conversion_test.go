@@ -0,0 +1,59 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+	innsmouthv1alpha1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1alpha1"
+	innsmouthv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1beta1"
+)
+
+// TestRetainedConversion checks the v1alpha1 Shoggoth, which has a +retainConversion comment, is no longer
+// served while a shoggoth stored in v1alpha1 still converts to v1beta1 through the internal version
+func TestRetainedConversion(t *testing.T) {
+	storage := apis.GetInnsmouthAPIBuilder().Build(noopRESTOptionsGetter{}).VersionedResourcesStorageMap
+	if _, found := storage["v1alpha1"]["shoggoths"]; found {
+		t.Errorf("expected v1alpha1 not to serve shoggoths")
+	}
+	if _, found := storage["v1beta1"]["shoggoths"]; !found {
+		t.Errorf("expected v1beta1 to serve shoggoths")
+	}
+
+	stored := &innsmouthv1alpha1.Shoggoth{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "innsmouth", Name: "tekeli-li"},
+		Spec:       innsmouthv1alpha1.ShoggothSpec{Eyes: 7},
+		Status:     innsmouthv1alpha1.ShoggothStatus{Master: "obed"},
+	}
+	internal := &innsmouth.Shoggoth{}
+	if err := builders.Scheme.Convert(stored, internal, nil); err != nil {
+		t.Fatal(err)
+	}
+	migrated := &innsmouthv1beta1.Shoggoth{}
+	if err := builders.Scheme.Convert(internal, migrated, nil); err != nil {
+		t.Fatal(err)
+	}
+	if migrated.Name != stored.Name || migrated.Spec.Eyes != stored.Spec.Eyes ||
+		migrated.Status.Master != stored.Status.Master {
+		t.Errorf("expected the v1alpha1 shoggoth %v to migrate to v1beta1, got %v", stored, migrated)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// University has a +webhook:runtime comment, so it implements the webhook interfaces of controller-runtime
+var _ webhook.Defaulter = &miskatonicv1beta1.University{}
+var _ webhook.Validator = &miskatonicv1beta1.University{}
+
+// TestRuntimeWebhook checks the webhook methods of University default it with its defaulting functions and
+// validate it with its storage, which validates its name with its +resource:nameValidation function
+func TestRuntimeWebhook(t *testing.T) {
+	university := &miskatonicv1beta1.University{ObjectMeta: metav1.ObjectMeta{Namespace: "arkham", Name: "miskatonic"}}
+	university.Default()
+	if university.Spec.MaxStudents == nil || *university.Spec.MaxStudents != 15 {
+		t.Fatalf("expected the university to be defaulted to 15 max students, got %v", university.Spec.MaxStudents)
+	}
+	if err := university.ValidateCreate(); err != nil {
+		t.Errorf("expected the university to be valid, got %v", err)
+	}
+
+	// The names of universities must be DNS labels
+	invalid := university.DeepCopy()
+	invalid.Name = "miskatonic.arkham"
+	if err := invalid.ValidateCreate(); !apierrors.IsInvalid(err) {
+		t.Errorf("expected the university %s to be invalid, got %v", invalid.Name, err)
+	}
+
+	if err := university.ValidateUpdate(university.DeepCopy()); err != nil {
+		t.Errorf("expected the university update to be valid, got %v", err)
+	}
+	if err := invalid.ValidateUpdate(university); !apierrors.IsInvalid(err) {
+		t.Errorf("expected the university update to %s to be invalid, got %v", invalid.Name, err)
+	}
+	if err := university.ValidateDelete(); err != nil {
+		t.Errorf("expected the university to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// TestSemanticEqual checks universities of different versions are compared by their internal version
+func TestSemanticEqual(t *testing.T) {
+	maxStudents := 300
+	desired := &miskatonicv1beta1.University{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "miskatonic.k8s.io/v1beta1", Kind: "University"},
+		ObjectMeta: metav1.ObjectMeta{Name: "miskatonic", Labels: map[string]string{"city": "arkham"}},
+		Spec:       miskatonicv1beta1.UniversitySpec{FacultySize: 15, MaxStudents: &maxStudents},
+	}
+	actual := &miskatonic.University{}
+	if err := builders.Scheme.Convert(desired, actual, nil); err != nil {
+		t.Fatal(err)
+	}
+	actual.TypeMeta = metav1.TypeMeta{}
+	actual.ResourceVersion = "42"
+
+	compare := func(a, b runtime.Object) bool {
+		equal, err := miskatonic.UniversitySemanticEqual(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return equal
+	}
+	if !compare(desired, actual) {
+		t.Errorf("expected the v1beta1 and internal universities to be equal")
+	}
+	if !compare(actual, desired.DeepCopy()) {
+		t.Errorf("expected the internal and v1beta1 universities to be equal")
+	}
+
+	actual.Spec.FacultySize = 16
+	if compare(desired, actual) {
+		t.Errorf("expected universities with different faculty sizes to differ")
+	}
+	actual.Spec.FacultySize = 15
+	actual.Labels = map[string]string{"city": "innsmouth"}
+	if compare(desired, actual) {
+		t.Errorf("expected universities with different labels to differ")
+	}
+}
+
+// TestSpecEqual checks universities are compared by their specs only, semantically
+func TestSpecEqual(t *testing.T) {
+	university := func(memory string) *miskatonic.University {
+		return &miskatonic.University{
+			ObjectMeta: metav1.ObjectMeta{Name: "miskatonic"},
+			Spec: miskatonic.UniversitySpec{
+				FacultySize: 15,
+				Template: &corev1.PodSpec{Containers: []corev1.Container{{
+					Name: "library",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+					},
+				}}},
+			},
+		}
+	}
+	desired := university("1Gi")
+	observed := university("1024Mi")
+	observed.ResourceVersion = "42"
+	observed.Status.EnrolledStudents = []string{"wilbur"}
+
+	if !miskatonic.UniversitySpecEqual(desired, observed) {
+		t.Errorf("expected universities with equal specs and different statuses to be equal")
+	}
+	observed.Spec.FacultySize = 16
+	if miskatonic.UniversitySpecEqual(desired, observed) {
+		t.Errorf("expected universities with different faculty sizes to differ")
+	}
+	if miskatonic.UniversitySpecEqual(desired, nil) {
+		t.Errorf("expected a university to differ from nil")
+	}
+}
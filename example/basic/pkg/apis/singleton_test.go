@@ -0,0 +1,68 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport"
+)
+
+// TestSingleton checks the objects of Harbor, which has the "+singleton=kingsport" comment, are only created under
+// the name of the comment, which the objects created without a name default to, once per namespace
+func TestSingleton(t *testing.T) {
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	harbors := kingsport.KingsportHarborStorage.Build("kingsport.k8s.io", getter)
+
+	ctx := request.WithNamespace(context.Background(), "kingsport")
+	_, err := harbors.Create(ctx, &kingsport.Harbor{ObjectMeta: metav1.ObjectMeta{Name: "innsmouth"}}, nil,
+		&metav1.CreateOptions{})
+	if !apierrors.IsInvalid(err) {
+		t.Errorf("expected the Harbor named innsmouth to be invalid, got %v", err)
+	} else if expected := `metadata.name: Invalid value: "innsmouth": must be "kingsport"`; !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected the error to contain %q, got %v", expected, err)
+	}
+	if _, found := getter.storage.objects["/kingsport.k8s.io/harbors/kingsport/innsmouth"]; found {
+		t.Errorf("expected the Harbor named innsmouth not to be stored")
+	}
+
+	obj, err := harbors.Create(ctx, &kingsport.Harbor{}, nil, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("expected the Harbor without a name to be created, got %v", err)
+	}
+	if name := obj.(*kingsport.Harbor).Name; name != "kingsport" {
+		t.Errorf("expected the name of the Harbor to default to kingsport, got %q", name)
+	}
+
+	_, err = harbors.Create(ctx, &kingsport.Harbor{}, nil, &metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		t.Errorf("expected the second Harbor of the namespace to already exist, got %v", err)
+	}
+
+	ctx = request.WithNamespace(context.Background(), "dunwich")
+	if _, err := harbors.Create(ctx, &kingsport.Harbor{ObjectMeta: metav1.ObjectMeta{Name: "kingsport"}}, nil,
+		&metav1.CreateOptions{}); err != nil {
+		t.Errorf("expected the Harbor named kingsport of another namespace to be created, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+)
+
+// TestStampUser checks the strategy of DeepOnes, which have a +resource:stampUser comment, annotates them with
+// the users of the requests creating and updating them
+func TestStampUser(t *testing.T) {
+	strategy := innsmouth.InnsmouthDeepOneStorage.StorageBuilder
+	userContext := func(name string) context.Context {
+		return request.WithUser(context.TODO(), &user.DefaultInfo{Name: name})
+	}
+
+	created := &innsmouth.DeepOne{}
+	created.Name = "obed"
+	// The annotations set by the request are overwritten
+	created.Annotations = map[string]string{
+		"innsmouth.k8s.io/created-by": "dagon",
+		"innsmouth.k8s.io/updated-by": "dagon",
+	}
+	strategy.PrepareForCreate(userContext("alice"), created)
+	if by := created.Annotations["innsmouth.k8s.io/created-by"]; by != "alice" {
+		t.Errorf("expected the deepone to be created by alice, got %q", by)
+	}
+	if by, found := created.Annotations["innsmouth.k8s.io/updated-by"]; found {
+		t.Errorf("expected a created deepone not to be updated, got the updater %q", by)
+	}
+
+	updated := created.DeepCopy()
+	updated.Annotations["innsmouth.k8s.io/created-by"] = "dagon"
+	strategy.PrepareForUpdate(userContext("bob"), updated, created)
+	if by := updated.Annotations["innsmouth.k8s.io/created-by"]; by != "alice" {
+		t.Errorf("expected the updated deepone to keep its creator alice, got %q", by)
+	}
+	if by := updated.Annotations["innsmouth.k8s.io/updated-by"]; by != "bob" {
+		t.Errorf("expected the deepone to be updated by bob, got %q", by)
+	}
+}
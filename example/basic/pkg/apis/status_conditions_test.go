@@ -0,0 +1,56 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"reflect"
+	"testing"
+
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// TestStatusConditions checks the accessors generated for the Ready and Available conditions of universities
+// read and write the condition of their own type, leaving the other conditions alone
+func TestStatusConditions(t *testing.T) {
+	university := &miskatonicv1beta1.University{}
+	university.Status.Conditions = []miskatonicv1beta1.Condition{{Type: "Accredited", Status: "True"}}
+	if c := miskatonicv1beta1.GetUniversityReadyCondition(university); c != nil {
+		t.Errorf("expected no Ready condition, got %v", c)
+	}
+	if c := miskatonicv1beta1.GetUniversityAvailableCondition(university); c != nil {
+		t.Errorf("expected no Available condition, got %v", c)
+	}
+
+	miskatonicv1beta1.SetUniversityReadyCondition(university, "False", "Enrolling", "the students are enrolling")
+	miskatonicv1beta1.SetUniversityAvailableCondition(university, "True", "Open", "the campus is open")
+	miskatonicv1beta1.SetUniversityReadyCondition(university, "True", "Enrolled", "the students are enrolled")
+
+	expected := []miskatonicv1beta1.Condition{
+		{Type: "Accredited", Status: "True"},
+		{Type: "Ready", Status: "True", Reason: "Enrolled", Message: "the students are enrolled"},
+		{Type: "Available", Status: "True", Reason: "Open", Message: "the campus is open"},
+	}
+	if !reflect.DeepEqual(university.Status.Conditions, expected) {
+		t.Errorf("expected the conditions %v, got %v", expected, university.Status.Conditions)
+	}
+	if c := miskatonicv1beta1.GetUniversityReadyCondition(university); c == nil || !reflect.DeepEqual(*c, expected[1]) {
+		t.Errorf("expected the Ready condition %v, got %v", expected[1], c)
+	}
+	if c := miskatonicv1beta1.GetUniversityAvailableCondition(university); c == nil || !reflect.DeepEqual(*c, expected[2]) {
+		t.Errorf("expected the Available condition %v, got %v", expected[2], c)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/olympus"
+	olympusv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/olympus/v1beta1"
+)
+
+// TestStatusStorageMediaType checks Poseidons, stored as application/json, are stored as application/yaml
+// through the status subresource, per their +subresource:status:storageMediaType comment
+func TestStatusStorageMediaType(t *testing.T) {
+	// encoder returns the options of the serializer encoding the objects stored by strategy, read from the
+	// identifier of the storage codec
+	encoder := func(strategy builders.StorageBuilder) map[string]string {
+		getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+		options := &generic.StoreOptions{RESTOptions: getter}
+		strategy.Build(strategy, &builders.StorageWrapper{}, options)
+		restOptions, err := options.RESTOptions.GetRESTOptions(
+			schema.GroupResource{Group: "olympus.k8s.io", Resource: "poseidons"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		codec := struct{ Encoder string }{}
+		if err := json.Unmarshal([]byte(restOptions.StorageConfig.Codec.Identifier()), &codec); err != nil {
+			t.Fatal(err)
+		}
+		serializer := map[string]string{}
+		if err := json.Unmarshal([]byte(codec.Encoder), &serializer); err != nil {
+			t.Fatal(err)
+		}
+		return serializer
+	}
+
+	strategy, ok := olympusv1beta1.PoseidonStatusStorage.StorageBuilder.(*builders.StorageMediaTypeStorageStrategy)
+	if !ok {
+		t.Fatalf("expected the status storage to be a StorageMediaTypeStorageStrategy, got %T",
+			olympusv1beta1.PoseidonStatusStorage.StorageBuilder)
+	}
+	if strategy.MediaType != "application/yaml" {
+		t.Errorf("expected the status storage media type application/yaml, got %s", strategy.MediaType)
+	}
+	if serializer := encoder(strategy); serializer["name"] != "json" || serializer["yaml"] != "true" {
+		t.Errorf("expected the status storage to encode yaml, got the serializer %v", serializer)
+	}
+	if serializer := encoder(olympus.OlympusPoseidonStorage.StorageBuilder); serializer["name"] != "json" ||
+		serializer["yaml"] != "false" {
+		t.Errorf("expected the storage to encode json, got the serializer %v", serializer)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+	innsmouthv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1beta1"
+)
+
+// TestTenantKeyPrefix checks the objects of namespaces of different tenants are stored under the prefixes
+// of their tenant and are not read through the namespaces of the other tenants
+func TestTenantKeyPrefix(t *testing.T) {
+	tenants := map[string]string{"dunwich": "whateley", "innsmouth": "marsh"}
+	tenant := func(ctx context.Context) (string, error) {
+		namespace, _ := request.NamespaceFrom(ctx)
+		if tenant, ok := tenants[namespace]; ok {
+			return tenant, nil
+		}
+		return "", fmt.Errorf("namespace %q has no tenant", namespace)
+	}
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	shoggoths := builders.NewApiResource(
+		innsmouth.InternalShoggoth,
+		func() runtime.Object { return &innsmouthv1beta1.Shoggoth{} },
+		func() runtime.Object { return &innsmouthv1beta1.ShoggothList{} },
+		builders.NewTenantKeyPrefixStorageStrategy(tenant, innsmouth.InnsmouthShoggothStorage.StorageBuilder),
+	).Build("innsmouth.k8s.io", getter)
+
+	for _, namespace := range []string{"dunwich", "innsmouth"} {
+		ctx := request.WithNamespace(context.Background(), namespace)
+		if _, err := shoggoths.Create(ctx, &innsmouth.Shoggoth{ObjectMeta: metav1.ObjectMeta{Name: "tekeli-li"}},
+			nil, &metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys := []string{}
+	for key := range getter.storage.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	expected := []string{
+		"/innsmouth.k8s.io/shoggoths/marsh/innsmouth/tekeli-li",
+		"/innsmouth.k8s.io/shoggoths/whateley/dunwich/tekeli-li",
+	}
+	if fmt.Sprint(keys) != fmt.Sprint(expected) {
+		t.Errorf("expected the Shoggoths to be stored under the prefixes of their tenant %v, got %v", expected, keys)
+	}
+
+	for _, namespace := range []string{"dunwich", "innsmouth"} {
+		ctx := request.WithNamespace(context.Background(), namespace)
+		obj, err := shoggoths.Get(ctx, "tekeli-li", &metav1.GetOptions{})
+		if err != nil {
+			t.Errorf("expected the Shoggoth of %s to be found, got %v", namespace, err)
+		} else if obj.(*innsmouth.Shoggoth).Namespace != namespace {
+			t.Errorf("expected the Shoggoth of %s, got the one of %s", namespace, obj.(*innsmouth.Shoggoth).Namespace)
+		}
+	}
+	list, err := shoggoths.List(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items := list.(*innsmouth.ShoggothList).Items; len(items) != 2 {
+		t.Errorf("expected the list across all namespaces to span the tenants, got %d Shoggoths", len(items))
+	}
+
+	// Once dunwich belongs to the marsh tenant, the objects of the whateley tenant are no longer read
+	tenants["dunwich"] = "marsh"
+	ctx := request.WithNamespace(context.Background(), "dunwich")
+	if _, err := shoggoths.Get(ctx, "tekeli-li", &metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the Shoggoth of the whateley tenant to not be found by the marsh tenant, got %v", err)
+	}
+
+	ctx = request.WithNamespace(context.Background(), "arkham")
+	if _, err := shoggoths.Create(ctx, &innsmouth.Shoggoth{ObjectMeta: metav1.ObjectMeta{Name: "tekeli-li"}},
+		nil, &metav1.CreateOptions{}); err == nil {
+		t.Errorf("expected the Shoggoth of a namespace without tenant to not be created")
+	}
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/etcd3"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+	innsmouthv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1"
+	innsmouthtestclient "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth/v1/testclient"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport"
+	kingsportv1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1"
+	kingsporttestclient "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport/v1/testclient"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic"
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+	miskatonictestclient "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1/testclient"
+)
+
+// memoryStorage stores the created objects in memory
+type memoryStorage struct {
+	storage.Interface
+	objects map[string]runtime.Object
+	version uint64
+	// lists counts the lists, i.e. the pages listed
+	lists int
+}
+
+// store sets the next resourceVersion of obj and stores it under key
+func (s *memoryStorage) store(key string, obj runtime.Object) error {
+	s.version++
+	if err := s.Versioner().UpdateObject(obj, s.version); err != nil {
+		return err
+	}
+	s.objects[key] = obj.DeepCopyObject()
+	return nil
+}
+
+func (s *memoryStorage) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	if _, found := s.objects[key]; found {
+		return storage.NewKeyExistsError(key, 0)
+	}
+	obj = obj.DeepCopyObject()
+	if err := s.store(key, obj); err != nil {
+		return err
+	}
+	reflect.ValueOf(out).Elem().Set(reflect.ValueOf(obj.DeepCopyObject()).Elem())
+	return nil
+}
+
+func (s *memoryStorage) Get(ctx context.Context, key string, resourceVersion string, out runtime.Object, ignoreNotFound bool) error {
+	obj, found := s.objects[key]
+	if !found {
+		return storage.NewKeyNotFoundError(key, 0)
+	}
+	reflect.ValueOf(out).Elem().Set(reflect.ValueOf(obj.DeepCopyObject()).Elem())
+	return nil
+}
+
+func (s *memoryStorage) GuaranteedUpdate(ctx context.Context, key string, out runtime.Object, ignoreNotFound bool,
+	preconditions *storage.Preconditions, tryUpdate storage.UpdateFunc, suggestion ...runtime.Object) error {
+	obj, found := s.objects[key]
+	if !found {
+		return storage.NewKeyNotFoundError(key, 0)
+	}
+	updated, _, err := tryUpdate(obj.DeepCopyObject(), storage.ResponseMeta{})
+	if err != nil {
+		return err
+	}
+	if err := s.store(key, updated); err != nil {
+		return err
+	}
+	reflect.ValueOf(out).Elem().Set(reflect.ValueOf(updated.DeepCopyObject()).Elem())
+	return nil
+}
+
+func (s *memoryStorage) List(ctx context.Context, key string, resourceVersion string, p storage.SelectionPredicate,
+	listObj runtime.Object) error {
+	s.lists++
+	keys := []string{}
+	for k := range s.objects {
+		if strings.HasPrefix(k, strings.TrimSuffix(key, "/")+"/") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	// pages of p.Limit objects continue after the key of the last object of the previous page
+	if len(p.Continue) > 0 {
+		keys = keys[sort.SearchStrings(keys, p.Continue+"\x00"):]
+	}
+	continueKey := ""
+	if p.Limit > 0 && int64(len(keys)) > p.Limit {
+		keys = keys[:p.Limit]
+		continueKey = keys[len(keys)-1]
+	}
+	items := []runtime.Object{}
+	for _, k := range keys {
+		items = append(items, s.objects[k].DeepCopyObject())
+	}
+	list, err := meta.ListAccessor(listObj)
+	if err != nil {
+		return err
+	}
+	list.SetContinue(continueKey)
+	return meta.SetList(listObj, items)
+}
+
+func (s *memoryStorage) Versioner() storage.Versioner {
+	return etcd3.APIObjectVersioner{}
+}
+
+// memoryStorageGetter decorates the stores with a memoryStorage
+type memoryStorageGetter struct {
+	storage *memoryStorage
+}
+
+func (g memoryStorageGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	return generic.RESTOptions{
+		StorageConfig: &storagebackend.Config{},
+		Decorator: func(*storagebackend.Config, string, func(runtime.Object) (string, error), func() runtime.Object,
+			func() runtime.Object, storage.AttrFunc, storage.IndexerFuncs, *cache.Indexers) (storage.Interface, factory.DestroyFunc, error) {
+			return g.storage, func() {}, nil
+		},
+		ResourcePrefix: resource.Group + "/" + resource.Resource,
+	}, nil
+}
+
+// TestTestClient checks the fixtures created through the generated test clients are read back unchanged
+func TestTestClient(t *testing.T) {
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	miskatonic.MiskatonicUniversityStorage.Build("miskatonic.k8s.io", getter)
+	kingsport.KingsportFestivalStorage.Build("kingsport.k8s.io", getter)
+
+	obj, err := miskatonic.LoadFixture("v1beta1", "University")
+	if err != nil {
+		t.Fatal(err)
+	}
+	university := obj.(*miskatonicv1beta1.University)
+	universities := miskatonictestclient.Universities("arkham")
+	created, err := universities.Create(university)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Namespace != "arkham" || created.APIVersion != "miskatonic.k8s.io/v1beta1" || created.Kind != "University" {
+		t.Errorf("expected a v1beta1 University in the arkham namespace, got %+v", created)
+	}
+	read, err := universities.Get(university.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, created) {
+		t.Errorf("expected the University read to be the one created\n%+v\ngot\n%+v", created, read)
+	}
+	if !reflect.DeepEqual(read.Spec, university.Spec) {
+		t.Errorf("expected the spec of the fixture %+v, got %+v", university.Spec, read.Spec)
+	}
+	if _, err := miskatonictestclient.Universities("innsmouth").Get(university.Name); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the University to not be found in another namespace, got %v", err)
+	}
+
+	obj, err = kingsport.LoadFixture("v1", "Festival")
+	if err != nil {
+		t.Fatal(err)
+	}
+	festival := obj.(*kingsportv1.Festival)
+	if _, err := kingsporttestclient.Festivals().Create(festival); err != nil {
+		t.Fatal(err)
+	}
+	readFestival, err := kingsporttestclient.Festivals().Get(festival.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(readFestival.Spec, festival.Spec) {
+		t.Errorf("expected the spec of the fixture %+v, got %+v", festival.Spec, readFestival.Spec)
+	}
+}
+
+// TestTestClientForEach checks ForEach visits the objects of every page of the lists
+func TestTestClientForEach(t *testing.T) {
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	miskatonic.MiskatonicUniversityStorage.Build("miskatonic.k8s.io", getter)
+
+	obj, err := miskatonic.LoadFixture("v1beta1", "University")
+	if err != nil {
+		t.Fatal(err)
+	}
+	universities := miskatonictestclient.Universities("arkham")
+	universities.PageSize = 2
+	expected := []string{"brown", "miskatonic", "yale"}
+	for _, name := range expected {
+		university := obj.(*miskatonicv1beta1.University).DeepCopy()
+		university.Name = name
+		if _, err := universities.Create(university); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	visited := []string{}
+	if err := universities.ForEach(context.Background(), func(university *miskatonicv1beta1.University) error {
+		visited = append(visited, university.Name)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("expected ForEach to visit %v, got %v", expected, visited)
+	}
+	if getter.storage.lists != 2 {
+		t.Errorf("expected the universities to be listed in 2 pages, got %d", getter.storage.lists)
+	}
+}
+
+// TestTestClientUpdateStatus checks UpdateStatus goes through the status endpoint, which only updates the status
+func TestTestClientUpdateStatus(t *testing.T) {
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	universities := miskatonictestclient.Universities("arkham")
+	if _, err := universities.UpdateStatus(&miskatonicv1beta1.University{}); err == nil {
+		t.Error("expected UpdateStatus to fail before the apiserver is built")
+	}
+	apis.GetMiskatonicAPIBuilder().Build(getter)
+
+	obj, err := miskatonic.LoadFixture("v1beta1", "University")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := universities.Create(obj.(*miskatonicv1beta1.University))
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := created.DeepCopy()
+	update.Status.EnrolledStudents = []string{"wilbur"}
+	update.Spec.FacultySize = created.Spec.FacultySize + 1
+	if _, err := universities.UpdateStatus(update); err != nil {
+		t.Fatal(err)
+	}
+	read, err := universities.Get(created.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.Status.EnrolledStudents, update.Status.EnrolledStudents) {
+		t.Errorf("expected the enrolled students %v, got %v", update.Status.EnrolledStudents, read.Status.EnrolledStudents)
+	}
+	if read.Spec.FacultySize != created.Spec.FacultySize {
+		t.Errorf("expected the status endpoint to leave the faculty size %d, got %d", created.Spec.FacultySize, read.Spec.FacultySize)
+	}
+}
+
+// TestTestClientScale checks GetScale and UpdateScale read and update the scale subresource
+func TestTestClientScale(t *testing.T) {
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	apis.GetInnsmouthAPIBuilder().Build(getter)
+
+	deepOnes := innsmouthtestclient.DeepOnes("innsmouth")
+	deepOne := &innsmouthv1.DeepOne{}
+	deepOne.Name = "obed"
+	deepOne.Spec.FishRequired = 3
+	if _, err := deepOnes.Create(deepOne); err != nil {
+		t.Fatal(err)
+	}
+	scale, err := deepOnes.GetScale(deepOne.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scale.Replicas != 3 {
+		t.Errorf("expected 3 replicas, got %d", scale.Replicas)
+	}
+	scale.Replicas = 5
+	if _, err := deepOnes.UpdateScale(scale); err != nil {
+		t.Fatal(err)
+	}
+	read, err := deepOnes.Get(deepOne.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Spec.FishRequired != 5 {
+		t.Errorf("expected the scale to update the fish required to 5, got %d", read.Spec.FishRequired)
+	}
+}
+
+// TestTestClientJSONPatch checks JSONPatch applies a patch written against v1beta1 to the paths of v1beta1: the
+// dean annotation of universities is named miskatonic.k8s.io/dean-name in v1beta1 and miskatonic.k8s.io/dean in
+// the stored internal version
+func TestTestClientJSONPatch(t *testing.T) {
+	getter := memoryStorageGetter{&memoryStorage{objects: map[string]runtime.Object{}}}
+	miskatonic.MiskatonicUniversityStorage.Build("miskatonic.k8s.io", getter)
+
+	obj, err := miskatonic.LoadFixture("v1beta1", "University")
+	if err != nil {
+		t.Fatal(err)
+	}
+	university := obj.(*miskatonicv1beta1.University)
+	university.Annotations = map[string]string{miskatonicv1beta1.DeanAnnotation: "armitage"}
+	universities := miskatonictestclient.Universities("arkham")
+	if _, err := universities.Create(university); err != nil {
+		t.Fatal(err)
+	}
+
+	patched, err := universities.JSONPatch(university.Name, []byte(`[
+		{"op": "replace", "path": "/metadata/annotations/miskatonic.k8s.io~1dean-name", "value": "wilmarth"},
+		{"op": "replace", "path": "/spec/faculty_size", "value": 42}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dean := patched.Annotations[miskatonicv1beta1.DeanAnnotation]; dean != "wilmarth" || patched.Spec.FacultySize != 42 {
+		t.Errorf("expected the patched university to have the dean wilmarth and 42 faculty, got %q and %d", dean, patched.Spec.FacultySize)
+	}
+	for _, stored := range getter.storage.objects {
+		if dean := stored.(*miskatonic.University).Annotations[miskatonicv1beta1.InternalDeanAnnotation]; dean != "wilmarth" {
+			t.Errorf("expected the stored university to have the dean wilmarth, got %q", dean)
+		}
+	}
+
+	// The paths of the internal version are not those of v1beta1
+	if _, err := universities.JSONPatch(university.Name, []byte(`[
+		{"op": "test", "path": "/metadata/annotations/miskatonic.k8s.io~1dean", "value": "wilmarth"}
+	]`)); err == nil {
+		t.Error("expected a patch testing the internal dean annotation to fail")
+	}
+}
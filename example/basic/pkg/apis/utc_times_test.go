@@ -0,0 +1,64 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/kingsport"
+)
+
+// TestUTCTimes checks the strategy of Festivals, which have a +resource:utcTimes comment, converts the start
+// times of the performers to UTC on create and update, keeping the instants
+func TestUTCTimes(t *testing.T) {
+	strategy := kingsport.KingsportFestivalStorage.StorageBuilder
+	arkham := time.FixedZone("arkham", -5*60*60)
+	start := metav1.NewTime(time.Date(1928, time.April, 1, 21, 0, 0, 0, arkham))
+
+	festival := &kingsport.Festival{
+		ObjectMeta: metav1.ObjectMeta{Name: "yule"},
+		Spec: kingsport.FestivalSpec{Invited: 300, Performers: []kingsport.FestivalPerformer{
+			{Name: "flutes", Start: start.DeepCopy()},
+			{Name: "chants"},
+		}},
+	}
+	if err := rest.BeforeCreate(strategy, context.TODO(), festival); err != nil {
+		t.Fatal(err)
+	}
+	created := festival.Spec.Performers[0].Start
+	if created.Location() != time.UTC || !created.Equal(&start) {
+		t.Errorf("expected the start %v to be converted to UTC on create, got %v", start, created)
+	}
+	if festival.Spec.Performers[1].Start != nil {
+		t.Errorf("expected the unset start to stay unset, got %v", festival.Spec.Performers[1].Start)
+	}
+
+	updated := festival.DeepCopy()
+	updated.ResourceVersion = "1"
+	updated.Spec.Performers[1].Start = start.DeepCopy()
+	if err := rest.BeforeUpdate(strategy, context.TODO(), updated, festival); err != nil {
+		t.Fatal(err)
+	}
+	if s := updated.Spec.Performers[1].Start; s.Location() != time.UTC || !s.Equal(&start) {
+		t.Errorf("expected the start %v to be converted to UTC on update, got %v", start, s)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis"
+	"sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/innsmouth"
+)
+
+// TestVersionPriority checks the innsmouth versions are ordered GA before beta before alpha, in the scheme, the
+// builder of the group and the discovery of the group
+func TestVersionPriority(t *testing.T) {
+	expected := []schema.GroupVersion{
+		{Group: "innsmouth.k8s.io", Version: "v1"},
+		{Group: "innsmouth.k8s.io", Version: "v1beta1"},
+		{Group: "innsmouth.k8s.io", Version: "v1alpha1"},
+	}
+	if !reflect.DeepEqual(innsmouth.VersionPriority, expected) {
+		t.Errorf("expected the version priority %v, got %v", expected, innsmouth.VersionPriority)
+	}
+	if prioritized := builders.Scheme.PrioritizedVersionsForGroup("innsmouth.k8s.io"); !reflect.DeepEqual(prioritized, expected) {
+		t.Errorf("expected the scheme to prioritize %v, got %v", expected, prioritized)
+	}
+
+	group := apis.GetInnsmouthAPIBuilder()
+	if order := group.GetVersionPreferenceOrder(); !reflect.DeepEqual(order, []string{"v1", "v1beta1", "v1alpha1"}) {
+		t.Errorf("expected the builder to prefer v1, v1beta1, v1alpha1, got %v", order)
+	}
+	if discovered := group.Build(noopRESTOptionsGetter{}).PrioritizedVersions; !reflect.DeepEqual(discovered, expected) {
+		t.Errorf("expected discovery to present %v, got %v", expected, discovered)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+// TestWatchBookmark checks the UniversityWatchBookmark tracks the newest resourceVersion of the events of a watch,
+// including the BOOKMARK events which only carry the resourceVersion of their object
+func TestWatchBookmark(t *testing.T) {
+	w := watch.NewFakeWithChanSize(4, false)
+	w.Add(&miskatonicv1beta1.University{ObjectMeta: metav1.ObjectMeta{Name: "miskatonic", ResourceVersion: "5"}})
+	w.Action(watch.Bookmark, &miskatonicv1beta1.University{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "9"}})
+	w.Action(watch.Bookmark, &miskatonicv1beta1.University{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "7"}})
+	w.Error(&metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonExpired})
+	w.Stop()
+
+	bookmark := &miskatonicv1beta1.UniversityWatchBookmark{}
+	expected := []struct {
+		eventType       watch.EventType
+		newer           bool
+		resourceVersion string
+	}{
+		{watch.Added, true, "5"},
+		{watch.Bookmark, true, "9"},
+		{watch.Bookmark, false, "9"},
+		{watch.Error, false, "9"},
+	}
+	for _, e := range expected {
+		event, ok := <-w.ResultChan()
+		if !ok {
+			t.Fatalf("expected a %s event, the watch is closed", e.eventType)
+		}
+		if event.Type != e.eventType {
+			t.Fatalf("expected a %s event, got %+v", e.eventType, event)
+		}
+		if event.Type == watch.Bookmark {
+			if university := event.Object.(*miskatonicv1beta1.University); len(university.ResourceVersion) == 0 {
+				t.Errorf("expected the BOOKMARK event to carry a resourceVersion, got %+v", university)
+			}
+		}
+		if newer := bookmark.Observe(event); newer != e.newer {
+			t.Errorf("expected the %s event to be observed as newer %v, got %v", e.eventType, e.newer, newer)
+		}
+		if bookmark.ResourceVersion != e.resourceVersion {
+			t.Errorf("expected the resourceVersion %s after the %s event, got %s", e.resourceVersion, e.eventType,
+				bookmark.ResourceVersion)
+		}
+	}
+}
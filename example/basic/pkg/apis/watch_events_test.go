@@ -0,0 +1,57 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	miskatonicv1beta1 "sigs.k8s.io/apiserver-builder-alpha/example/basic/pkg/apis/miskatonic/v1beta1"
+)
+
+func TestWatchEvents(t *testing.T) {
+	w := watch.NewFake()
+	events := miskatonicv1beta1.WatchUniversityEvents(w)
+
+	university := &miskatonicv1beta1.University{ObjectMeta: metav1.ObjectMeta{Name: "miskatonic"}}
+	go w.Add(university)
+	if event := <-events; event.Type != watch.Added || event.Object != university || event.Status != nil {
+		t.Errorf("expected an Added event of the university, got %+v", event)
+	}
+
+	status := &metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonExpired}
+	go w.Error(status)
+	if event := <-events; event.Type != watch.Error || event.Object != nil || event.Status != status {
+		t.Errorf("expected an Error event of the status, got %+v", event)
+	}
+
+	go w.Modify(&miskatonicv1beta1.Student{})
+	event := <-events
+	if event.Type != watch.Error || event.Object != nil || event.Status == nil {
+		t.Fatalf("expected an Error event for the student, got %+v", event)
+	}
+	if event.Status.Code != 500 {
+		t.Errorf("expected an internal error status for the student, got %+v", event.Status)
+	}
+
+	w.Stop()
+	if event, ok := <-events; ok {
+		t.Errorf("expected the events to be closed with the watch, got %+v", event)
+	}
+}
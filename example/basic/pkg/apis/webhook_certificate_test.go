@@ -0,0 +1,101 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apis_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/cert"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+)
+
+// TestWebhookCertificateRotation checks a webhook serving the WebhookCertificate of a Secret serves the
+// certificate of the updated Secret once the Secret is updated, without restarting
+func TestWebhookCertificateRotation(t *testing.T) {
+	secret := func(host string) *corev1.Secret {
+		crt, key, err := cert.GenerateSelfSignedCertKey(host, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "basic", Name: "basic-conversion-webhook-tls"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key},
+		}
+	}
+	client := fake.NewSimpleClientset(secret("before.basic.svc"))
+
+	webhookCert := builders.NewWebhookCertificate(client, "basic", "basic-conversion-webhook-tls")
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go webhookCert.Run(stopCh)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{GetCertificate: webhookCert.GetCertificate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	// served returns the dns names of the certificate served by the webhook
+	served := func() []string {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", listener.Addr().String(),
+			&tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return nil
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].DNSNames
+	}
+	waitForServed := func(host string) {
+		err := wait.PollImmediate(10*time.Millisecond, 10*time.Second, func() (bool, error) {
+			for _, name := range served() {
+				if name == host {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+		if err != nil {
+			t.Fatalf("expected the certificate of %s to be served, got the certificate of %v", host, served())
+		}
+	}
+
+	waitForServed("before.basic.svc")
+	if _, err := client.CoreV1().Secrets("basic").Update(
+		context.TODO(), secret("after.basic.svc"), metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForServed("after.basic.svc")
+}
@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/controller/result"
 )
 
 /**
@@ -85,11 +86,11 @@ func (r *ReconcileFestival) Reconcile(request reconcile.Request) (reconcile.Resu
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
-			return reconcile.Result{}, nil
+			return result.Done()
 		}
 		// Error reading the object - requeue the request.
-		return reconcile.Result{}, err
+		return result.Error(err)
 	}
 
-	return reconcile.Result{}, nil
+	return result.Done()
 }
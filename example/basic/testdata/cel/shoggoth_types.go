@@ -0,0 +1,51 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// +resource:path=shoggoths
+// +kubebuilder:validation:XValidation:rule="!has(self.status) || !has(self.status.master) || self.status.master != self.metadata.name",message="a shoggoth cannot serve itself"
+// Shoggoth defines a servant of the deep ones
+type Shoggoth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ShoggothSpec   `json:"spec,omitempty"`
+	Status ShoggothStatus `json:"status,omitempty"`
+}
+
+// ShoggothSpec defines the desired state of Shoggoth
+type ShoggothSpec struct {
+	// Eyes is the number of eyes the Shoggoth forms
+	// +kubebuilder:validation:XValidation:rule="self <=",message="a shoggoth forms at most 1000 eyes"
+	Eyes int32 `json:"eyes,omitempty"`
+	// Tentacles is the number of tentacles the Shoggoth forms, added in v1beta1
+	Tentacles int32 `json:"tentacles,omitempty"`
+}
+
+// ShoggothStatus defines the observed state of Shoggoth
+type ShoggothStatus struct {
+	// Master is the name of the DeepOne the Shoggoth serves
+	Master string `json:"master,omitempty"`
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Festival
+// +k8s:openapi-gen=true
+// +resource:path=festivals,strategy=FestivalStrategy,shortname=fs
+// +index=spec.year
+// +resource:storageMediaType=application/json
+// +resource:customMarshal
+// +resource:oneOf=spec.invited,spec.guestList
+// +admission:validating=ValidateFestivalCreate
+// +removedField=spec.patron
+// +metrics:label=spec.venue.capacity
+type Festival struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FestivalSpec   `json:"spec,omitempty"`
+	Status FestivalStatus `json:"status,omitempty"`
+}
+
+// FestivalSpec defines the desired state of Festival
+type FestivalSpec struct {
+	// Year when the festival was held, may be negative (BC)
+	Year int `json:"year,omitempty"`
+	// Invited holds the number of invited attendees, exclusive with guestList
+	Invited uint `json:"invited,omitempty"`
+	// GuestList holds the names of the invited attendees, exclusive with invited
+	GuestList []string `json:"guestList,omitempty"`
+	// Venue is where the festival is held
+	// +optional
+	Venue *FestivalVenue `json:"venue,omitempty"`
+	// Performers holds the acts of the festival, patches merge them by name
+	// +listType=map
+	// +listMapKey=name
+	// +patchStrategy=merge
+	// +patchMergeKey=name
+	// +optional
+	Performers []FestivalPerformer `json:"performers,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+	// Patron sponsoring the festival, removed as festivals are no longer sponsored
+	// +optional
+	Patron string `json:"patron,omitempty"`
+}
+
+// FestivalPerformer is an act performing at a festival
+type FestivalPerformer struct {
+	// Name of the act, unique within the festival
+	Name string `json:"name"`
+	// Stage where the act performs
+	Stage string `json:"stage,omitempty"`
+}
+
+// FestivalVenue is a union of the hall or the open air location holding the festival.  A hall is
+// encoded as its name, e.g. "Town Hall", and an open air location as its coordinates.
+type FestivalVenue struct {
+	Hall    string
+	OpenAir *FestivalLocation
+}
+
+// FestivalLocation is the location of an open air festival
+type FestivalLocation struct {
+	Latitude  string `json:"latitude"`
+	Longitude string `json:"longitude"`
+}
+
+func (v FestivalVenue) MarshalJSON() ([]byte, error) {
+	if v.OpenAir != nil {
+		return json.Marshal(v.OpenAir)
+	}
+	return json.Marshal(v.Hall)
+}
+
+func (v *FestivalVenue) UnmarshalJSON(data []byte) error {
+	*v = FestivalVenue{}
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &v.Hall)
+	}
+	v.OpenAir = &FestivalLocation{}
+	return json.Unmarshal(data, v.OpenAir)
+}
+
+// FestivalStatus defines the observed state of Festival
+type FestivalStatus struct {
+	// Attended holds the actual number of attendees
+	Attended uint `json:"attended,omitempty"`
+}
+
@@ -3,8 +3,11 @@ module sigs.k8s.io/apiserver-builder-alpha
 go 1.13
 
 require (
+	github.com/emicklei/go-restful v2.9.5+incompatible
+	github.com/evanphx/json-patch v4.5.0+incompatible
 	github.com/go-logr/zapr v0.1.1 // indirect
-	github.com/golang/protobuf v1.3.4 // indirect
+	github.com/golang/protobuf v1.3.4
+	github.com/google/cel-go v0.4.1
 	github.com/google/go-cmp v0.3.1 // indirect
 	github.com/gorilla/websocket v1.4.1 // indirect
 	github.com/grpc-ecosystem/go-grpc-middleware v1.0.1-0.20190723091251-e0797f438f94 // indirect
@@ -17,12 +20,14 @@ require (
 	github.com/spf13/cobra v0.0.5
 	github.com/spf13/pflag v1.0.5
 	github.com/tmc/grpc-websocket-proxy v0.0.0-20190109142713-0ad062ec5ee5 // indirect
+	golang.org/x/tools v0.0.0-20190920225731-5eefd052ad72
 	golang.org/x/xerrors v0.0.0-20191011141410-1b5146add898 // indirect
 	k8s.io/api v0.18.4
 	k8s.io/apimachinery v0.18.4
 	k8s.io/apiserver v0.18.4
 	k8s.io/client-go v0.18.4
 	k8s.io/code-generator v0.18.4
+	k8s.io/component-base v0.18.4
 	k8s.io/gengo v0.0.0-20200114144118-36b2048a9120
 	k8s.io/klog v1.0.0
 	k8s.io/kube-aggregator v0.18.4
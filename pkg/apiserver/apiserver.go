@@ -17,6 +17,8 @@ limitations under the License.
 package apiserver
 
 import (
+	"path"
+
 	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -102,6 +104,9 @@ func (c completedConfig) New() (*Server, error) {
 	for hookName, hook := range c.PostStartHooks {
 		genericServer.AddPostStartHookOrDie(hookName, hook)
 	}
+	if err := genericServer.AddHealthChecks(builders.HealthzChecks...); err != nil {
+		return nil, err
+	}
 
 	s := &Server{
 		GenericAPIServer: genericServer,
@@ -112,6 +117,14 @@ func (c completedConfig) New() (*Server, error) {
 		if err := s.GenericAPIServer.InstallAPIGroup(group); err != nil {
 			return nil, err
 		}
+		builder.AddHandlers(s.GenericAPIServer.Handler.GoRestfulContainer)
+	}
+	// The custom metrics API is installed as a separate group, served by the aggregator through its own APIService
+	if len(builders.CustomMetrics) > 0 {
+		root := path.Join("/apis", builders.CustomMetricsGroupVersion.String())
+		handler := builders.NewCustomMetricsHandler(builders.CustomMetrics...)
+		s.GenericAPIServer.Handler.NonGoRestfulMux.Handle(root, handler)
+		s.GenericAPIServer.Handler.NonGoRestfulMux.HandlePrefix(root+"/", handler)
 	}
 	return s, nil
 }
@@ -0,0 +1,55 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// NewSelfSubjectAccessReview returns a SelfSubjectAccessReview of whether the user may verb the object of resource
+// named name in namespace.  An empty name reviews the access to all the objects of the namespace, an empty
+// namespace the access to the objects of all namespaces or to cluster scoped objects.
+func NewSelfSubjectAccessReview(
+	resource schema.GroupVersionResource, verb, namespace, name string) *authorizationv1.SelfSubjectAccessReview {
+	return &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     resource.Group,
+				Version:   resource.Version,
+				Resource:  resource.Resource,
+				Name:      name,
+			},
+		},
+	}
+}
+
+// ReviewSelfSubjectAccess creates review through client and returns true if the access it reviews is allowed
+func ReviewSelfSubjectAccess(ctx context.Context, client authorizationv1client.SelfSubjectAccessReviewsGetter,
+	review *authorizationv1.SelfSubjectAccessReview) (bool, error) {
+	result, err := client.SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+// AnnotationConversionFunc migrates the annotations of a resource converted to the internal version if
+// toInternal is true, and from the internal version otherwise
+type AnnotationConversionFunc func(annotations map[string]string, toInternal bool)
+
+// ConvertAnnotations returns a copy of the annotations migrated by migrate.  The generated conversions
+// share the annotations of the converted object, so they are copied rather than migrated in place.
+// Called by the conversions generated for resources with "+annotationConversion=<func>" comments.
+func ConvertAnnotations(annotations map[string]string, toInternal bool, migrate AnnotationConversionFunc) map[string]string {
+	converted := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		converted[k] = v
+	}
+	migrate(converted, toInternal)
+	if len(converted) == 0 && annotations == nil {
+		return nil
+	}
+	return converted
+}
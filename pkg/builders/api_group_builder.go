@@ -17,8 +17,16 @@ limitations under the License.
 package builders
 
 import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/registry/generic"
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
@@ -33,6 +41,45 @@ type APIGroupBuilder struct {
 	Name            string
 	ImportPrefix    string
 	RootScopedKinds []string
+
+	// ResourceOptions decides which resources of the group are registered.  All resources are
+	// registered if nil.
+	ResourceOptions ResourceOptions
+
+	// DisabledSubresources are the "<resource>/<subresource>" paths of the subresources of the group that
+	// are not registered, e.g. "foos/status".  The resources themselves are still registered.
+	DisabledSubresources []string
+
+	// StorageMediaTypes are the media types of the resources stored in etcd with a media type other
+	// than the --storage-media-type of the apiserver
+	StorageMediaTypes map[schema.GroupResource]string
+
+	// AdmissionPlugins are the admission plugins scoped to the resources of the group, keyed by plugin name
+	AdmissionPlugins map[string]admission.Interface
+
+	// Handlers are served under each version of the group, keyed by their path relative to the version,
+	// e.g. "features" is served at /apis/<group>/<version>/features
+	Handlers map[string]http.Handler
+
+	// OpenAPIExamples are the json examples of the types of the group, keyed by the name of their OpenAPI
+	// definition
+	OpenAPIExamples map[string]string
+
+	// OpenAPIUIDescriptors are the x-descriptors of the fields of the types of the group, keyed by the name of
+	// their OpenAPI definition and the json name of the field
+	OpenAPIUIDescriptors map[string]map[string][]string
+
+	// PreviousName is the name the group was renamed from.  The resources of the group are stored under the
+	// etcd keys of the previous name.
+	PreviousName string
+}
+
+// ResourceOptions enables and disables serving the resources of an api group through flags
+type ResourceOptions interface {
+	// AddFlags adds the flags enabling and disabling the resources to fs
+	AddFlags(fs *pflag.FlagSet)
+	// DisabledResources returns the names of the resources that should not be registered
+	DisabledResources() []string
 }
 
 func NewApiGroupBuilder(name, prefix string) *APIGroupBuilder {
@@ -58,6 +105,71 @@ func (g *APIGroupBuilder) WithRootScopedKinds(kinds ...string) *APIGroupBuilder
 	return g
 }
 
+func (g *APIGroupBuilder) WithResourceOptions(options ResourceOptions) *APIGroupBuilder {
+	g.ResourceOptions = options
+	return g
+}
+
+func (g *APIGroupBuilder) WithDisabledSubresources(paths ...string) *APIGroupBuilder {
+	g.DisabledSubresources = append(g.DisabledSubresources, paths...)
+	return g
+}
+
+func (g *APIGroupBuilder) WithStorageMediaTypes(mediaTypes map[schema.GroupResource]string) *APIGroupBuilder {
+	g.StorageMediaTypes = mediaTypes
+	return g
+}
+
+func (g *APIGroupBuilder) WithAdmissionPlugins(plugins map[string]admission.Interface) *APIGroupBuilder {
+	g.AdmissionPlugins = plugins
+	return g
+}
+
+func (g *APIGroupBuilder) WithHandlers(handlers map[string]http.Handler) *APIGroupBuilder {
+	g.Handlers = handlers
+	return g
+}
+
+func (g *APIGroupBuilder) WithOpenAPIExamples(examples map[string]string) *APIGroupBuilder {
+	g.OpenAPIExamples = examples
+	return g
+}
+
+func (g *APIGroupBuilder) WithOpenAPIUIDescriptors(descriptors map[string]map[string][]string) *APIGroupBuilder {
+	g.OpenAPIUIDescriptors = descriptors
+	return g
+}
+
+func (g *APIGroupBuilder) WithPreviousName(name string) *APIGroupBuilder {
+	g.PreviousName = name
+	return g
+}
+
+// AddHandlers adds a GET route serving each of the Handlers to the web service of each version of the group
+// in container.  The group must be installed in container beforehand.
+func (g *APIGroupBuilder) AddHandlers(container *restful.Container) {
+	if len(g.Handlers) == 0 {
+		return
+	}
+	for _, ws := range container.RegisteredWebServices() {
+		for _, v := range g.Versions {
+			if ws.RootPath() != path.Join("/apis", v.GroupVersion.Group, v.GroupVersion.Version) {
+				continue
+			}
+			for p, handler := range g.Handlers {
+				handler := handler
+				ws.Route(ws.GET("/" + p).
+					To(func(req *restful.Request, resp *restful.Response) {
+						handler.ServeHTTP(resp.ResponseWriter, req.Request)
+					}).
+					Produces(restful.MIME_JSON).
+					Operation("get" + strings.Title(strings.Split(v.GroupVersion.Group, ".")[0]) +
+						strings.Title(v.GroupVersion.Version) + strings.Title(p)))
+			}
+		}
+	}
+}
+
 // GetVersionPreferenceOrder returns the preferred ordering of versions for this api group
 func (g *APIGroupBuilder) GetVersionPreferenceOrder() []string {
 	order := []string{}
@@ -79,9 +191,15 @@ func (g *APIGroupBuilder) registerEndpoints(
 	optionsGetter generic.RESTOptionsGetter,
 	registry map[string]map[string]rest.Storage) {
 
+	disabled := sets.NewString()
+	if g.ResourceOptions != nil {
+		disabled.Insert(g.ResourceOptions.DisabledResources()...)
+	}
+	disabled.Insert(g.DisabledSubresources...)
+
 	// Register the endpoints for each version
 	for _, v := range g.Versions {
-		v.registerEndpoints(optionsGetter, registry)
+		v.registerEndpoints(optionsGetter, registry, disabled)
 	}
 }
 
@@ -103,6 +221,9 @@ func (g *APIGroupBuilder) Build(optionsGetter generic.RESTOptionsGetter) *generi
 		ParameterCodec,
 		Codecs)
 
+	if len(g.PreviousName) > 0 {
+		optionsGetter = &previousGroupRESTOptionsGetter{optionsGetter, g.PreviousName}
+	}
 	g.registerEndpoints(optionsGetter, i.VersionedResourcesStorageMap)
 
 	return &i
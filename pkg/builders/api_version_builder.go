@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/registry/generic"
 	"k8s.io/apiserver/pkg/registry/rest"
 )
@@ -53,12 +54,21 @@ func (s *VersionedApiBuilder) WithResources(resourceBuilders ...*versionedResour
 // group is the group to register the resources under
 // optionsGetter is the RESTOptionsGetter provided by a server.Config
 // registry is the server.APIGroupInfo VersionedResourcesStorageMap used to register REST endpoints
+// disabled is the set of resources whose endpoints, including subresources, are not registered, and of the
+// "<resource>/<subresource>" paths of the subresources that are not registered
 func (s *VersionedApiBuilder) registerEndpoints(
 	optionsGetter generic.RESTOptionsGetter,
-	registry map[string]map[string]rest.Storage) {
+	registry map[string]map[string]rest.Storage,
+	disabled sets.String) {
 
 	// Register the endpoints for each kind
 	for _, k := range s.Kinds {
+		if disabled.Has(k.Unversioned.GetName()) {
+			continue
+		}
+		if len(k.Unversioned.GetPath()) > 0 && disabled.Has(k.Unversioned.GetName()+"/"+k.Unversioned.GetPath()) {
+			continue
+		}
 		if _, found := registry[s.GroupVersion.Version]; !found {
 			// Initialize the version if missing
 			registry[s.GroupVersion.Version] = map[string]rest.Storage{}
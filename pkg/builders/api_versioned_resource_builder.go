@@ -20,9 +20,12 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/apiserver/pkg/registry/generic"
 	"k8s.io/apiserver/pkg/registry/generic/registry"
 	"k8s.io/apiserver/pkg/registry/rest"
@@ -30,6 +33,25 @@ import (
 
 type NewRESTFunc func(getter generic.RESTOptionsGetter) rest.Storage
 
+// StoreOption overrides a default of the registry.Store built for a resource
+type StoreOption func(store *registry.Store)
+
+// WithNewFunc overrides the function returning empty UNVERSIONED instances of the resource stored by the
+// registry.Store, e.g. to pre-populate fields of the objects it creates
+func WithNewFunc(newFunc func() runtime.Object) StoreOption {
+	return func(store *registry.Store) {
+		store.NewFunc = newFunc
+	}
+}
+
+// WithNewListFunc overrides the function returning empty UNVERSIONED instances of the resource List
+// stored by the registry.Store
+func WithNewListFunc(newListFunc func() runtime.Object) StoreOption {
+	return func(store *registry.Store) {
+		store.NewListFunc = newListFunc
+	}
+}
+
 //
 // Versioned Kind Builder builds a versioned resource using unversioned strategy
 //
@@ -40,17 +62,19 @@ type NewRESTFunc func(getter generic.RESTOptionsGetter) rest.Storage
 // new - function for creating new empty VERSIONED instances - e.g. func() runtime.Object { return &Deployment{} }
 // newList - function for creating an empty list of VERSIONED instances - e.g. func() runtime.Object { return &DeploymentList{} }
 // storeBuilder - builder for creating the store
+// storeOptions - overrides of the store defaults - e.g. WithNewFunc(func() runtime.Object { return &Deployment{} })
 func NewApiResource(
 	unversionedBuilder UnversionedResourceBuilder,
 	new, newList func() runtime.Object,
-	storeBuilder StorageBuilder) *versionedResourceBuilder {
+	storeBuilder StorageBuilder,
+	storeOptions ...StoreOption) *versionedResourceBuilder {
 
 	if storeBuilder == nil {
 		storeBuilder = StorageStrategySingleton
 	}
 
 	return &versionedResourceBuilder{
-		unversionedBuilder, new, newList, storeBuilder, nil, nil,
+		unversionedBuilder, new, newList, storeBuilder, storeOptions, nil, nil, nil,
 	}
 }
 
@@ -64,7 +88,7 @@ func NewApiResourceWithStorage(
 	new, newList func() runtime.Object,
 	RESTFunc NewRESTFunc) *versionedResourceBuilder {
 	v := &versionedResourceBuilder{
-		unversionedBuilder, new, newList, nil, RESTFunc, nil,
+		unversionedBuilder, new, newList, nil, nil, RESTFunc, nil, nil,
 	}
 	if new == nil {
 		panic(fmt.Errorf("Cannot call NewApiResourceWithStorage with nil new function."))
@@ -87,10 +111,23 @@ type versionedResourceBuilder struct {
 	// StorageBuilder is used to modify the default storage, mutually exclusive with RESTFunc
 	StorageBuilder StorageBuilder
 
+	// StoreOptions override the defaults of the store, applied after the StorageBuilder
+	StoreOptions []StoreOption
+
 	// RESTFunc returns a rest.Storage implementation, mutually exclusive with StorageBuilder
 	RESTFunc NewRESTFunc
 
 	Storage rest.StandardStorage
+
+	// restStorage is the storage returned by the RESTFunc when the endpoints were registered
+	restStorage rest.Storage
+}
+
+// WithStoreOptions adds options overriding the defaults of the store, e.g. of the generated resources
+// before the apiserver is started
+func (b *versionedResourceBuilder) WithStoreOptions(storeOptions ...StoreOption) *versionedResourceBuilder {
+	b.StoreOptions = append(b.StoreOptions, storeOptions...)
+	return b
 }
 
 func (b *versionedResourceBuilder) New() runtime.Object {
@@ -115,6 +152,29 @@ func (s StorageWrapper) Create(ctx context.Context, obj runtime.Object, createVa
 	return s.Store.Create(ctx, obj, createValidation, options)
 }
 
+// List lists the objects with the ResourceVersionMatch of ctx, see ApplyResourceVersionMatch
+func (s StorageWrapper) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+	if match := ResourceVersionMatchFrom(ctx); len(match) > 0 {
+		if options == nil {
+			options = &metainternalversion.ListOptions{}
+		} else {
+			options = options.DeepCopy()
+		}
+		if err := ApplyResourceVersionMatch(options, match); err != nil {
+			return nil, err
+		}
+	}
+	return s.Store.List(ctx, options)
+}
+
+// Watch rejects the watches with a ResourceVersionMatch, which only applies to lists
+func (s StorageWrapper) Watch(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
+	if match := ResourceVersionMatchFrom(ctx); len(match) > 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("resourceVersionMatch %q is forbidden for watch", match))
+	}
+	return s.Store.Watch(ctx, options)
+}
+
 func (b *versionedResourceBuilder) Build(
 	group string,
 	optionsGetter generic.RESTOptionsGetter) rest.StandardStorage {
@@ -148,6 +208,9 @@ func (b *versionedResourceBuilder) Build(
 		// Allow overriding the storage defaults
 		b.StorageBuilder.Build(b.StorageBuilder, storeWithShortcuts.StorageWrapper, options)
 	}
+	for _, o := range b.StoreOptions {
+		o(&store.Store)
+	}
 
 	if err := storeWithShortcuts.CompleteWithOptions(options); err != nil {
 		panic(err) // TODO: Propagate error up
@@ -162,6 +225,15 @@ func (b *versionedResourceBuilder) GetStandardStorage() rest.StandardStorage {
 	return b.Storage
 }
 
+// GetStorage returns the storage built for the resource by Build, or by the RESTFunc when the endpoints
+// were registered, e.g. the storage of a subresource.  Returns nil until then.
+func (b *versionedResourceBuilder) GetStorage() rest.Storage {
+	if b.RESTFunc != nil {
+		return b.restStorage
+	}
+	return b.Storage
+}
+
 // getGroupResource returns the GroupResource for this Resource and the provided Group
 // group is the group the resource belongs to
 func (b *versionedResourceBuilder) getGroupResource(group string) schema.GroupResource {
@@ -189,7 +261,8 @@ func (b *versionedResourceBuilder) registerEndpoints(
 
 	if b.RESTFunc != nil {
 		// Use the REST implementation directly.
-		registry[path] = b.RESTFunc(optionsGetter)
+		b.restStorage = b.RESTFunc(optionsGetter)
+		registry[path] = b.restStorage
 	} else {
 		// Create a new REST implementation wired to storage.
 		registry[path] = b.
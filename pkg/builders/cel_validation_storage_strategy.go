@@ -0,0 +1,162 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ StorageBuilder = &CELValidationStorageStrategy{}
+
+// CELRule is a "+kubebuilder:validation:XValidation" rule of a resource
+type CELRule struct {
+	// Path is the path of the field the rule validates as self, empty for the object - e.g. spec
+	Path string
+	// Rule is the CEL expression of the rule, which must evaluate to true for valid objects
+	Rule string
+	// Message is the detail of the error of the objects failing the rule
+	Message string
+}
+
+// CELValidator evaluates CELRules against the json of the objects of a resource converted to a version
+type CELValidator struct {
+	Version schema.GroupVersion
+	Rules   []CELRule
+
+	programs []cel.Program
+}
+
+// NewCELValidator compiles the rules declared by the types of version of a resource.  Generated for
+// resources with "+kubebuilder:validation:XValidation" comments, whose rules are checked to compile by
+// apiregister-gen, so a failure to compile panics.
+func NewCELValidator(version schema.GroupVersion, rules ...CELRule) *CELValidator {
+	v := &CELValidator{Version: version, Rules: rules}
+	for _, rule := range rules {
+		program, err := CompileCELRule(rule.Rule)
+		if err != nil {
+			panic(fmt.Errorf("failed to compile the rule %q of %s: %v", rule.Rule, version, err))
+		}
+		v.programs = append(v.programs, program)
+	}
+	return v
+}
+
+// CompileCELRule compiles the CEL expression of a rule, in which self is the value of the validated field
+func CompileCELRule(rule string) (cel.Program, error) {
+	env, err := cel.NewEnv(cel.Declarations(decls.NewIdent("self", decls.Dyn, nil)))
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(rule)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if t := ast.ResultType(); !proto.Equal(t, decls.Bool) && !proto.Equal(t, decls.Dyn) {
+		return nil, fmt.Errorf("the rule must evaluate to a bool")
+	}
+	return env.Program(ast)
+}
+
+// Validate returns a field.Invalid error for each rule obj fails.  The rules of unset fields are not evaluated.
+func (v *CELValidator) Validate(obj runtime.Object) field.ErrorList {
+	errors := field.ErrorList{}
+	versioned, err := Scheme.ConvertToVersion(obj.DeepCopyObject(), v.Version)
+	if err != nil {
+		return append(errors, field.InternalError(nil, err))
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(versioned)
+	if err != nil {
+		return append(errors, field.InternalError(nil, err))
+	}
+	for i, rule := range v.Rules {
+		// The rules of the object are reported at the root, as for the CustomResourceDefinitions
+		var path *field.Path
+		var self interface{} = content
+		if len(rule.Path) > 0 {
+			path = fieldPath(rule.Path)
+			for _, elem := range strings.Split(rule.Path, ".") {
+				if m, ok := self.(map[string]interface{}); ok {
+					self = m[elem]
+				} else {
+					self = nil
+				}
+			}
+		}
+		if self == nil {
+			continue
+		}
+
+		value := self
+		if _, ok := self.(map[string]interface{}); ok {
+			value = "object"
+		}
+		out, _, err := v.programs[i].Eval(map[string]interface{}{"self": self})
+		switch {
+		case err != nil:
+			errors = append(errors, field.Invalid(path, value, fmt.Sprintf("rule evaluation error: %v", err)))
+		case out.Type() != types.BoolType:
+			errors = append(errors, field.Invalid(path, value, fmt.Sprintf("rule %q did not evaluate to a bool", rule.Rule)))
+		case out != types.True:
+			message := rule.Message
+			if len(message) == 0 {
+				message = fmt.Sprintf("failed rule: %s", rule.Rule)
+			}
+			errors = append(errors, field.Invalid(path, value, message))
+		}
+	}
+	return errors
+}
+
+// NewCELValidationStorageStrategy wraps a StorageBuilder so created and updated objects are validated
+// against the rules of the validators.  Generated for resources with "+kubebuilder:validation:XValidation"
+// comments.
+func NewCELValidationStorageStrategy(strategy StorageBuilder, validators ...*CELValidator) StorageBuilder {
+	return &CELValidationStorageStrategy{strategy, validators}
+}
+
+// CELValidationStorageStrategy validates the rules of the Validators in addition to the validation of the
+// StorageBuilder
+type CELValidationStorageStrategy struct {
+	StorageBuilder
+	Validators []*CELValidator
+}
+
+func (s *CELValidationStorageStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	errors := s.StorageBuilder.Validate(ctx, obj)
+	for _, v := range s.Validators {
+		errors = append(errors, v.Validate(obj)...)
+	}
+	return errors
+}
+
+func (s *CELValidationStorageStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	errors := s.StorageBuilder.ValidateUpdate(ctx, obj, old)
+	for _, v := range s.Validators {
+		errors = append(errors, v.Validate(obj)...)
+	}
+	return errors
+}
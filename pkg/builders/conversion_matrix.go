@@ -0,0 +1,62 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ConversionPair is an ordered pair of the versions of a resource, the conversion of whose objects from the
+// From version to the To version is checked by the generated conversion matrix tests
+type ConversionPair struct {
+	Group, Kind, From, To string
+}
+
+func (p ConversionPair) String() string {
+	return fmt.Sprintf("%s/%s->%s", p.Kind, p.From, p.To)
+}
+
+// CheckConversionPath returns an error if scheme converts the objects of the From version of pair to the To
+// version neither directly nor through the internal version of the group, the hub of the generated conversions
+func CheckConversionPath(scheme *runtime.Scheme, pair ConversionPair) error {
+	from, err := scheme.New(schema.GroupVersionKind{Group: pair.Group, Version: pair.From, Kind: pair.Kind})
+	if err != nil {
+		return err
+	}
+	to, err := scheme.New(schema.GroupVersionKind{Group: pair.Group, Version: pair.To, Kind: pair.Kind})
+	if err != nil {
+		return err
+	}
+	if err := scheme.Convert(from, to, nil); err == nil {
+		return nil
+	}
+	internal, err := scheme.New(
+		schema.GroupVersionKind{Group: pair.Group, Version: runtime.APIVersionInternal, Kind: pair.Kind})
+	if err != nil {
+		return fmt.Errorf("no conversion of %s: %v", pair, err)
+	}
+	if err := scheme.Convert(from, internal, nil); err != nil {
+		return fmt.Errorf("no conversion of %s through the internal version: %v", pair, err)
+	}
+	if err := scheme.Convert(internal, to, nil); err != nil {
+		return fmt.Errorf("no conversion of %s through the internal version: %v", pair, err)
+	}
+	return nil
+}
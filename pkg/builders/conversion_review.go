@@ -0,0 +1,120 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ConversionReview is the apiextensions.k8s.io/v1 ConversionReview posted by the kube-apiserver to the
+// conversion webhook of a CustomResourceDefinition
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Request  *ConversionRequest  `json:"request,omitempty"`
+	Response *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest holds the objects to convert to the DesiredAPIVersion
+type ConversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse holds the converted objects, in the order of the objects of the request
+type ConversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+// NewConversionReviewHandler returns a conversion webhook handler of the ConversionReviews of the objects
+// of group, e.g. miskatonic.k8s.io.  The objects are converted to the desired version through the internal
+// version of the group, then the defaults of the desired version are applied so the converted objects
+// are fully defaulted.
+func NewConversionReviewHandler(group string) http.Handler {
+	return &conversionReviewHandler{group: group}
+}
+
+type conversionReviewHandler struct {
+	group string
+}
+
+func (h *conversionReviewHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	review := &ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode the ConversionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "the ConversionReview has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = &ConversionResponse{UID: review.Request.UID}
+	converted, err := h.convert(review.Request)
+	if err != nil {
+		review.Response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+	} else {
+		review.Response.ConvertedObjects = converted
+		review.Response.Result = metav1.Status{Status: metav1.StatusSuccess}
+	}
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// convert returns the objects of the request converted to its desired version and defaulted
+func (h *conversionReviewHandler) convert(request *ConversionRequest) ([]runtime.RawExtension, error) {
+	gv, err := schema.ParseGroupVersion(request.DesiredAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	if gv.Group != h.group {
+		return nil, fmt.Errorf("cannot convert to %s, expected a version of %s", request.DesiredAPIVersion, h.group)
+	}
+	converted := []runtime.RawExtension{}
+	for i, raw := range request.Objects {
+		// The decoder converts the object to the internal version
+		obj, gvk, err := Codecs.UniversalDecoder().Decode(raw.Raw, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode object %d: %v", i, err)
+		}
+		if gvk.Group != h.group {
+			return nil, fmt.Errorf("cannot convert object %d of %v, expected an object of %s", i, gvk, h.group)
+		}
+		out, err := Scheme.ConvertToVersion(obj, gv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert object %d of %v to %s: %v", i, gvk, gv, err)
+		}
+		Scheme.Default(out)
+		data, err := json.Marshal(out)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, runtime.RawExtension{Raw: data})
+	}
+	return converted, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog"
+)
+
+// ConversionTrace enables the tracing of the conversions of the versioned objects to the internal objects
+// by the generated conversions, to find the fields lost by a lossy conversion.  It is enabled by setting
+// the APISERVER_BUILDER_TRACE_CONVERSIONS environment variable.
+var ConversionTrace = len(os.Getenv("APISERVER_BUILDER_TRACE_CONVERSIONS")) > 0
+
+// TraceConversion converts the internal object out back to the type of the versioned object in with revert,
+// and logs each field of in which is lost or changed by the round trip along with its values before and after
+func TraceConversion(in, out runtime.Object, revert func(in, out runtime.Object) error) {
+	back := reflect.New(reflect.TypeOf(in).Elem()).Interface().(runtime.Object)
+	if err := revert(out, back); err != nil {
+		klog.Warningf("failed to convert the %T back to trace its conversion: %v", in, err)
+		return
+	}
+	inMap, err := toJSONMap(in)
+	if err != nil {
+		klog.Warningf("failed to trace the conversion of the %T: %v", in, err)
+		return
+	}
+	backMap, err := toJSONMap(back)
+	if err != nil {
+		klog.Warningf("failed to trace the conversion of the %T: %v", in, err)
+		return
+	}
+	// The TypeMeta is not converted
+	for _, m := range []map[string]interface{}{inMap, backMap} {
+		delete(m, "apiVersion")
+		delete(m, "kind")
+	}
+	name := ""
+	if accessor, err := meta.Accessor(in); err == nil {
+		name = accessor.GetName()
+	}
+	for _, field := range unmappedFields("", inMap, backMap) {
+		klog.Warningf("the conversion of the %T %q loses the field %s: %s before, %s after the round trip",
+			in, name, field, jsonFieldValue(inMap, field), jsonFieldValue(backMap, field))
+	}
+}
+
+// jsonFieldValue returns the json encoding of the field at path in m, or <unset>
+func jsonFieldValue(m map[string]interface{}, path string) string {
+	var value interface{} = m
+	for _, key := range strings.Split(path, ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return "<unset>"
+		}
+		if value, ok = fields[key]; !ok {
+			return "<unset>"
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "<unset>"
+	}
+	return string(data)
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ConversionWebhookClient converts the fields of a versioned object that the generated conversion
+// functions do not map to the internal type
+type ConversionWebhookClient interface {
+	// Convert returns obj with the fields at the unmapped json paths - e.g. spec.legacyName - moved into
+	// fields that the generated conversion functions map
+	Convert(obj *unstructured.Unstructured, unmapped []string) (*unstructured.Unstructured, error)
+}
+
+// ConversionWebhook is called by the conversion functions generated for resources with the
+// "+conversion:webhookFallback" comment.  Unmapped fields are dropped while it is nil.
+var ConversionWebhook ConversionWebhookClient
+
+// ConversionWebhookRequest is the body posted to the webhook by NewConversionWebhookClient
+type ConversionWebhookRequest struct {
+	Object         *unstructured.Unstructured `json:"object"`
+	UnmappedFields []string                   `json:"unmappedFields"`
+}
+
+// ConversionWebhookResponse is the body returned by the webhook to NewConversionWebhookClient
+type ConversionWebhookResponse struct {
+	Object *unstructured.Unstructured `json:"object"`
+}
+
+type httpConversionWebhookClient struct {
+	url    string
+	client *http.Client
+}
+
+// DefaultConversionWebhookTimeout bounds the requests of the NewConversionWebhookClient clients created
+// without an http.Client, for a hung webhook not to hang the reads and writes of the resources
+const DefaultConversionWebhookTimeout = 10 * time.Second
+
+// NewConversionWebhookClient returns a ConversionWebhookClient posting a ConversionWebhookRequest
+// to url and reading a ConversionWebhookResponse.  Defaults client to an http.Client timing out
+// after DefaultConversionWebhookTimeout.
+func NewConversionWebhookClient(url string, client *http.Client) ConversionWebhookClient {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultConversionWebhookTimeout}
+	}
+	return &httpConversionWebhookClient{url: url, client: client}
+}
+
+func (c *httpConversionWebhookClient) Convert(
+	obj *unstructured.Unstructured, unmapped []string) (*unstructured.Unstructured, error) {
+	body, err := json.Marshal(&ConversionWebhookRequest{Object: obj, UnmappedFields: unmapped})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("conversion webhook %s returned status %d", c.url, resp.StatusCode)
+	}
+	response := &ConversionWebhookResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, err
+	}
+	if response.Object == nil {
+		return nil, fmt.Errorf("conversion webhook %s returned no object", c.url)
+	}
+	return response.Object, nil
+}
+
+// ConvertWithWebhookFallback converts the versioned object in to the internal object out with convert.
+// The fields of in that are lost when converting out back with revert are passed to the ConversionWebhook,
+// and the object it returns is converted to out in their place.
+func ConvertWithWebhookFallback(in, out runtime.Object, convert, revert func(in, out runtime.Object) error) error {
+	if err := convert(in, out); err != nil {
+		return err
+	}
+	if ConversionWebhook == nil {
+		return nil
+	}
+
+	back := reflect.New(reflect.TypeOf(in).Elem()).Interface().(runtime.Object)
+	if err := revert(out, back); err != nil {
+		return err
+	}
+	inMap, err := toJSONMap(in)
+	if err != nil {
+		return err
+	}
+	backMap, err := toJSONMap(back)
+	if err != nil {
+		return err
+	}
+	// The TypeMeta is not converted
+	delete(inMap, "apiVersion")
+	delete(inMap, "kind")
+	unmapped := unmappedFields("", inMap, backMap)
+	if len(unmapped) == 0 {
+		return nil
+	}
+
+	obj := &unstructured.Unstructured{Object: inMap}
+	if gvks, _, err := Scheme.ObjectKinds(in); err == nil && len(gvks) > 0 {
+		obj.SetGroupVersionKind(gvks[0])
+	}
+	converted, err := ConversionWebhook.Convert(obj, unmapped)
+	if err != nil {
+		return fmt.Errorf("conversion webhook failed for fields %v: %v", unmapped, err)
+	}
+	fixed := reflect.New(reflect.TypeOf(in).Elem()).Interface().(runtime.Object)
+	data, err := json.Marshal(converted.Object)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, fixed); err != nil {
+		return err
+	}
+	return convert(fixed, out)
+}
+
+// toJSONMap returns the json encoding of obj as a map, so the field names match the ones served by the apiserver
+func toJSONMap(obj runtime.Object) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// unmappedFields returns the sorted paths of the fields of in that are missing or different in back
+func unmappedFields(prefix string, in, back map[string]interface{}) []string {
+	fields := []string{}
+	for k, v := range in {
+		path := k
+		if len(prefix) > 0 {
+			path = prefix + "." + k
+		}
+		b, found := back[k]
+		if found && equality.Semantic.DeepEqual(v, b) {
+			continue
+		}
+		vm, vok := v.(map[string]interface{})
+		bm, bok := b.(map[string]interface{})
+		if vok && bok {
+			fields = append(fields, unmappedFields(path, vm, bm)...)
+			continue
+		}
+		fields = append(fields, path)
+	}
+	sort.Strings(fields)
+	return fields
+}
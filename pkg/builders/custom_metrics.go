@@ -0,0 +1,244 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// CustomMetricsGroupVersion is the version of the custom metrics API serving the CustomMetrics
+var CustomMetricsGroupVersion = schema.GroupVersion{Group: "custom.metrics.k8s.io", Version: "v1beta1"}
+
+// Global registry of the metrics served by the custom metrics API of the apiserver
+var CustomMetrics = []CustomMetric{}
+
+// AddCustomMetrics registers metrics served by the custom metrics API of the apiserver, e.g. from the init of
+// the api groups with "+metric" comments.  The apiserver installs the API if any metric is registered.
+func AddCustomMetrics(metrics ...CustomMetric) {
+	CustomMetrics = append(CustomMetrics, metrics...)
+}
+
+// CustomMetric is a metric of the objects of a resource, read from each object
+type CustomMetric struct {
+	// Name is the name of the metric - e.g. fish
+	Name string
+	// Resource is the resource of the objects - e.g. deepones.innsmouth.k8s.io
+	Resource schema.GroupResource
+	// NamespaceScoped is true if the objects of the resource are namespaced
+	NamespaceScoped bool
+	// Storage stores the objects of the resource
+	Storage StandardStorageProvider
+	// Value returns the value of the metric for an unversioned object of the resource, false if it is not set
+	Value func(obj runtime.Object) (resource.Quantity, bool)
+}
+
+// MetricValueList is the custom.metrics.k8s.io/v1beta1 MetricValueList holding the values of a metric
+type MetricValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MetricValue `json:"items"`
+}
+
+// MetricValue is the value of a metric for an object
+type MetricValue struct {
+	metav1.TypeMeta `json:",inline"`
+
+	DescribedObject corev1.ObjectReference `json:"describedObject"`
+	MetricName      string                 `json:"metricName"`
+	Timestamp       metav1.Time            `json:"timestamp"`
+	WindowSeconds   *int64                 `json:"window,omitempty"`
+	Value           resource.Quantity      `json:"value"`
+	Selector        *metav1.LabelSelector  `json:"selector"`
+}
+
+// NewCustomMetricsHandler returns a handler serving the metrics under the custom metrics API path, e.g.
+// /apis/custom.metrics.k8s.io/v1beta1/namespaces/<namespace>/deepones.innsmouth.k8s.io/<name>/fish, as
+// requested by the HorizontalPodAutoscalers.  The name "*" serves the metric of the objects matching the
+// labelSelector parameter.  The objects are read from the storage on each request.
+func NewCustomMetricsHandler(metrics ...CustomMetric) http.Handler {
+	return &customMetricsHandler{metrics: metrics}
+}
+
+type customMetricsHandler struct {
+	metrics []CustomMetric
+}
+
+func (h *customMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeStatus(w, apierrors.NewMethodNotSupported(schema.GroupResource{Group: CustomMetricsGroupVersion.Group}, r.Method))
+		return
+	}
+	p := strings.Trim(strings.TrimPrefix(r.URL.Path, path.Join("/apis", CustomMetricsGroupVersion.String())), "/")
+	if len(p) == 0 {
+		writeJSON(w, h.resources())
+		return
+	}
+	segments := strings.Split(p, "/")
+	namespace := ""
+	if len(segments) == 5 && segments[0] == "namespaces" {
+		namespace, segments = segments[1], segments[2:]
+	}
+	if len(segments) != 3 {
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{Group: CustomMetricsGroupVersion.Group}, p))
+		return
+	}
+	metric, err := h.metric(segments[0], segments[2], len(namespace) > 0)
+	if err != nil {
+		writeStatus(w, err)
+		return
+	}
+	list, err := metric.values(r.Context(), namespace, segments[1], r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, err)
+		return
+	}
+	writeJSON(w, list)
+}
+
+// resources returns the discovery of the metrics, a resource named <resource>/<metric> per metric
+func (h *customMetricsHandler) resources() *metav1.APIResourceList {
+	list := &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: CustomMetricsGroupVersion.String(),
+		APIResources: []metav1.APIResource{},
+	}
+	for _, m := range h.metrics {
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:       m.Resource.String() + "/" + m.Name,
+			Namespaced: m.NamespaceScoped,
+			Kind:       "MetricValueList",
+			Verbs:      metav1.Verbs{"get"},
+		})
+	}
+	return list
+}
+
+// metric returns the metric named name of the resource, e.g. deepones.innsmouth.k8s.io
+func (h *customMetricsHandler) metric(resource, name string, namespaced bool) (*CustomMetric, error) {
+	for i, m := range h.metrics {
+		if m.Resource.String() == resource && m.Name == name && m.NamespaceScoped == namespaced {
+			return &h.metrics[i], nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: CustomMetricsGroupVersion.Group, Resource: resource}, name)
+}
+
+// values returns the values of the metric of the object named name, or of the objects matching selector if
+// name is "*"
+func (m *CustomMetric) values(ctx context.Context, namespace, name, selector string) (*MetricValueList, error) {
+	st := m.Storage.GetStandardStorage()
+	if st == nil {
+		return nil, apierrors.NewServiceUnavailable(fmt.Sprintf("the storage of %s is not built", m.Resource))
+	}
+	ctx = request.WithNamespace(ctx, namespace)
+	objects := []runtime.Object{}
+	if name == "*" {
+		s, err := labels.Parse(selector)
+		if err != nil {
+			return nil, apierrors.NewBadRequest(err.Error())
+		}
+		list, err := st.List(ctx, &internalversion.ListOptions{LabelSelector: s})
+		if err != nil {
+			return nil, err
+		}
+		if objects, err = meta.ExtractList(list); err != nil {
+			return nil, err
+		}
+	} else {
+		obj, err := st.Get(ctx, name, &metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+
+	apiVersion := ""
+	if versions := Scheme.PrioritizedVersionsForGroup(m.Resource.Group); len(versions) > 0 {
+		apiVersion = versions[0].String()
+	}
+	list := &MetricValueList{
+		TypeMeta: metav1.TypeMeta{Kind: "MetricValueList", APIVersion: CustomMetricsGroupVersion.String()},
+		Items:    []MetricValue{},
+	}
+	now := metav1.Now()
+	for _, obj := range objects {
+		value, ok := m.Value(obj)
+		if !ok {
+			continue
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		kind := ""
+		if gvks, _, err := Scheme.ObjectKinds(obj); err == nil {
+			kind = gvks[0].Kind
+		}
+		list.Items = append(list.Items, MetricValue{
+			DescribedObject: corev1.ObjectReference{
+				Kind:       kind,
+				APIVersion: apiVersion,
+				Namespace:  accessor.GetNamespace(),
+				Name:       accessor.GetName(),
+			},
+			MetricName: m.Name,
+			Timestamp:  now,
+			Value:      value,
+		})
+	}
+	if name != "*" && len(list.Items) == 0 {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: CustomMetricsGroupVersion.Group, Resource: m.Name}, name)
+	}
+	return list, nil
+}
+
+// writeJSON writes obj as the json body of the response
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeStatus writes the Status of err with its code
+func writeStatus(w http.ResponseWriter, err error) {
+	status := apierrors.NewInternalError(err).Status()
+	if s, ok := err.(apierrors.APIStatus); ok {
+		status = s.Status()
+	}
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(status.Code))
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+)
+
+var _ StorageBuilder = &DefaultOnReadStorageStrategy{}
+
+// NewDefaultOnReadStorageStrategy wraps a StorageBuilder so the objects read from storage get the defaults
+// of the preferred version of the resource, e.g. the defaults of the fields introduced after the objects were
+// stored.  Generated for resources with the "+resource:defaultOnRead" comment.
+func NewDefaultOnReadStorageStrategy(strategy StorageBuilder) StorageBuilder {
+	return &DefaultOnReadStorageStrategy{strategy}
+}
+
+// DefaultOnReadStorageStrategy decorates the objects returned by the store with the defaults of the
+// preferred version of the resource
+type DefaultOnReadStorageStrategy struct {
+	StorageBuilder
+}
+
+func (s *DefaultOnReadStorageStrategy) Build(builder StorageBuilder, store *StorageWrapper, options *generic.StoreOptions) {
+	s.StorageBuilder.Build(builder, store, options)
+	decorator := store.Decorator
+	group := store.DefaultQualifiedResource.Group
+	store.Decorator = func(obj runtime.Object) error {
+		if decorator != nil {
+			if err := decorator(obj); err != nil {
+				return err
+			}
+		}
+		if !meta.IsListType(obj) {
+			return defaultObject(obj, group)
+		}
+		return meta.EachListItem(obj, func(item runtime.Object) error {
+			return defaultObject(item, group)
+		})
+	}
+}
+
+// defaultObject applies the defaults of the preferred version of group to the unversioned obj by converting
+// it to the version and back
+func defaultObject(obj runtime.Object, group string) error {
+	kinds, _, err := Scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	for _, version := range Scheme.PrioritizedVersionsForGroup(group) {
+		if version.Version == runtime.APIVersionInternal ||
+			!Scheme.Recognizes(schema.GroupVersionKind{Group: group, Version: version.Version, Kind: kinds[0].Kind}) {
+			continue
+		}
+		versioned, err := Scheme.ConvertToVersion(obj.DeepCopyObject(), version)
+		if err != nil {
+			return err
+		}
+		Scheme.Default(versioned)
+		return Scheme.Convert(versioned, obj, nil)
+	}
+	return fmt.Errorf("no versions of %v registered to default", kinds[0])
+}
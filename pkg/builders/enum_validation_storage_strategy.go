@@ -0,0 +1,97 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ StorageBuilder = &EnumValidationStorageStrategy{}
+
+// EnumValue is the value of a field of a "+enum" type along with the values of the constants of the type
+type EnumValue struct {
+	// Path is the json path of the field
+	Path string
+	// Value is the value of the field
+	Value string
+	// Values are the values of the constants of the type of the field
+	Values []string
+}
+
+// ValidateEnums returns a field.NotSupported error for each of the values that is set and is not one of the
+// constants of its type.  Called by the ValidateEnums methods generated for the versioned types of resources with
+// fields of "+enum" types, as the conversions between the "+enum" types and the strings of the internal version
+// accept any value.
+func ValidateEnums(values ...EnumValue) field.ErrorList {
+	errs := field.ErrorList{}
+	for _, v := range values {
+		if len(v.Value) > 0 && !sets.NewString(v.Values...).Has(v.Value) {
+			errs = append(errs, field.NotSupported(fieldPath(v.Path), v.Value, v.Values))
+		}
+	}
+	return errs
+}
+
+// EnumValidator is a versioned type with fields of "+enum" types
+type EnumValidator interface {
+	ValidateEnums() field.ErrorList
+}
+
+// NewEnumValidationStorageStrategy wraps a StorageBuilder so the fields of "+enum" types of created and updated
+// objects are validated in each of the versions.  Generated for resources with fields of "+enum" types.  The
+// objects are not validated when read, so the objects stored with a value that is no longer a constant of its
+// type can still be read and fixed.
+func NewEnumValidationStorageStrategy(strategy StorageBuilder, versions ...schema.GroupVersion) StorageBuilder {
+	return &EnumValidationStorageStrategy{strategy, versions}
+}
+
+// EnumValidationStorageStrategy validates the fields of "+enum" types of the objects converted to each of the
+// Versions in addition to the validation of the StorageBuilder
+type EnumValidationStorageStrategy struct {
+	StorageBuilder
+	Versions []schema.GroupVersion
+}
+
+func (s *EnumValidationStorageStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	errors := s.StorageBuilder.Validate(ctx, obj)
+	return append(errors, s.validateEnums(obj)...)
+}
+
+func (s *EnumValidationStorageStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	errors := s.StorageBuilder.ValidateUpdate(ctx, obj, old)
+	return append(errors, s.validateEnums(obj)...)
+}
+
+func (s *EnumValidationStorageStrategy) validateEnums(obj runtime.Object) field.ErrorList {
+	errors := field.ErrorList{}
+	for _, version := range s.Versions {
+		versioned, err := Scheme.ConvertToVersion(obj.DeepCopyObject(), version)
+		if err != nil {
+			errors = append(errors, field.InternalError(nil, err))
+			continue
+		}
+		if v, ok := versioned.(EnumValidator); ok {
+			errors = append(errors, v.ValidateEnums()...)
+		}
+	}
+	return errors
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"k8s.io/component-base/featuregate"
+)
+
+// NewFeaturesHandler returns a handler serving the states of features in gate as a JSON object mapping the
+// name of each feature to whether it is enabled.  Generated as the "features" handler of api groups with
+// "+resource:featureGate" comments.
+func NewFeaturesHandler(features map[featuregate.Feature]featuregate.FeatureSpec, gate featuregate.FeatureGate) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		states := map[featuregate.Feature]bool{}
+		for feature := range features {
+			states[feature] = gate.Enabled(feature)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(states); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ StorageBuilder = &FieldConstraintStorageStrategy{}
+
+// FieldConstraintRule is the rule a FieldConstraint enforces on its fields
+type FieldConstraintRule string
+
+const (
+	// OneOf requires exactly one of the fields to be set
+	OneOf FieldConstraintRule = "oneOf"
+	// AllOrNone requires either all or none of the fields to be set
+	AllOrNone FieldConstraintRule = "allOrNone"
+)
+
+// FieldConstraint is a cross-field constraint of a resource
+type FieldConstraint struct {
+	Rule   FieldConstraintRule
+	Fields []ConstrainedField
+}
+
+// ConstrainedField is a field of a FieldConstraint
+type ConstrainedField struct {
+	// Path is the path of the field - e.g. spec.hall
+	Path string
+
+	// Value returns the field of the object, or nil if a parent of the field is not set
+	Value func(obj runtime.Object) interface{}
+}
+
+// NewFieldConstraintStorageStrategy wraps a StorageBuilder so created and updated objects are validated
+// against the constraints.  Generated for resources with "+resource:oneOf" or "+resource:allOrNone" comments.
+func NewFieldConstraintStorageStrategy(strategy StorageBuilder, constraints ...FieldConstraint) StorageBuilder {
+	return &FieldConstraintStorageStrategy{strategy, constraints}
+}
+
+// FieldConstraintStorageStrategy validates the Constraints in addition to the validation of the StorageBuilder
+type FieldConstraintStorageStrategy struct {
+	StorageBuilder
+	Constraints []FieldConstraint
+}
+
+func (s *FieldConstraintStorageStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	errors := s.StorageBuilder.Validate(ctx, obj)
+	return append(errors, ValidateFieldConstraints(obj, s.Constraints...)...)
+}
+
+func (s *FieldConstraintStorageStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	errors := s.StorageBuilder.ValidateUpdate(ctx, obj, old)
+	return append(errors, ValidateFieldConstraints(obj, s.Constraints...)...)
+}
+
+// ValidateFieldConstraints returns a field.Invalid error for each constraint violated by obj
+func ValidateFieldConstraints(obj runtime.Object, constraints ...FieldConstraint) field.ErrorList {
+	errors := field.ErrorList{}
+	for _, c := range constraints {
+		paths := []string{}
+		set := []string{}
+		for _, f := range c.Fields {
+			paths = append(paths, f.Path)
+			if IsFieldSet(f.Value(obj)) {
+				set = append(set, f.Path)
+			}
+		}
+
+		var detail string
+		switch c.Rule {
+		case OneOf:
+			if len(set) != 1 {
+				detail = fmt.Sprintf("exactly one of %s must be set", strings.Join(paths, ", "))
+			}
+		case AllOrNone:
+			if len(set) != 0 && len(set) != len(paths) {
+				detail = fmt.Sprintf("either all or none of %s must be set", strings.Join(paths, ", "))
+			}
+		default:
+			detail = fmt.Sprintf("unknown constraint %q on %s", c.Rule, strings.Join(paths, ", "))
+		}
+		if len(detail) > 0 {
+			errors = append(errors, field.Invalid(fieldPath(paths[0]), set, detail))
+		}
+	}
+	return errors
+}
+
+// IsFieldSet returns true if value is not nil, empty or the zero value of its type
+func IsFieldSet(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		return false
+	case v.Kind() == reflect.Slice, v.Kind() == reflect.Map:
+		return v.Len() > 0
+	}
+	return !v.IsZero()
+}
+
+// fieldPath returns the field.Path of a dot separated path - e.g. spec.hall
+func fieldPath(path string) *field.Path {
+	elems := strings.Split(path, ".")
+	return field.NewPath(elems[0], elems[1:]...)
+}
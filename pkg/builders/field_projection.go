@@ -0,0 +1,191 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// FieldProjectionParam is the query parameter of the comma separated paths of the fields the objects of GET
+// responses are trimmed to - e.g. ?fields=metadata.name,status.phase
+const FieldProjectionParam = "fields"
+
+// WithFieldProjection trims the json objects of the successful GET responses of handler to the fields of the
+// FieldProjectionParam of the requests, keeping their apiVersion and kind.  The items of lists are trimmed
+// rather than the lists.  Requests without the parameter, watches and responses in other media types, e.g.
+// protobuf, are served unchanged.
+func WithFieldProjection(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		fields := query.Get(FieldProjectionParam)
+		if req.Method != http.MethodGet || len(fields) == 0 || query.Get("watch") == "true" || query.Get("watch") == "1" {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		// The parameter is not forwarded to the storage, and the response is read uncompressed to be trimmed
+		query.Del(FieldProjectionParam)
+		req = req.Clone(req.Context())
+		req.URL.RawQuery = query.Encode()
+		req.RequestURI = req.URL.RequestURI()
+		req.Header.Del("Accept-Encoding")
+
+		recorder := &fieldProjectionRecorder{header: http.Header{}, status: http.StatusOK}
+		handler.ServeHTTP(recorder, req)
+
+		body := recorder.body.Bytes()
+		mediaType, _, _ := mime.ParseMediaType(recorder.header.Get("Content-Type"))
+		if recorder.status == http.StatusOK && mediaType == "application/json" {
+			if projected, err := ProjectFields(body, strings.Split(fields, ",")); err == nil {
+				body = projected
+			}
+		}
+		for key, values := range recorder.header {
+			w.Header()[key] = values
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(recorder.status)
+		w.Write(body)
+	})
+}
+
+// ProjectFields trims the json object data to the fields at paths, keeping its apiVersion and kind.  The items
+// of a list are trimmed rather than the list, and the paths traverse the elements of the lists of the object.
+// Fields missing from the object are left out.
+func ProjectFields(data []byte, paths []string) ([]byte, error) {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	split := [][]string{}
+	for _, path := range paths {
+		if path = strings.TrimPrefix(strings.TrimSpace(path), "."); len(path) > 0 {
+			split = append(split, strings.Split(path, "."))
+		}
+	}
+	items, isList := obj["items"].([]interface{})
+	if !isList {
+		return json.Marshal(projectObject(obj, split))
+	}
+	for i, item := range items {
+		if itemObj, ok := item.(map[string]interface{}); ok {
+			items[i] = projectObject(itemObj, split)
+		}
+	}
+	return json.Marshal(obj)
+}
+
+// projectObject returns the apiVersion, kind and the fields at paths of obj
+func projectObject(obj map[string]interface{}, paths [][]string) map[string]interface{} {
+	projected := map[string]interface{}{}
+	for _, key := range []string{"apiVersion", "kind"} {
+		if value, found := obj[key]; found {
+			projected[key] = value
+		}
+	}
+	for _, path := range paths {
+		if value, found := projectPath(obj, path); found {
+			mergeValues(projected, value)
+		}
+	}
+	return projected
+}
+
+// projectPath returns the value of the field at path in value, projecting the elements of the lists it
+// traverses
+func projectPath(value interface{}, path []string) (interface{}, bool) {
+	if len(path) == 0 {
+		return value, true
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		field, found := v[path[0]]
+		if !found {
+			return nil, false
+		}
+		projected, found := projectPath(field, path[1:])
+		if !found {
+			return nil, false
+		}
+		return map[string]interface{}{path[0]: projected}, true
+	case []interface{}:
+		elems := []interface{}{}
+		for _, elem := range v {
+			// The elements lacking the field are kept empty to merge the elements projected by other paths
+			projected, found := projectPath(elem, path)
+			if !found {
+				projected = map[string]interface{}{}
+			}
+			elems = append(elems, projected)
+		}
+		return elems, true
+	}
+	return nil, false
+}
+
+// mergeValues merges the fields of the projected objects and the elements of the projected lists into
+// existing, the values projected by several paths
+func mergeValues(existing, projected interface{}) interface{} {
+	switch e := existing.(type) {
+	case map[string]interface{}:
+		p, ok := projected.(map[string]interface{})
+		if !ok {
+			return existing
+		}
+		for key := range p {
+			if _, found := e[key]; found {
+				e[key] = mergeValues(e[key], p[key])
+			} else {
+				e[key] = p[key]
+			}
+		}
+		return e
+	case []interface{}:
+		p, ok := projected.([]interface{})
+		if !ok || len(p) != len(e) {
+			return existing
+		}
+		for i := range e {
+			e[i] = mergeValues(e[i], p[i])
+		}
+		return e
+	}
+	return existing
+}
+
+// fieldProjectionRecorder records the response of a request to trim it
+type fieldProjectionRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *fieldProjectionRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *fieldProjectionRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *fieldProjectionRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
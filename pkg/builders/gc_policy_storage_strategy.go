@@ -0,0 +1,61 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+var _ StorageBuilder = &GCPolicyStorageStrategy{}
+var _ rest.GarbageCollectionDeleteStrategy = &GCPolicyStorageStrategy{}
+
+// NewGCPolicyStorageStrategy wraps a StorageBuilder so deletes of the resource that do not set a
+// propagation policy default to the policy of the resource in policies.  Generated for resources with
+// the "+resource:enableGarbageCollection=false" comment, which orphan their dependents by default.
+func NewGCPolicyStorageStrategy(
+	policies map[schema.GroupResource]metav1.DeletionPropagation,
+	resource schema.GroupResource,
+	strategy StorageBuilder) StorageBuilder {
+	return &GCPolicyStorageStrategy{strategy, policies, resource}
+}
+
+// GCPolicyStorageStrategy looks up the default garbage collection policy of Resource in Policies on each
+// delete, so the policies may be changed before the apiserver is started
+type GCPolicyStorageStrategy struct {
+	StorageBuilder
+	Policies map[schema.GroupResource]metav1.DeletionPropagation
+	Resource schema.GroupResource
+}
+
+// DefaultGarbageCollectionPolicy returns the garbage collection policy of the Resource, falling back to the
+// policy of the wrapped StorageBuilder if the Resource has none
+func (s *GCPolicyStorageStrategy) DefaultGarbageCollectionPolicy(ctx context.Context) rest.GarbageCollectionPolicy {
+	switch s.Policies[s.Resource] {
+	case metav1.DeletePropagationOrphan:
+		return rest.OrphanDependents
+	case metav1.DeletePropagationBackground, metav1.DeletePropagationForeground:
+		return rest.DeleteDependents
+	}
+	if gc, ok := s.StorageBuilder.(rest.GarbageCollectionDeleteStrategy); ok {
+		return gc.DefaultGarbageCollectionPolicy(ctx)
+	}
+	return rest.DeleteDependents
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ StorageBuilder = &GenerateNameStorageStrategy{}
+
+// NewGenerateNameStorageStrategy wraps a StorageBuilder so the names of the created objects are generated by
+// the server from their generateName.  Generated for resources with the "+resource:generateNameRequired" comment.
+func NewGenerateNameStorageStrategy(strategy StorageBuilder) StorageBuilder {
+	return &GenerateNameStorageStrategy{strategy}
+}
+
+// GenerateNameStorageStrategy rejects the created objects without a generateName, and clears the name of the
+// others so the store generates it from their generateName
+type GenerateNameStorageStrategy struct {
+	StorageBuilder
+}
+
+// PrepareForCreate clears the name of obj if it has a generateName, the store then generates the name from it
+func (s *GenerateNameStorageStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	s.StorageBuilder.PrepareForCreate(ctx, obj)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	if len(accessor.GetGenerateName()) > 0 {
+		accessor.SetName("")
+	}
+}
+
+// Validate requires obj to have a generateName
+func (s *GenerateNameStorageStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	errors := s.StorageBuilder.Validate(ctx, obj)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return append(errors, field.InternalError(field.NewPath("metadata"), err))
+	}
+	if len(accessor.GetGenerateName()) == 0 {
+		errors = append(errors, field.Required(field.NewPath("metadata", "generateName"),
+			"the names of the objects are generated by the server from their generateName"))
+	}
+	return errors
+}
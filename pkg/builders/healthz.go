@@ -0,0 +1,30 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"k8s.io/apiserver/pkg/server/healthz"
+)
+
+// Global registry of the custom healthz checks of the apiserver
+var HealthzChecks = []healthz.HealthChecker{}
+
+// AddHealthzCheck registers checks served under /healthz of the apiserver, e.g. from main before the
+// apiserver is started.  /healthz fails while any of the checks fails.
+func AddHealthzCheck(checks ...healthz.HealthChecker) {
+	HealthzChecks = append(HealthzChecks, checks...)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// NewJSONPatchObjectInfo returns the rest.UpdatedObjectInfo of a JSON patch written against the version of the
+// objects returned by newFunc.  The stored internal object is converted to the version before applying the
+// patch, so its paths are those of the version rather than of the internal object, and the patched object is
+// defaulted and converted back, as the apiserver does for the patch requests.
+func NewJSONPatchObjectInfo(patch []byte, newFunc func() runtime.Object) rest.UpdatedObjectInfo {
+	return &jsonPatchObjectInfo{patch, newFunc}
+}
+
+type jsonPatchObjectInfo struct {
+	patch   []byte
+	newFunc func() runtime.Object
+}
+
+func (i *jsonPatchObjectInfo) Preconditions() *metav1.Preconditions {
+	return nil
+}
+
+func (i *jsonPatchObjectInfo) UpdatedObject(ctx context.Context, oldObj runtime.Object) (runtime.Object, error) {
+	versioned := i.newFunc()
+	if err := Scheme.Convert(oldObj, versioned, nil); err != nil {
+		return nil, err
+	}
+	original, err := json.Marshal(versioned)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.DecodePatch(i.patch)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	patched, err := patch.Apply(original)
+	if err != nil {
+		// As the apiserver, a patch failing to apply is an unprocessable entity
+		return nil, apierrors.NewGenericServerResponse(http.StatusUnprocessableEntity, "", schema.GroupResource{}, "", err.Error(), 0, false)
+	}
+
+	versioned = i.newFunc()
+	if err := json.Unmarshal(patched, versioned); err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	Scheme.Default(versioned)
+	internal := reflect.New(reflect.TypeOf(oldObj).Elem()).Interface().(runtime.Object)
+	if err := Scheme.Convert(versioned, internal, nil); err != nil {
+		return nil, err
+	}
+	return internal, nil
+}
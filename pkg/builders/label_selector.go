@@ -0,0 +1,68 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+// compiledSelectors caches label selectors by their canonical form so objects sharing a selector only
+// compile it once
+var compiledSelectors = cache.NewLRUExpireCache(1024)
+
+const compiledSelectorTTL = 10 * time.Minute
+
+// LabelSelectorAsSelector converts the LabelSelector into a labels.Selector, reusing the previously
+// compiled selector for equivalent LabelSelectors.  A nil LabelSelector selects nothing.
+func LabelSelectorAsSelector(ls *metav1.LabelSelector) (labels.Selector, error) {
+	if ls == nil {
+		return labels.Nothing(), nil
+	}
+	key := labelSelectorKey(ls)
+	if s, found := compiledSelectors.Get(key); found {
+		return s.(labels.Selector), nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(ls)
+	if err != nil {
+		return nil, err
+	}
+	compiledSelectors.Add(key, s, compiledSelectorTTL)
+	return s, nil
+}
+
+// labelSelectorKey returns a canonical string for the LabelSelector that is equal for LabelSelectors
+// matching the same labels
+func labelSelectorKey(ls *metav1.LabelSelector) string {
+	reqs := []string{}
+	for k, v := range ls.MatchLabels {
+		reqs = append(reqs, fmt.Sprintf("%q=%q", k, v))
+	}
+	for _, expr := range ls.MatchExpressions {
+		values := append([]string{}, expr.Values...)
+		sort.Strings(values)
+		reqs = append(reqs, fmt.Sprintf("%q %s %q", expr.Key, expr.Operator, values))
+	}
+	sort.Strings(reqs)
+	return strings.Join(reqs, ",")
+}
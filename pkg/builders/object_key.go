@@ -0,0 +1,39 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitObjectKey splits the key of an object in the stores of informers into its namespace and name.  The keys
+// of namespaced objects are <namespace>/<name>, the keys of cluster scoped objects are their name and their
+// namespace is empty.
+func SplitObjectKey(key string, namespaced bool) (namespace, name string, err error) {
+	if !namespaced {
+		if len(key) == 0 || strings.Contains(key, "/") {
+			return "", "", fmt.Errorf("unexpected key %q of a cluster scoped object, expected <name>", key)
+		}
+		return "", key, nil
+	}
+	parts := strings.Split(key, "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", fmt.Errorf("unexpected key %q of a namespaced object, expected <namespace>/<name>", key)
+	}
+	return parts[0], parts[1], nil
+}
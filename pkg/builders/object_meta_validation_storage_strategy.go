@@ -0,0 +1,82 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ StorageBuilder = &ObjectMetaValidationStorageStrategy{}
+
+// NewObjectMetaValidationStorageStrategy wraps a StorageBuilder so the metadata of the objects is validated,
+// with nameFunc validating their names.  Generated for all resources, nameFunc is NameIsDNSSubdomain unless
+// overridden by the "+resource:nameValidation=<func>" comment.
+func NewObjectMetaValidationStorageStrategy(strategy StorageBuilder, nameFunc validation.ValidateNameFunc) StorageBuilder {
+	return &ObjectMetaValidationStorageStrategy{strategy, nameFunc}
+}
+
+// ObjectMetaValidationStorageStrategy validates the name, namespace, labels and annotations of created
+// objects, and the name of updated objects
+type ObjectMetaValidationStorageStrategy struct {
+	StorageBuilder
+	NameFunc validation.ValidateNameFunc
+}
+
+func (s *ObjectMetaValidationStorageStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	errors := s.StorageBuilder.Validate(ctx, obj)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return append(errors, field.InternalError(field.NewPath("metadata"), err))
+	}
+	return append(errors, validation.ValidateObjectMetaAccessor(
+		accessor, s.NamespaceScoped(), s.NameFunc, field.NewPath("metadata"))...)
+}
+
+// ValidateUpdate only validates the name, the store validates the rest of the metadata of updated objects
+// with the generic rules, which would report their errors twice
+func (s *ObjectMetaValidationStorageStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	errors := s.StorageBuilder.ValidateUpdate(ctx, obj, old)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return append(errors, field.InternalError(field.NewPath("metadata"), err))
+	}
+	for _, msg := range s.NameFunc(accessor.GetName(), false) {
+		errors = append(errors, field.Invalid(field.NewPath("metadata", "name"), accessor.GetName(), msg))
+	}
+	return errors
+}
+
+// NameMatchesRegex returns a ValidateNameFunc validating names with nameFunc and requiring them to match regex.
+// Generated for resources with the "+resource:nameRegex=<regex>" comment, which is checked to compile.
+func NameMatchesRegex(regex string, nameFunc validation.ValidateNameFunc) validation.ValidateNameFunc {
+	re := regexp.MustCompile(regex)
+	return func(name string, prefix bool) []string {
+		errors := nameFunc(name, prefix)
+		// The generateName prefix is not matched, the name generated from it is validated once set
+		if !prefix && !re.MatchString(name) {
+			errors = append(errors, fmt.Sprintf("must match the regex %s", regex))
+		}
+		return errors
+	}
+}
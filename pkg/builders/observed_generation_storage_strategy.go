@@ -0,0 +1,48 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ StorageBuilder = &ObservedGenerationStorageStrategy{}
+
+// NewObservedGenerationStorageStrategy wraps the StorageBuilder of the status of a resource so status updates
+// set the observed generation of the status to the generation of the object.  Generated for resources with
+// the "+status:observedGeneration" comment.
+func NewObservedGenerationStorageStrategy(
+	strategy StorageBuilder, setObservedGeneration func(obj runtime.Object, generation int64)) StorageBuilder {
+	return &ObservedGenerationStorageStrategy{strategy, setObservedGeneration}
+}
+
+// ObservedGenerationStorageStrategy calls SetObservedGeneration with the generation of the updated object
+// once the wrapped StorageBuilder prepared the status update
+type ObservedGenerationStorageStrategy struct {
+	StorageBuilder
+	SetObservedGeneration func(obj runtime.Object, generation int64)
+}
+
+func (s *ObservedGenerationStorageStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	s.StorageBuilder.PrepareForUpdate(ctx, obj, old)
+	// Status updates do not change the generation, which is the one of the stored object
+	if o, ok := old.(HasObjectMeta); ok {
+		s.SetObservedGeneration(obj, o.GetObjectMeta().Generation)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"encoding/json"
+
+	"k8s.io/kube-openapi/pkg/common"
+)
+
+// MergeOpenAPIDefinitions returns the union of the definitions returned by the getters, e.g. of each
+// api group version.  The definitions of shared types, such as the ObjectMeta, are returned by more than
+// one getter and taken from the first of them, so the result does not depend on map iteration order.
+func MergeOpenAPIDefinitions(
+	ref common.ReferenceCallback, getters ...common.GetOpenAPIDefinitions) map[string]common.OpenAPIDefinition {
+	merged := map[string]common.OpenAPIDefinition{}
+	for _, getter := range getters {
+		for name, definition := range getter(ref) {
+			if _, found := merged[name]; !found {
+				merged[name] = definition
+			}
+		}
+	}
+	return merged
+}
+
+// AddOpenAPIExamples returns the definitions returned by getter with the example of each definition set from
+// the OpenAPIExamples of the api groups
+func AddOpenAPIExamples(getter common.GetOpenAPIDefinitions, apis []*APIGroupBuilder) common.GetOpenAPIDefinitions {
+	return func(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+		definitions := getter(ref)
+		for _, g := range apis {
+			for name, example := range g.OpenAPIExamples {
+				if definition, found := definitions[name]; found {
+					definition.Schema.Example = json.RawMessage(example)
+					definitions[name] = definition
+				}
+			}
+		}
+		return definitions
+	}
+}
+
+// AddOpenAPIUIDescriptors returns the definitions returned by getter with the x-descriptors extension of the
+// properties of each definition set from the OpenAPIUIDescriptors of the api groups
+func AddOpenAPIUIDescriptors(
+	getter common.GetOpenAPIDefinitions, apis []*APIGroupBuilder) common.GetOpenAPIDefinitions {
+	return func(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+		definitions := getter(ref)
+		for _, g := range apis {
+			for name, fields := range g.OpenAPIUIDescriptors {
+				definition, found := definitions[name]
+				if !found {
+					continue
+				}
+				for field, descriptors := range fields {
+					if property, found := definition.Schema.Properties[field]; found {
+						property.AddExtension("x-descriptors", descriptors)
+						definition.Schema.Properties[field] = property
+					}
+				}
+				definitions[name] = definition
+			}
+		}
+		return definitions
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+)
+
+// previousGroupRESTOptionsGetter keeps the resources of a renamed group under the etcd keys of the previous
+// group name, so the objects stored before the rename are still found.  The objects stored with the
+// apiVersion of the previous group decode as the types of the group, which are also registered under the
+// previous group name.  Prefixes not starting with the group, e.g. those of a storage factory, are kept.
+type previousGroupRESTOptionsGetter struct {
+	generic.RESTOptionsGetter
+	previousGroup string
+}
+
+func (g *previousGroupRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	options, err := g.RESTOptionsGetter.GetRESTOptions(resource)
+	if err != nil {
+		return options, err
+	}
+	if strings.HasPrefix(options.ResourcePrefix, resource.Group+"/") {
+		options.ResourcePrefix = g.previousGroup + strings.TrimPrefix(options.ResourcePrefix, resource.Group)
+	}
+	return options, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+var _ StorageBuilder = &PrintColumnStorageStrategy{}
+
+// PrintColumn is an additional column printed for a resource by "kubectl get"
+type PrintColumn struct {
+	metav1.TableColumnDefinition
+
+	// Value returns the cell of the column for the object, or nil if the column is not set
+	Value func(obj runtime.Object) interface{}
+}
+
+// NewPrintColumnStorageStrategy wraps a StorageBuilder so the resource is printed as a table with
+// the given columns.  Generated for resources with "+resource:printColumn" comments.
+func NewPrintColumnStorageStrategy(strategy StorageBuilder, columns ...PrintColumn) StorageBuilder {
+	return &PrintColumnStorageStrategy{strategy, columns}
+}
+
+// PrintColumnStorageStrategy sets a TableConvertor printing Columns on the store
+type PrintColumnStorageStrategy struct {
+	StorageBuilder
+	Columns []PrintColumn
+}
+
+func (s *PrintColumnStorageStrategy) Build(builder StorageBuilder, store *StorageWrapper, options *generic.StoreOptions) {
+	s.StorageBuilder.Build(builder, store, options)
+	store.TableConvertor = &printColumnTableConvertor{s.Columns, store}
+}
+
+var _ rest.TableConvertor = &printColumnTableConvertor{}
+
+var swaggerMetadataDescriptions = metav1.ObjectMeta{}.SwaggerDoc()
+
+// printColumnTableConvertor prints the name of the object, followed by the columns and the age
+// of the object.  Objects that are not of the type of the store are printed with only the name
+// and the age.
+type printColumnTableConvertor struct {
+	columns []PrintColumn
+	store   *StorageWrapper
+}
+
+// handles returns true if the object is of the type of the store or of its list type
+func (c *printColumnTableConvertor) handles(object runtime.Object) bool {
+	t := reflect.TypeOf(object)
+	return t == reflect.TypeOf(c.store.NewFunc()) || t == reflect.TypeOf(c.store.NewListFunc())
+}
+
+func (c *printColumnTableConvertor) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	columns := c.columns
+	if !c.handles(object) {
+		columns = nil
+	}
+	table := &metav1.Table{}
+	fn := func(obj runtime.Object) error {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		cells := []interface{}{m.GetName()}
+		for _, column := range columns {
+			cells = append(cells, printCell(column.Value(obj)))
+		}
+		cells = append(cells, m.GetCreationTimestamp().Time.UTC().Format(time.RFC3339))
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  cells,
+			Object: runtime.RawExtension{Object: obj},
+		})
+		return nil
+	}
+	if meta.IsListType(object) {
+		if err := meta.EachListItem(object, fn); err != nil {
+			return nil, err
+		}
+	} else if err := fn(object); err != nil {
+		return nil, err
+	}
+
+	if m, err := meta.ListAccessor(object); err == nil {
+		table.ResourceVersion = m.GetResourceVersion()
+		table.SelfLink = m.GetSelfLink()
+		table.Continue = m.GetContinue()
+		table.RemainingItemCount = m.GetRemainingItemCount()
+	} else if m, err := meta.CommonAccessor(object); err == nil {
+		table.ResourceVersion = m.GetResourceVersion()
+		table.SelfLink = m.GetSelfLink()
+	}
+
+	if opt, ok := tableOptions.(*metav1.TableOptions); !ok || !opt.NoHeaders {
+		table.ColumnDefinitions = []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name", Description: swaggerMetadataDescriptions["name"]},
+		}
+		for _, column := range columns {
+			table.ColumnDefinitions = append(table.ColumnDefinitions, column.TableColumnDefinition)
+		}
+		table.ColumnDefinitions = append(table.ColumnDefinitions, metav1.TableColumnDefinition{
+			Name: "Age", Type: "date", Description: swaggerMetadataDescriptions["creationTimestamp"],
+		})
+	}
+	return table, nil
+}
+
+// printCell formats timestamps the same way as the creation timestamp of the object
+func printCell(value interface{}) interface{} {
+	switch t := value.(type) {
+	case metav1.Time:
+		if t.IsZero() {
+			return nil
+		}
+		return t.UTC().Format(time.RFC3339)
+	case *metav1.Time:
+		if t == nil || t.IsZero() {
+			return nil
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return value
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ StorageBuilder = &RangeDefaultStorageStrategy{}
+
+// RangeDefault is an integer field of a resource clamped into the [Min, Max] range on create
+type RangeDefault struct {
+	// Path is the path of the field - e.g. spec.replicas
+	Path string
+
+	// Min and Max are the bounds of the range, Min is the default of an unset pointer field
+	Min, Max int64
+
+	// Field returns a pointer to the field of the object, or nil if a parent of the field is not set
+	Field func(obj runtime.Object) interface{}
+}
+
+// NewRangeDefaultStorageStrategy wraps a StorageBuilder so the fields of the created objects are clamped into
+// their range before they are validated.  Generated for resources with "+resource:rangeDefault=" comments.
+func NewRangeDefaultStorageStrategy(strategy StorageBuilder, ranges ...RangeDefault) StorageBuilder {
+	return &RangeDefaultStorageStrategy{strategy, ranges}
+}
+
+// RangeDefaultStorageStrategy clamps the Ranges before the StorageBuilder prepares the created objects
+type RangeDefaultStorageStrategy struct {
+	StorageBuilder
+	Ranges []RangeDefault
+}
+
+func (s *RangeDefaultStorageStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	ClampRangeDefaults(obj, s.Ranges...)
+	s.StorageBuilder.PrepareForCreate(ctx, obj)
+}
+
+// ClampRangeDefaults sets the fields of obj below their range to its Min and the fields above it to its Max.
+// Unset pointer fields are set to a pointer to Min.
+func ClampRangeDefaults(obj runtime.Object, ranges ...RangeDefault) {
+	for _, r := range ranges {
+		field := r.Field(obj)
+		if field == nil {
+			continue
+		}
+		v := reflect.ValueOf(field).Elem()
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+				setInteger(v.Elem(), r.Min)
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if n := v.Int(); n < r.Min {
+				v.SetInt(r.Min)
+			} else if n > r.Max {
+				v.SetInt(r.Max)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			// apiregister-gen checks Min is not negative for unsigned fields
+			if n := v.Uint(); n < uint64(r.Min) {
+				v.SetUint(uint64(r.Min))
+			} else if n > uint64(r.Max) {
+				v.SetUint(uint64(r.Max))
+			}
+		}
+	}
+}
+
+// setInteger sets the signed or unsigned integer v to n
+func setInteger(v reflect.Value, n int64) {
+	if v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64 {
+		v.SetUint(uint64(n))
+	} else {
+		v.SetInt(n)
+	}
+}
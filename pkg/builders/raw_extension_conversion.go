@@ -0,0 +1,70 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+// Convert_runtime_RawExtension_To_runtime_RawExtension deep copies the Raw bytes and the Object of a
+// RawExtension field.  apiserver-boot passes this package to conversion-gen with --extra-peer-dirs, so
+// the generated conversions call it rather than sharing the RawExtension between the converted objects.
+func Convert_runtime_RawExtension_To_runtime_RawExtension(in, out *runtime.RawExtension, s conversion.Scope) error {
+	in.DeepCopyInto(out)
+	return nil
+}
+
+// DecodeRawExtension decodes the Raw bytes of a "+embeddedObject" RawExtension field into the object of the
+// scheme named by their apiVersion and kind, set as the Object of the field at the json path.  Called by the conversions
+// generated to the internal version, so the internal objects hold the embedded objects decoded.
+func DecodeRawExtension(scheme *runtime.Scheme, path string, ext *runtime.RawExtension) error {
+	if ext.Object != nil || len(ext.Raw) == 0 || string(ext.Raw) == "null" {
+		return nil
+	}
+	obj, _, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(ext.Raw, nil, nil)
+	if err != nil {
+		return fmt.Errorf("%s: decoding the embedded object: %v", path, err)
+	}
+	ext.Object = obj
+	return nil
+}
+
+// EncodeRawExtension encodes the Object of a "+embeddedObject" RawExtension field, along with its apiVersion
+// and kind, into the Raw bytes of the field.  Called by the conversions generated from the internal version,
+// as RawExtensions are serialized from their Raw bytes.
+func EncodeRawExtension(scheme *runtime.Scheme, path string, ext *runtime.RawExtension) error {
+	if ext.Object == nil {
+		return nil
+	}
+	gvks, _, err := scheme.ObjectKinds(ext.Object)
+	if err != nil {
+		return fmt.Errorf("%s: encoding the embedded object: %v", path, err)
+	}
+	obj := ext.Object.DeepCopyObject()
+	obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("%s: encoding the embedded object: %v", path, err)
+	}
+	ext.Raw, ext.Object = raw, nil
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ StorageBuilder = &ReadOnlyFieldStorageStrategy{}
+
+// ReadOnlyField is a field of a resource clients may not set through the resource, only through its status
+// subresource
+type ReadOnlyField struct {
+	// Path is the path of the field - e.g. status.phase
+	Path string
+
+	// Field returns a pointer to the field of the object, or nil if a parent of the field is not set
+	Field func(obj runtime.Object) interface{}
+}
+
+// NewReadOnlyFieldStorageStrategy wraps a StorageBuilder so the read-only fields are cleared from the created
+// objects and kept from the stored objects on updates.  Generated for resources with "+readOnly" fields.
+func NewReadOnlyFieldStorageStrategy(strategy StorageBuilder, fields ...ReadOnlyField) StorageBuilder {
+	return &ReadOnlyFieldStorageStrategy{strategy, fields}
+}
+
+// ReadOnlyFieldStorageStrategy resets the Fields before the StorageBuilder prepares the created and updated
+// objects, so a field set by the client does not bump the generation, and again after it so the StorageBuilder
+// does not set them either
+type ReadOnlyFieldStorageStrategy struct {
+	StorageBuilder
+	Fields []ReadOnlyField
+}
+
+func (s *ReadOnlyFieldStorageStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	ResetReadOnlyFields(obj, nil, s.Fields...)
+	s.StorageBuilder.PrepareForCreate(ctx, obj)
+	ResetReadOnlyFields(obj, nil, s.Fields...)
+}
+
+func (s *ReadOnlyFieldStorageStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	ResetReadOnlyFields(obj, old, s.Fields...)
+	s.StorageBuilder.PrepareForUpdate(ctx, obj, old)
+	ResetReadOnlyFields(obj, old, s.Fields...)
+}
+
+// ResetReadOnlyFields sets the fields of obj to their value in old, or to their zero value if old is nil or
+// does not set them
+func ResetReadOnlyFields(obj, old runtime.Object, fields ...ReadOnlyField) {
+	for _, f := range fields {
+		field := f.Field(obj)
+		if field == nil {
+			continue
+		}
+		var stored interface{}
+		if old != nil {
+			stored = f.Field(old)
+		}
+		v := reflect.ValueOf(field).Elem()
+		if stored != nil {
+			v.Set(reflect.ValueOf(stored).Elem())
+		} else {
+			v.Set(reflect.Zero(v.Type()))
+		}
+	}
+}
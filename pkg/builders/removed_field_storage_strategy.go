@@ -0,0 +1,94 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/generic"
+)
+
+var _ StorageBuilder = &RemovedFieldStorageStrategy{}
+
+// RemovedField is a field removed from a resource, kept in its types until the objects stored before its
+// removal no longer set it
+type RemovedField struct {
+	// Path is the path of the field - e.g. spec.oldThing
+	Path string
+
+	// Field returns a pointer to the field of the object, or nil if a parent of the field is not set
+	Field func(obj runtime.Object) interface{}
+}
+
+// NewRemovedFieldStorageStrategy wraps a StorageBuilder so the removed fields are cleared from the created and
+// updated objects and from the objects read from the storage.  Generated for resources with "+removedField="
+// comments.
+func NewRemovedFieldStorageStrategy(strategy StorageBuilder, fields ...RemovedField) StorageBuilder {
+	return &RemovedFieldStorageStrategy{strategy, fields}
+}
+
+// RemovedFieldStorageStrategy clears the Fields before the StorageBuilder prepares the created and updated
+// objects, and decorates the store to clear them from the objects it returns
+type RemovedFieldStorageStrategy struct {
+	StorageBuilder
+	Fields []RemovedField
+}
+
+func (s *RemovedFieldStorageStrategy) Build(builder StorageBuilder, store *StorageWrapper, options *generic.StoreOptions) {
+	s.StorageBuilder.Build(builder, store, options)
+	decorator := store.Decorator
+	store.Decorator = func(obj runtime.Object) error {
+		if err := ClearRemovedFields(obj, s.Fields...); err != nil {
+			return err
+		}
+		if decorator != nil {
+			return decorator(obj)
+		}
+		return nil
+	}
+}
+
+func (s *RemovedFieldStorageStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	ClearRemovedFields(obj, s.Fields...)
+	s.StorageBuilder.PrepareForCreate(ctx, obj)
+}
+
+func (s *RemovedFieldStorageStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	// The fields are cleared from the stored object too, so clearing them does not count as a change
+	ClearRemovedFields(obj, s.Fields...)
+	ClearRemovedFields(old, s.Fields...)
+	s.StorageBuilder.PrepareForUpdate(ctx, obj, old)
+}
+
+// ClearRemovedFields sets the fields of obj, or of each item of obj if it is a list, to their zero value
+func ClearRemovedFields(obj runtime.Object, fields ...RemovedField) error {
+	if meta.IsListType(obj) {
+		return meta.EachListItem(obj, func(item runtime.Object) error {
+			return ClearRemovedFields(item, fields...)
+		})
+	}
+	for _, f := range fields {
+		if field := f.Field(obj); field != nil {
+			v := reflect.ValueOf(field).Elem()
+			v.Set(reflect.Zero(v.Type()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+// ValidatingAdmissionFunc validates the unversioned object of a create or update of a resource
+type ValidatingAdmissionFunc func(ctx context.Context, obj runtime.Object, a admission.Attributes) error
+
+var _ admission.ValidationInterface = &ResourceValidatingAdmission{}
+
+// NewValidatingAdmissionPlugin returns an admission plugin calling validate on creates and updates of
+// the resource.  Generated for resources with "+admission:validating=<func>" comments.
+func NewValidatingAdmissionPlugin(resource schema.GroupResource, validate ValidatingAdmissionFunc) admission.Interface {
+	return &ResourceValidatingAdmission{
+		Handler:  admission.NewHandler(admission.Create, admission.Update),
+		Resource: resource,
+		Func:     validate,
+	}
+}
+
+// ResourceValidatingAdmission is a validating admission plugin scoped to a single resource
+type ResourceValidatingAdmission struct {
+	*admission.Handler
+	Resource schema.GroupResource
+	Func     ValidatingAdmissionFunc
+}
+
+// Validate calls Func with the object if the request is for the Resource, and rejects the
+// request with the returned error
+func (p *ResourceValidatingAdmission) Validate(
+	ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != p.Resource || len(a.GetSubresource()) > 0 {
+		return nil
+	}
+	if err := p.Func(ctx, a.GetObject(), a); err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	return nil
+}
@@ -83,3 +83,9 @@ type SchemeFns interface {
 type StandardStorageProvider interface {
 	GetStandardStorage() rest.StandardStorage
 }
+
+// StorageProvider provides the storage of a resource, including the resources not backed by a standard storage
+// such as subresources
+type StorageProvider interface {
+	GetStorage() rest.Storage
+}
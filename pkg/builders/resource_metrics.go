@@ -0,0 +1,100 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog"
+)
+
+// MetricsLabel is a label of the gauge of the objects of a resource, whose value is read from each object
+type MetricsLabel struct {
+	// Name is the name of the label - e.g. tier
+	Name string
+	// Value returns the value of the label for an unversioned object of the resource
+	Value func(obj runtime.Object) string
+}
+
+// NewResourceMetricsCollector returns a collector of the gauge fqName counting the objects of the resource
+// stored by sp by namespace and by the values of the labels.  The objects are listed from the storage on each
+// collection, nothing is collected until the storage is built.
+func NewResourceMetricsCollector(fqName, help string, sp StandardStorageProvider, labels ...MetricsLabel) metrics.StableCollector {
+	names := []string{"namespace"}
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	return &resourceMetricsCollector{
+		fqName:  fqName,
+		desc:    metrics.NewDesc(fqName, help, names, nil, metrics.ALPHA, ""),
+		storage: sp,
+		labels:  labels,
+	}
+}
+
+type resourceMetricsCollector struct {
+	metrics.BaseStableCollector
+
+	fqName  string
+	desc    *metrics.Desc
+	storage StandardStorageProvider
+	labels  []MetricsLabel
+}
+
+func (c *resourceMetricsCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- c.desc
+}
+
+func (c *resourceMetricsCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	st := c.storage.GetStandardStorage()
+	if st == nil {
+		return
+	}
+	list, err := st.List(request.WithNamespace(context.Background(), metav1.NamespaceAll), &internalversion.ListOptions{})
+	if err != nil {
+		klog.Errorf("failed to list the objects of %s: %v", c.fqName, err)
+		return
+	}
+	// The label values are joined by "\x00" into the keys of the counts
+	counts := map[string]float64{}
+	err = meta.EachListItem(list, func(obj runtime.Object) error {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		values := []string{accessor.GetNamespace()}
+		for _, label := range c.labels {
+			values = append(values, label.Value(obj))
+		}
+		counts[strings.Join(values, "\x00")]++
+		return nil
+	})
+	if err != nil {
+		klog.Errorf("failed to count the objects of %s: %v", c.fqName, err)
+		return
+	}
+	for key, count := range counts {
+		ch <- metrics.NewLazyConstMetric(c.desc, metrics.GaugeValue, count, strings.Split(key, "\x00")...)
+	}
+}
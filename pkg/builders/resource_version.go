@@ -0,0 +1,37 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"k8s.io/apiserver/pkg/storage/etcd3"
+)
+
+var resourceVersioner = etcd3.APIObjectVersioner{}
+
+// IsNewerResourceVersion returns true if the resource version a is newer than b.  An empty
+// resource version is older than any other resource version.
+func IsNewerResourceVersion(a, b string) (bool, error) {
+	av, err := resourceVersioner.ParseResourceVersion(a)
+	if err != nil {
+		return false, err
+	}
+	bv, err := resourceVersioner.ParseResourceVersion(b)
+	if err != nil {
+		return false, err
+	}
+	return av > bv, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// ResourceVersionMatch determines how the ResourceVersion of a list request is applied.  It mirrors
+// metav1.ResourceVersionMatch from newer apimachinery releases.
+type ResourceVersionMatch string
+
+const (
+	// ResourceVersionMatchNotOlderThan lists data that is at least as new as the provided
+	// ResourceVersion.  An empty match is treated the same way.
+	ResourceVersionMatchNotOlderThan ResourceVersionMatch = "NotOlderThan"
+	// ResourceVersionMatchExact lists data at exactly the provided ResourceVersion.
+	ResourceVersionMatchExact ResourceVersionMatch = "Exact"
+)
+
+// ResourceVersionMatchParam is the query parameter of the ResourceVersionMatch of the list requests, which the
+// list options of this apimachinery release do not decode
+const ResourceVersionMatchParam = "resourceVersionMatch"
+
+type resourceVersionMatchKey int
+
+// WithResourceVersionMatch passes the ResourceVersionMatchParam of the requests to the storage of the resources
+// through the context of the requests
+func WithResourceVersionMatch(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if match := req.URL.Query().Get(ResourceVersionMatchParam); len(match) > 0 {
+			req = req.WithContext(ContextWithResourceVersionMatch(req.Context(), ResourceVersionMatch(match)))
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// ContextWithResourceVersionMatch returns a copy of ctx listing with match
+func ContextWithResourceVersionMatch(ctx context.Context, match ResourceVersionMatch) context.Context {
+	return request.WithValue(ctx, resourceVersionMatchKey(0), match)
+}
+
+// ResourceVersionMatchFrom returns the ResourceVersionMatch of the lists of ctx, empty if none
+func ResourceVersionMatchFrom(ctx context.Context) ResourceVersionMatch {
+	match, _ := ctx.Value(resourceVersionMatchKey(0)).(ResourceVersionMatch)
+	return match
+}
+
+// ApplyResourceVersionMatch updates the list options so the generic storage serves the list with the
+// requested match semantics.
+//
+// The storage reads from etcd at exactly the provided ResourceVersion when a limit is set, and serves
+// from the watch cache, waiting until it is at least as new as the provided ResourceVersion, otherwise.
+func ApplyResourceVersionMatch(options *internalversion.ListOptions, match ResourceVersionMatch) error {
+	switch match {
+	case "":
+		return nil
+	case ResourceVersionMatchNotOlderThan:
+		if len(options.ResourceVersion) == 0 {
+			return errors.NewBadRequest("resourceVersionMatch=NotOlderThan requires a resourceVersion")
+		}
+		if options.Limit > 0 && options.ResourceVersion != "0" {
+			// A limited list would be read at exactly the ResourceVersion, read the latest data
+			// instead which is never older than the requested version.
+			options.ResourceVersion = ""
+		}
+		return nil
+	case ResourceVersionMatchExact:
+		if len(options.ResourceVersion) == 0 || options.ResourceVersion == "0" {
+			return errors.NewBadRequest("resourceVersionMatch=Exact requires a non-zero resourceVersion")
+		}
+		if len(options.Continue) > 0 {
+			return errors.NewBadRequest("resourceVersionMatch is not allowed when using continue")
+		}
+		if options.Limit == 0 {
+			// Only limited lists are read at an exact revision
+			options.Limit = math.MaxInt64
+		}
+		return nil
+	default:
+		return errors.NewBadRequest(fmt.Sprintf("unsupported resourceVersionMatch %q", match))
+	}
+}
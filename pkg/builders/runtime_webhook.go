@@ -0,0 +1,85 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// ValidateRuntimeWebhookCreate validates the versioned obj with strategy as the storage of the apiserver validates
+// created objects, converting it to the internal object returned by newInternal.  Generated as the
+// ValidateCreate method of the resources with the "+webhook:runtime" comment.
+func ValidateRuntimeWebhookCreate(strategy StorageBuilder, obj runtime.Object, newInternal func() runtime.Object) error {
+	internal := newInternal()
+	if err := Scheme.Convert(obj, internal, nil); err != nil {
+		return err
+	}
+	ctx, err := runtimeWebhookContext(obj)
+	if err != nil {
+		return err
+	}
+	return runtimeWebhookError(obj, strategy.Validate(ctx, internal))
+}
+
+// ValidateRuntimeWebhookUpdate validates the versioned obj updating old with strategy as the storage of the
+// apiserver validates updated objects, converting both to the internal objects returned by newInternal.
+// Generated as the ValidateUpdate method of the resources with the "+webhook:runtime" comment.
+func ValidateRuntimeWebhookUpdate(strategy StorageBuilder, obj, old runtime.Object, newInternal func() runtime.Object) error {
+	internal, oldInternal := newInternal(), newInternal()
+	if err := Scheme.Convert(obj, internal, nil); err != nil {
+		return err
+	}
+	if err := Scheme.Convert(old, oldInternal, nil); err != nil {
+		return err
+	}
+	ctx, err := runtimeWebhookContext(obj)
+	if err != nil {
+		return err
+	}
+	return runtimeWebhookError(obj, strategy.ValidateUpdate(ctx, internal, oldInternal))
+}
+
+// runtimeWebhookContext returns the context of a request for the namespace of obj
+func runtimeWebhookContext(obj runtime.Object) (context.Context, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	return request.WithNamespace(context.TODO(), accessor.GetNamespace()), nil
+}
+
+// runtimeWebhookError returns the Invalid error of obj for errs, nil if errs is empty
+func runtimeWebhookError(obj runtime.Object, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	gvks, _, err := Scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	return errors.NewInvalid(gvks[0].GroupKind(), accessor.GetName(), errs)
+}
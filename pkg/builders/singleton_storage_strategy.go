@@ -0,0 +1,62 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ StorageBuilder = &SingletonStorageStrategy{}
+
+// NewSingletonStorageStrategy wraps a StorageBuilder so the resource may only have a single
+// instance with the given name.  Generated for resources with the "+singleton=<name>" comment.
+func NewSingletonStorageStrategy(name string, strategy StorageBuilder) StorageBuilder {
+	return &SingletonStorageStrategy{strategy, name}
+}
+
+// SingletonStorageStrategy defaults the name of created objects to Name and rejects objects
+// with any other name
+type SingletonStorageStrategy struct {
+	StorageBuilder
+	Name string
+}
+
+func (s *SingletonStorageStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	if accessor, err := meta.Accessor(obj); err == nil && len(accessor.GetName()) == 0 {
+		accessor.SetName(s.Name)
+		accessor.SetGenerateName("")
+	}
+	s.StorageBuilder.PrepareForCreate(ctx, obj)
+}
+
+func (s *SingletonStorageStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	errors := s.StorageBuilder.Validate(ctx, obj)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return append(errors, field.InternalError(field.NewPath("metadata"), err))
+	}
+	if accessor.GetName() != s.Name {
+		errors = append(errors, field.Invalid(field.NewPath("metadata", "name"), accessor.GetName(),
+			fmt.Sprintf("must be %q, only a single instance of this resource is allowed", s.Name)))
+	}
+	return errors
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+var _ StorageBuilder = &StampUserStorageStrategy{}
+
+// NewStampUserStorageStrategy wraps a StorageBuilder so the objects are annotated with the users creating
+// and last updating them, under the <group>/created-by and <group>/updated-by keys.  Generated for resources
+// with the "+resource:stampUser" comment.
+func NewStampUserStorageStrategy(group string, strategy StorageBuilder) StorageBuilder {
+	return &StampUserStorageStrategy{strategy, group + "/created-by", group + "/updated-by"}
+}
+
+// StampUserStorageStrategy sets the annotations of the users creating and updating the objects from the
+// user of the request.  The values of the annotations set by the requests are ignored, so the annotations
+// cannot be forged.
+type StampUserStorageStrategy struct {
+	StorageBuilder
+	CreatedByAnnotation string
+	UpdatedByAnnotation string
+}
+
+// PrepareForCreate annotates obj with the user of the request as its creator
+func (s *StampUserStorageStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	s.StorageBuilder.PrepareForCreate(ctx, obj)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	annotations := accessor.GetAnnotations()
+	setUserAnnotation(&annotations, s.CreatedByAnnotation, userName(ctx))
+	setUserAnnotation(&annotations, s.UpdatedByAnnotation, "")
+	accessor.SetAnnotations(annotations)
+}
+
+// PrepareForUpdate annotates obj with the user of the request as its last updater, its creator is kept from old
+func (s *StampUserStorageStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	s.StorageBuilder.PrepareForUpdate(ctx, obj, old)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	oldAccessor, err := meta.Accessor(old)
+	if err != nil {
+		return
+	}
+	annotations := accessor.GetAnnotations()
+	setUserAnnotation(&annotations, s.CreatedByAnnotation, oldAccessor.GetAnnotations()[s.CreatedByAnnotation])
+	setUserAnnotation(&annotations, s.UpdatedByAnnotation, userName(ctx))
+	accessor.SetAnnotations(annotations)
+}
+
+// userName returns the name of the user of the request of ctx, empty if the request has no user
+func userName(ctx context.Context) string {
+	if user, ok := request.UserFrom(ctx); ok {
+		return user.GetName()
+	}
+	return ""
+}
+
+// setUserAnnotation sets the annotation key to user, removing it if user is empty
+func setUserAnnotation(annotations *map[string]string, key, user string) {
+	if len(user) == 0 {
+		delete(*annotations, key)
+		return
+	}
+	if *annotations == nil {
+		*annotations = map[string]string{}
+	}
+	(*annotations)[key] = user
+}
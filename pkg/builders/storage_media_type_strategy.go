@@ -0,0 +1,88 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+	serverstorage "k8s.io/apiserver/pkg/server/storage"
+)
+
+var _ StorageBuilder = &StorageMediaTypeStorageStrategy{}
+
+// NewStorageMediaTypeStorageStrategy wraps a StorageBuilder so the resource is stored in etcd using
+// the given media type rather than the --storage-media-type of the server.  Generated for resources
+// with the "+resource:storageMediaType=<media type>" comment, and for the status subresource of resources
+// with the "+subresource:status:storageMediaType=<media type>" comment.
+func NewStorageMediaTypeStorageStrategy(mediaType string, strategy StorageBuilder) StorageBuilder {
+	return &StorageMediaTypeStorageStrategy{strategy, mediaType}
+}
+
+// StorageMediaTypeStorageStrategy overrides the storage codec of the resource to encode MediaType
+type StorageMediaTypeStorageStrategy struct {
+	StorageBuilder
+	MediaType string
+}
+
+func (s *StorageMediaTypeStorageStrategy) Build(builder StorageBuilder, store *StorageWrapper, options *generic.StoreOptions) {
+	s.StorageBuilder.Build(builder, store, options)
+	options.RESTOptions = &storageMediaTypeRESTOptionsGetter{options.RESTOptions, s.MediaType}
+}
+
+// storageMediaTypeRESTOptionsGetter replaces the codec of the storage config with one encoding mediaType.
+// Objects stored using other media types can still be decoded.
+type storageMediaTypeRESTOptionsGetter struct {
+	generic.RESTOptionsGetter
+	mediaType string
+}
+
+func (g *storageMediaTypeRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	options, err := g.RESTOptionsGetter.GetRESTOptions(resource)
+	if err != nil {
+		return options, err
+	}
+	// Store the preferred version, the same as the default resource encoding config of the server
+	var storageVersion *schema.GroupVersion
+	for _, v := range Scheme.PrioritizedVersionsForGroup(resource.Group) {
+		if v.Version != runtime.APIVersionInternal {
+			storageVersion = &v
+			break
+		}
+	}
+	if storageVersion == nil {
+		return options, fmt.Errorf("no versions registered for group %q", resource.Group)
+	}
+
+	config := *options.StorageConfig
+	codec, encodeVersioner, err := serverstorage.NewStorageCodec(serverstorage.StorageCodecConfig{
+		StorageMediaType:  g.mediaType,
+		StorageSerializer: Codecs,
+		StorageVersion:    *storageVersion,
+		MemoryVersion:     schema.GroupVersion{Group: resource.Group, Version: runtime.APIVersionInternal},
+		Config:            config,
+	})
+	if err != nil {
+		return options, fmt.Errorf("unable to create storage codec for %v: %v", resource, err)
+	}
+	config.Codec = codec
+	config.EncodeVersioner = encodeVersioner
+	options.StorageConfig = &config
+	return options, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/generic/registry"
+)
+
+var _ StorageBuilder = &TenantKeyPrefixStorageStrategy{}
+
+// TenantFunc returns the tenant of the namespace of the request context, e.g. read from a label of the
+// namespace.  It is also called with contexts holding only the namespace of a stored object, so the tenant
+// must be derived from the namespace alone.  Tenants may not be empty nor contain "/".
+type TenantFunc func(ctx context.Context) (string, error)
+
+// NewTenantKeyPrefixStorageStrategy wraps the StorageBuilder of a namespaced resource so its objects are
+// stored in etcd under <resource prefix>/<tenant>/<namespace>/<name>, isolating the objects of the tenants
+// returned by tenant.  Lists across all namespaces span the objects of all the tenants.
+func NewTenantKeyPrefixStorageStrategy(tenant TenantFunc, strategy StorageBuilder) StorageBuilder {
+	return &TenantKeyPrefixStorageStrategy{strategy, tenant}
+}
+
+// TenantKeyPrefixStorageStrategy prefixes the etcd keys of the objects of the resource by their Tenant
+type TenantKeyPrefixStorageStrategy struct {
+	StorageBuilder
+	Tenant TenantFunc
+}
+
+func (s *TenantKeyPrefixStorageStrategy) Build(builder StorageBuilder, store *StorageWrapper, options *generic.StoreOptions) {
+	s.StorageBuilder.Build(builder, store, options)
+	if !builder.NamespaceScoped() {
+		panic(fmt.Errorf("cannot prefix the keys of the non-namespaced resource %s by tenant",
+			store.DefaultQualifiedResource))
+	}
+
+	getter := &resourcePrefixRESTOptionsGetter{RESTOptionsGetter: options.RESTOptions}
+	options.RESTOptions = getter
+	store.KeyRootFunc = func(ctx context.Context) string {
+		namespace, ok := genericapirequest.NamespaceFrom(ctx)
+		if !ok || len(namespace) == 0 {
+			return getter.prefix
+		}
+		tenant, err := s.tenant(ctx, store.DefaultQualifiedResource)
+		if err != nil {
+			// Match no objects rather than those of the other tenants
+			return path.Join(getter.prefix, namespace)
+		}
+		return path.Join(getter.prefix, tenant, namespace)
+	}
+	store.KeyFunc = func(ctx context.Context, name string) (string, error) {
+		tenant, err := s.tenant(ctx, store.DefaultQualifiedResource)
+		if err != nil {
+			return "", err
+		}
+		return registry.NamespaceKeyFunc(ctx, path.Join(getter.prefix, tenant), name)
+	}
+}
+
+// tenant returns the validated tenant of the namespace of ctx
+func (s *TenantKeyPrefixStorageStrategy) tenant(ctx context.Context, resource schema.GroupResource) (string, error) {
+	tenant, err := s.Tenant(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(tenant) == 0 || strings.Contains(tenant, "/") {
+		namespace, _ := genericapirequest.NamespaceFrom(ctx)
+		return "", errors.NewInternalError(fmt.Errorf("invalid tenant %q of namespace %q for %s",
+			tenant, namespace, resource))
+	}
+	return tenant, nil
+}
+
+// resourcePrefixRESTOptionsGetter records the resource prefix of the store, which is the root of the keys
+// of the objects of the resource
+type resourcePrefixRESTOptionsGetter struct {
+	generic.RESTOptionsGetter
+	prefix string
+}
+
+func (g *resourcePrefixRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	options, err := g.RESTOptionsGetter.GetRESTOptions(resource)
+	if err != nil {
+		return options, err
+	}
+	g.prefix = "/" + strings.TrimPrefix(options.ResourcePrefix, "/")
+	return options, nil
+}
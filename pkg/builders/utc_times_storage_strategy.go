@@ -0,0 +1,49 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ StorageBuilder = &UTCTimesStorageStrategy{}
+
+// NewUTCTimesStorageStrategy wraps a StorageBuilder so the times of the created and updated objects are
+// converted to UTC by normalizeTimes, and are stored in the same zone whatever the zone of the requests.
+// Generated for resources with the "+resource:utcTimes" comment.
+func NewUTCTimesStorageStrategy(strategy StorageBuilder, normalizeTimes func(obj runtime.Object)) StorageBuilder {
+	return &UTCTimesStorageStrategy{strategy, normalizeTimes}
+}
+
+// UTCTimesStorageStrategy calls NormalizeTimes once the wrapped StorageBuilder prepared the created and
+// updated objects
+type UTCTimesStorageStrategy struct {
+	StorageBuilder
+	NormalizeTimes func(obj runtime.Object)
+}
+
+func (s *UTCTimesStorageStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	s.StorageBuilder.PrepareForCreate(ctx, obj)
+	s.NormalizeTimes(obj)
+}
+
+func (s *UTCTimesStorageStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	s.StorageBuilder.PrepareForUpdate(ctx, obj, old)
+	s.NormalizeTimes(obj)
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// UnexpectedWatchObjectStatus returns the status of the Error event replacing an event whose object is not of
+// the expected type, e.g. "*v1.Foo".  Used by the generated Watch<Kind>Events functions.
+func UnexpectedWatchObjectStatus(event watch.Event, expected string) *metav1.Status {
+	status := apierrors.NewInternalError(
+		fmt.Errorf("expected %s in the %s watch event, got %T", expected, event.Type, event.Object)).Status()
+	return &status
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builders
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WebhookCertificate is the serving certificate of a webhook read from the tls.crt and tls.key of a
+// kubernetes.io/tls Secret.  The certificate is reloaded each time the Secret is updated, so a rotated
+// certificate is served without restarting the webhook.
+type WebhookCertificate struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+
+	lock sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewWebhookCertificate returns the WebhookCertificate of the Secret name in namespace, read with client once
+// Run is called.  Set GetCertificate as the tls.Config.GetCertificate of the server of the webhook.
+func NewWebhookCertificate(client kubernetes.Interface, namespace, name string) *WebhookCertificate {
+	return &WebhookCertificate{client: client, namespace: namespace, name: name}
+}
+
+// Run watches the Secret, loading its certificate on each update until stopCh is closed.  The last valid
+// certificate keeps being served while the Secret holds an invalid one.
+func (c *WebhookCertificate) Run(stopCh <-chan struct{}) {
+	selector := fields.OneTermEqualSelector("metadata.name", c.name).String()
+	secrets := c.client.CoreV1().Secrets(c.namespace)
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return secrets.List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return secrets.Watch(context.TODO(), options)
+		},
+	}
+	_, informer := cache.NewInformer(lw, &corev1.Secret{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.load(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.load(newObj)
+		},
+	})
+	informer.Run(stopCh)
+}
+
+func (c *WebhookCertificate) load(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.Name != c.name {
+		return
+	}
+	cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to load the webhook certificate of secret %s/%s: %v",
+			c.namespace, c.name, err))
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.cert = &cert
+}
+
+// GetCertificate returns the certificate last loaded from the Secret, it implements
+// tls.Config.GetCertificate
+func (c *WebhookCertificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.cert == nil {
+		return nil, fmt.Errorf("no webhook certificate loaded from secret %s/%s", c.namespace, c.name)
+	}
+	return c.cert, nil
+}
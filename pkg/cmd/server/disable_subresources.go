@@ -0,0 +1,58 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+)
+
+// DisableSubresources skips registering the subresources of apis at the "<resource>/<subresource>" paths,
+// e.g. "foos/status", in the groups serving them.  Each path must be the path of a subresource of one of the
+// groups.
+func DisableSubresources(apis []*builders.APIGroupBuilder, paths []string) error {
+	for _, p := range paths {
+		if parts := strings.Split(p, "/"); len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return fmt.Errorf("--disable-subresource expects <resource>/<subresource>, got %q", p)
+		}
+		found := false
+		for _, g := range apis {
+			if hasSubresource(g, p) {
+				g.DisabledSubresources = append(g.DisabledSubresources, p)
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("--disable-subresource %s is not a subresource of the apiserver", p)
+		}
+	}
+	return nil
+}
+
+// hasSubresource returns true if a version of g serves the subresource at the "<resource>/<subresource>" path
+func hasSubresource(g *builders.APIGroupBuilder, path string) bool {
+	for _, v := range g.Versions {
+		for _, k := range v.Kinds {
+			if len(k.Unversioned.GetPath()) > 0 && k.Unversioned.GetName()+"/"+k.Unversioned.GetPath() == path {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+)
+
+// ApplyFieldProjection trims the GET responses of the apiserver of config to the fields of the "fields" query
+// parameter of the requests if enabled.
+func ApplyFieldProjection(config *genericapiserver.Config, enabled bool) error {
+	if !enabled {
+		return nil
+	}
+	buildHandlerChain := config.BuildHandlerChainFunc
+	config.BuildHandlerChainFunc = func(handler http.Handler, c *genericapiserver.Config) http.Handler {
+		// Wrapped by the generic filters, the requests are authenticated and authorized before being trimmed
+		return buildHandlerChain(builders.WithFieldProjection(handler), c)
+	}
+	return nil
+}
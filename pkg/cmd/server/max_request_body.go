@@ -0,0 +1,60 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"sigs.k8s.io/apiserver-builder-alpha/pkg/builders"
+)
+
+// ApplyMaxRequestBodyBytes limits the size of the request bodies served by the apiserver of config to limit
+// bytes, a limit of 0 or less keeping the default limit of the generic apiserver.
+func ApplyMaxRequestBodyBytes(config *genericapiserver.Config, limit int64) error {
+	if limit <= 0 {
+		return nil
+	}
+	// The decoders of the request bodies read up to MaxRequestBodyBytes, the handler rejects larger bodies
+	// before they are read
+	config.MaxRequestBodyBytes = limit
+	buildHandlerChain := config.BuildHandlerChainFunc
+	config.BuildHandlerChainFunc = func(handler http.Handler, c *genericapiserver.Config) http.Handler {
+		return WithMaxRequestBodyBytes(buildHandlerChain(handler, c), limit)
+	}
+	return nil
+}
+
+// WithMaxRequestBodyBytes rejects the requests whose body is larger than limit bytes with a 413 status
+func WithMaxRequestBodyBytes(handler http.Handler, limit int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.ContentLength > limit {
+			err := apierrors.NewRequestEntityTooLargeError(fmt.Sprintf("limit is %d", limit))
+			responsewriters.ErrorNegotiated(err, builders.Codecs, schema.GroupVersion{}, w, req)
+			return
+		}
+		if req.Body != nil {
+			// Bodies of unknown length fail to read past the limit
+			req.Body = http.MaxBytesReader(w, req.Body, limit)
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
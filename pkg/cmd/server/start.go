@@ -18,11 +18,15 @@ package server
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -62,6 +66,20 @@ type ServerOptions struct {
 	RunDelegatedAuth bool
 	BearerToken      string
 	PostStartHooks   []PostStartHook
+
+	// ConversionWebhookURL is the url of the builders.ConversionWebhook converting the fields of the
+	// "+conversion:webhookFallback" resources that the generated conversions do not map
+	ConversionWebhookURL string
+	// ConversionWebhookCAFile is the PEM bundle of the certificate authorities of the conversion webhook
+	ConversionWebhookCAFile string
+	// ConversionWebhookTimeout bounds the requests to the conversion webhook
+	ConversionWebhookTimeout time.Duration
+	// MaxRequestBodyBytes is the size limit of the request bodies, 0 keeping the generic apiserver default
+	MaxRequestBodyBytes int64
+	// DisabledSubresources are the "<resource>/<subresource>" paths of the subresources that are not served
+	DisabledSubresources []string
+	// FieldProjection trims the GET responses to the fields of the "fields" query parameter of the requests
+	FieldProjection bool
 }
 
 type PostStartHook struct {
@@ -135,8 +153,9 @@ func NewServerOptions(etcdPath, title, version string, b []*builders.APIGroupBui
 			builders.Codecs.LegacyCodec(versions...),
 			genericoptions.NewProcessInfo(title, version),
 		),
-		APIBuilders:      b,
-		RunDelegatedAuth: true,
+		APIBuilders:              b,
+		RunDelegatedAuth:         true,
+		ConversionWebhookTimeout: builders.DefaultConversionWebhookTimeout,
 	}
 	o.RecommendedOptions.SecureServing.BindPort = 443
 
@@ -155,6 +174,12 @@ func NewCommandStartServer(etcdPath string, out, errOut io.Writer, builders []*b
 	stopCh <-chan struct{}, title, version string, tweakConfigFuncs ...func(apiServer *apiserver.Config) error) (*cobra.Command, *ServerOptions) {
 	o := NewServerOptions(etcdPath, title, version, builders)
 
+	// Resource-scoped plugins generated from "+admission" comments are registered with the aggregated plugins
+	for _, b := range builders {
+		for pluginName, plugin := range b.AdmissionPlugins {
+			AggregatedAdmissionPlugins[pluginName] = plugin
+		}
+	}
 	for pluginName := range AggregatedAdmissionPlugins {
 		o.RecommendedOptions.Admission.RecommendedPluginOrder = append(o.RecommendedOptions.Admission.RecommendedPluginOrder, pluginName)
 	}
@@ -196,8 +221,25 @@ func NewCommandStartServer(etcdPath string, out, errOut io.Writer, builders []*b
 		"Print the openapi json and exit")
 	flags.BoolVar(&o.RunDelegatedAuth, "delegated-auth", true,
 		"Setup delegated auth")
+	flags.StringVar(&o.ConversionWebhookURL, "conversion-webhook-url", "",
+		"url of the webhook converting the fields of the +conversion:webhookFallback resources the generated conversions do not map")
+	flags.StringVar(&o.ConversionWebhookCAFile, "conversion-webhook-ca-file", "",
+		"PEM bundle of the certificate authorities of the --conversion-webhook-url, defaults to the system roots")
+	flags.DurationVar(&o.ConversionWebhookTimeout, "conversion-webhook-timeout", o.ConversionWebhookTimeout,
+		"timeout of the requests to the --conversion-webhook-url")
+	flags.Int64Var(&o.MaxRequestBodyBytes, "max-request-body-bytes", 0,
+		"limit of the size of the request bodies, larger requests are rejected, defaults to the 3MB of the generic apiserver")
+	flags.StringSliceVar(&o.DisabledSubresources, "disable-subresource", nil,
+		"<resource>/<subresource> path of a subresource to not serve, e.g. foos/status, may be repeated")
+	flags.BoolVar(&o.FieldProjection, "field-projection", false,
+		"trim the objects of the GET responses to the comma separated field paths of the fields query parameter, e.g. ?fields=metadata.name,status.phase")
 	o.RecommendedOptions.AddFlags(flags)
 	o.InsecureServingOptions.AddFlags(flags)
+	for _, b := range builders {
+		if b.ResourceOptions != nil {
+			b.ResourceOptions.AddFlags(flags)
+		}
+	}
 
 	feature.DefaultMutableFeatureGate.AddFlag(flags)
 
@@ -208,10 +250,32 @@ func NewCommandStartServer(etcdPath string, out, errOut io.Writer, builders []*b
 }
 
 func (o ServerOptions) Validate(args []string) error {
+	if o.MaxRequestBodyBytes < 0 {
+		return fmt.Errorf("--max-request-body-bytes can not be negative, got %d", o.MaxRequestBodyBytes)
+	}
+	if o.ConversionWebhookTimeout <= 0 {
+		return fmt.Errorf("--conversion-webhook-timeout must be positive, got %v", o.ConversionWebhookTimeout)
+	}
 	return nil
 }
 
 func (o *ServerOptions) Complete() error {
+	if len(o.ConversionWebhookURL) == 0 {
+		return nil
+	}
+	client := &http.Client{Timeout: o.ConversionWebhookTimeout}
+	if len(o.ConversionWebhookCAFile) > 0 {
+		ca, err := ioutil.ReadFile(o.ConversionWebhookCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read --conversion-webhook-ca-file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return fmt.Errorf("no certificates found in --conversion-webhook-ca-file %s", o.ConversionWebhookCAFile)
+		}
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	}
+	builders.ConversionWebhook = builders.NewConversionWebhookClient(o.ConversionWebhookURL, client)
 	return nil
 }
 
@@ -273,6 +337,13 @@ func (o ServerOptions) Config(tweakConfigFuncs ...func(config *apiserver.Config)
 			)
 		},
 		o.RecommendedOptions.Features.ApplyTo,
+		func(cfg *genericapiserver.Config) error {
+			return ApplyMaxRequestBodyBytes(cfg, o.MaxRequestBodyBytes)
+		},
+		func(cfg *genericapiserver.Config) error {
+			return ApplyFieldProjection(cfg, o.FieldProjection)
+		},
+		ApplyResourceVersionMatch,
 	)
 	if err != nil {
 		return nil, err
@@ -334,6 +405,11 @@ func (o ServerOptions) Config(tweakConfigFuncs ...func(config *apiserver.Config)
 					storage.NewResourceConfig(),
 					make(map[schema.GroupResource]string),
 				)
+				for _, b := range o.APIBuilders {
+					for groupResource, mediaType := range b.StorageMediaTypes {
+						storageFactory.SetSerializer(groupResource, mediaType, builders.Codecs)
+					}
+				}
 				return o.RecommendedOptions.Etcd.ApplyWithStorageFactoryTo(storageFactory, cfg)
 			},
 		)
@@ -396,13 +472,18 @@ func (o *ServerOptions) RunServer(stopCh <-chan struct{}, title, version string,
 	}
 	o.BearerToken = genericConfig.LoopbackClientConfig.BearerToken
 
+	if err := DisableSubresources(o.APIBuilders, o.DisabledSubresources); err != nil {
+		return err
+	}
 	for _, provider := range o.APIBuilders {
 		aggregatedAPIServerConfig.AddApi(provider)
 	}
 
 	aggregatedAPIServerConfig.Init()
 
-	genericConfig.OpenAPIConfig = genericapiserver.DefaultOpenAPIConfig(GetOpenApiDefinition, openapinamer.NewDefinitionNamer(builders.Scheme))
+	genericConfig.OpenAPIConfig = genericapiserver.DefaultOpenAPIConfig(
+		builders.AddOpenAPIUIDescriptors(builders.AddOpenAPIExamples(GetOpenApiDefinition, o.APIBuilders), o.APIBuilders),
+		openapinamer.NewDefinitionNamer(builders.Scheme))
 	genericConfig.OpenAPIConfig.Info.Title = title
 	genericConfig.OpenAPIConfig.Info.Version = version
 
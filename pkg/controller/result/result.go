@@ -0,0 +1,41 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package result contains the results returned by the Reconcile functions of the scaffolded controllers
+package result
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Done returns the result of a reconcile that succeeded and does not need to be requeued
+func Done() (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+// RequeueAfter returns the result of a reconcile that succeeded and is requeued after d, e.g. to poll
+// the state of an object not watched by the controller
+func RequeueAfter(d time.Duration) (reconcile.Result, error) {
+	return reconcile.Result{RequeueAfter: d}, nil
+}
+
+// Error returns the result of a reconcile that failed with err.  The request is requeued with the rate
+// limited backoff of the controller.
+func Error(err error) (reconcile.Result, error) {
+	return reconcile.Result{}, err
+}
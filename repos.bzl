@@ -10,6 +10,14 @@ def go_repositories():
         version = "v0.0.0-20170929234023-d6e3b3328b78",
     )
 
+    go_repository(
+        name = "com_github_antlr_antlr4",
+        build_file_generation = "on",
+        build_file_proto_mode = "disable",
+        importpath = "github.com/antlr/antlr4",
+        sum = "h1:StuiJFxQUsxSCzcby6NFZRdEhPkXD5vxN7TZ4MD6T84=",
+        version = "v0.0.0-20190819145818-b43a4c3a8015",
+    )
     go_repository(
         name = "com_github_beorn7_perks",
         build_file_generation = "on",
@@ -229,6 +237,14 @@ def go_repositories():
         sum = "h1:0udJVsspx3VBr5FwtLhQQtuAsVc79tTq0ocGIPAU6qo=",
         version = "v1.0.0",
     )
+    go_repository(
+        name = "com_github_google_cel_go",
+        build_file_generation = "on",
+        build_file_proto_mode = "disable",
+        importpath = "github.com/google/cel-go",
+        sum = "h1:2kqc5arTucvtLJzXVUbmiUh7n2xjizwZijPrpEsagAE=",
+        version = "v0.4.1",
+    )
     go_repository(
         name = "com_github_google_go_cmp",
         build_file_generation = "on",